@@ -0,0 +1,524 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Combat is what the combat block tracks during play. Maximum HP, AC,
+// initiative and speed are worked out from the rest of the character
+type Combat struct {
+	Damage       int         `json:"damage"` // hit points lost; current HP is the maximum less this
+	TempHP       int         `json:"temp_hp"`
+	HitDiceSpent map[int]int `json:"hit_dice_spent,omitempty"` // by die size: 8 for d8
+	DeathSaves   DeathSaves  `json:"death_saves"`
+	Dead         bool        `json:"dead,omitempty"` // until revived, as by a spell
+}
+
+// DeathSaves counts the death saving throws made while at 0 hit points
+type DeathSaves struct {
+	Successes int `json:"successes"`
+	Failures  int `json:"failures"`
+}
+
+var (
+	hitDicePattern = regexp.MustCompile(`^(\d+)d(\d+)(?:\+(\d+))?`) // "9d8+2*"
+	// "You gain a +1 bonus to AC and saving throws while wearing this ring"
+	itemACBonusPattern = regexp.MustCompile(`\+(\d) bonus to AC(?: and saving throws)? while`)
+	// Bracers of Defense only help without armor or a shield
+	itemUnarmoredACBonusPattern = regexp.MustCompile(`\+(\d) bonus to AC if you are wearing no armor`)
+	unarmoredDefensePattern     = regexp.MustCompile(`10 \+ your Dexterity modifier \+ your (\w+) modifier`)
+)
+
+// hitDie is the size of the character's first class hit die, 0 for a
+// class the SRD data doesn't know
+func (m Model) hitDie() int {
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		if class, ok := ruleset.class(m.character.Class); ok {
+			if match := hitDicePattern.FindStringSubmatch(class.HitDie); match != nil {
+				die, _ := strconv.Atoi(match[2])
+				return die
+			}
+		}
+		return 0
+	}
+	return m.classHitDie(m.character.Class)
+}
+
+// classHitDie is the size of a 5e class's hit die, 0 if unknown
+func (m Model) classHitDie(name string) int {
+	if class, ok := m.srdData.class(name); ok {
+		return class.HitDie
+	}
+	return 0
+}
+
+// maxHP takes the full hit die of the first class at 1st level, then the
+// die rolled at each level-up or the rounded-up average when none was, each
+// with the Constitution modifier and at least 1 a level. Multiclassed
+// characters take each class's own die for its levels. OSE characters
+// follow the Hit Dice column of their level, flat bonuses and all,
+// averaging the first die too. A condition can halve the result
+func (m Model) maxHP() int {
+	con := m.ruleset().AbilityModifier(m.character.Abilities.Constitution)
+	hp := 0
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		die := m.hitDie()
+		class, _ := ruleset.class(m.character.Class)
+		row, _ := class.level(max(m.character.Level, 1))
+		match := hitDicePattern.FindStringSubmatch(row.HitDice)
+		if die == 0 || match == nil {
+			return 0
+		}
+		dice, _ := strconv.Atoi(match[1])
+		flat, _ := strconv.Atoi(match[3])
+		hp = max(die/2+1+con, 1) + flat
+		for n := 1; n < dice; n++ {
+			hp += hitDieHP(m.character.HPRolls, n, die, con)
+		}
+	} else {
+		for i, class := range m.classLevels() {
+			die := m.classHitDie(class.Name)
+			if die == 0 {
+				return 0
+			}
+			dice := class.Level
+			if i == 0 {
+				hp += max(die+con, 1)
+				dice--
+			}
+			for n := 1; n <= dice; n++ {
+				hp += hitDieHP(class.HPRolls, n, die, con)
+			}
+		}
+	}
+
+	if hasEffect(m.conditionEffects(), effectHPMaxHalved, "") {
+		hp /= 2
+	}
+	return hp
+}
+
+// currentHP is the maximum less the damage taken, never below 0
+func (m Model) currentHP() int {
+	return max(m.maxHP()-m.character.Combat.Damage, 0)
+}
+
+// armorClass is the ruleset's AC for the equipped armor and shield plus
+// the bonuses of magic items, with a breakdown for the sheet: "Chain mail
+// 16, Shield +2". Classes with Unarmored Defense add a second ability when
+// the character wears no armor
+func (m Model) armorClass() ArmorClass {
+	var worn []Item
+	for _, item := range m.equipment {
+		if item.Equipped {
+			worn = append(worn, item)
+		}
+	}
+	ac := m.ruleset().ArmorClass(m.character, worn)
+
+	if !ac.Body {
+		if ability, withShield := m.unarmoredDefense(); ability != "" && (!ac.Shield || withShield) {
+			n := m.ruleset().AbilityModifier(m.character.Abilities.score(ability))
+			ac.Value += n
+			ac.Parts[0] += fmt.Sprintf(" + %s %+d", ability[:3], n)
+		}
+	}
+	for _, item := range worn {
+		magic, ok := m.srdData.magicItem(item.Name)
+		if !ok {
+			continue
+		}
+		if match := itemACBonusPattern.FindStringSubmatch(magic.Description); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			ac.add(n, fmt.Sprintf("%s %+d", item.Name, n))
+		} else if match := itemUnarmoredACBonusPattern.FindStringSubmatch(magic.Description); match != nil && !ac.Body && !ac.Shield {
+			n, _ := strconv.Atoi(match[1])
+			ac.add(n, fmt.Sprintf("%s %+d", item.Name, n))
+		}
+	}
+	return ac
+}
+
+// unarmoredDefense is the ability a class's Unarmored Defense adds to AC,
+// "" if the character doesn't have the feature, and whether it still
+// works with a shield
+func (m Model) unarmoredDefense() (string, bool) {
+	if m.ruleset().ID() != "5e" {
+		return "", false
+	}
+	// A second class's Unarmored Defense doesn't replace the first's
+	for _, feature := range m.classFeatures() {
+		if feature.Name != "Unarmored Defense" {
+			continue
+		}
+		if match := unarmoredDefensePattern.FindStringSubmatch(feature.Description); match != nil {
+			return match[1], strings.Contains(feature.Description, "can use a shield")
+		}
+	}
+	return "", false
+}
+
+// initiative is the Dexterity modifier, or the DEX initiative column of
+// the OSE ability score table
+func (m Model) initiative() int {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		return oseInitiative[oseScoreBand(m.character.Abilities.Dexterity)]
+	}
+	return m.ruleset().AbilityModifier(m.character.Abilities.Dexterity)
+}
+
+// speed is the walking speed after the load, conditions and heavy armor the
+// character is too weak for, which costs 10 feet. The variant encumbrance
+// rules ignore the armor's Strength, as does OSE
+func (m Model) speed() int {
+	speed := m.character.Speed
+	if encumbrance := m.encumbrance(); encumbrance.Speed >= 0 {
+		speed = encumbrance.Speed
+	}
+	if _, ose := m.ruleset().(oseRuleset); !ose && m.character.Encumbrance != encumbranceVariant {
+		for _, item := range m.equipment {
+			if armor, _, ok := m.srdData.armor(item.Name); ok && item.Equipped && armor.StrengthRequirement > m.character.Abilities.Strength {
+				speed -= 10
+				break
+			}
+		}
+	}
+	effects := m.conditionEffects()
+	switch {
+	case hasEffect(effects, effectSpeedZero, ""):
+		return 0
+	case hasEffect(effects, effectSpeedHalved, ""):
+		return speed / 2
+	}
+	return max(speed, 0)
+}
+
+// takeDamage spends temporary hit points first. Under OSE a character
+// reduced to 0 hit points is killed. Under 5e, damage that drops the
+// character to 0 knocks it unconscious, and what's left over kills outright
+// if it matches the hit point maximum. Damage at 0 is a failed death save,
+// two for a critical hit, or death if it matches the maximum. A stable
+// character hurt at 0 starts its death saves over
+func (m *Model) takeDamage(amount int, critical bool) {
+	maxHP := m.maxHP()
+	combat := &m.character.Combat
+	switch {
+	case maxHP == 0:
+		m.message = fmt.Sprintf("Can't take damage: no hit point maximum for class %q", m.character.Class)
+		return
+	case combat.Dead:
+		m.message = "Already dead"
+		return
+	}
+	absorbed := min(combat.TempHP, amount)
+	combat.TempHP -= absorbed
+	amount -= absorbed
+
+	current := m.currentHP()
+	if current == 0 && amount > 0 {
+		if amount >= maxHP {
+			combat.Dead = true
+			m.message = fmt.Sprintf("Took %d damage at 0 HP: killed outright", amount+absorbed)
+			return
+		}
+		// Damage ends stability: the saves start over from this failure
+		if combat.DeathSaves.Successes >= 3 {
+			combat.DeathSaves.Successes = 0
+		}
+		m.addDeathSave(false)
+		if critical {
+			m.addDeathSave(false)
+		}
+		return
+	}
+	combat.Damage += min(amount, current)
+	m.message = fmt.Sprintf("Took %d damage, %d HP left", amount+absorbed, m.currentHP())
+	if m.currentHP() > 0 || amount == 0 {
+		return
+	}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		combat.Dead = true
+		m.message = fmt.Sprintf("Took %d damage: reduced to 0 HP and killed", amount+absorbed)
+		return
+	}
+	if amount-current >= maxHP {
+		combat.Dead = true
+		m.message = fmt.Sprintf("Took %d damage: killed outright", amount+absorbed)
+		return
+	}
+	if condition, ok := m.srdData.condition("Unconscious"); ok {
+		m.addCondition(condition, 0)
+	}
+	m.message = fmt.Sprintf("Took %d damage: down to 0 HP and unconscious", amount+absorbed)
+}
+
+// heal restores hit points up to the maximum. Any healing at 0 HP wakes the
+// character and clears its death saves. The dead stay dead until revived
+func (m *Model) heal(amount int) {
+	combat := &m.character.Combat
+	if combat.Dead {
+		m.message = "Can't heal the dead; ctrl+v to revive"
+		return
+	}
+	wasDown := m.currentHP() == 0
+	combat.Damage = max(combat.Damage-amount, 0)
+	if wasDown && m.currentHP() > 0 {
+		m.wake()
+	}
+	m.message = fmt.Sprintf("Healed %d, %d HP", amount, m.currentHP())
+}
+
+// revive brings a dead character back with 1 hit point, as a spell like
+// revivify or raise dead does
+func (m *Model) revive() {
+	combat := &m.character.Combat
+	if !combat.Dead {
+		m.message = "Only the dead can be revived"
+		return
+	}
+	combat.Dead = false
+	combat.Damage = max(m.maxHP()-1, 0)
+	m.wake()
+	m.message = fmt.Sprintf("Revived with %d HP", m.currentHP())
+}
+
+// wake clears the death saves and the Unconscious condition of a character
+// back above 0 hit points
+func (m *Model) wake() {
+	m.character.Combat.DeathSaves = DeathSaves{}
+	for i, active := range m.character.Conditions {
+		if active.Name == "Unconscious" {
+			m.removeCondition(i)
+			break
+		}
+	}
+}
+
+// addDeathSave records a death saving throw. Three successes stabilize the
+// character and three failures kill it
+func (m *Model) addDeathSave(success bool) {
+	saves := &m.character.Combat.DeathSaves
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		m.message = "OSE has no death saves"
+		return
+	}
+	if m.currentHP() > 0 {
+		m.message = "Death saves are only made at 0 HP"
+		return
+	}
+	if m.character.Combat.Dead || saves.Successes >= 3 || saves.Failures >= 3 {
+		return
+	}
+	if success {
+		saves.Successes++
+	} else {
+		saves.Failures++
+	}
+	switch {
+	case saves.Successes == 3:
+		m.message = "Stable at 0 HP"
+	case saves.Failures == 3:
+		m.character.Combat.Dead = true
+		m.message = "Dead after three failed death saves"
+	default:
+		m.message = fmt.Sprintf("Death saves: %d successes, %d failures", saves.Successes, saves.Failures)
+	}
+}
+
+// hitDicePool is the character's hit dice of one size
+type hitDicePool struct {
+	Die   int
+	Total int
+	Spent int
+}
+
+// hitDicePools groups the hit dice of the character's classes by size, one
+// die a class level. An OSE character has one pool of their class's die
+func (m Model) hitDicePools() []hitDicePool {
+	var pools []hitDicePool
+	add := func(die, n int) {
+		for i := range pools {
+			if pools[i].Die == die {
+				pools[i].Total += n
+				return
+			}
+		}
+		pools = append(pools, hitDicePool{Die: die, Total: n})
+	}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		if die := m.hitDie(); die > 0 {
+			add(die, max(m.character.Level, 1))
+		}
+	} else {
+		for _, class := range m.classLevels() {
+			if die := m.classHitDie(class.Name); die > 0 {
+				add(die, class.Level)
+			}
+		}
+	}
+	for i := range pools {
+		pools[i].Spent = min(m.character.Combat.HitDiceSpent[pools[i].Die], pools[i].Total)
+	}
+	return pools
+}
+
+// spendHitDie heals with one of the character's hit dice, asking which
+// size when they have more than one left
+func (m *Model) spendHitDie() {
+	pools := m.hitDicePools()
+	if len(pools) == 0 {
+		m.message = fmt.Sprintf("Unknown hit die for class %q", m.character.Class)
+		return
+	}
+	var options []string
+	var dice []int
+	for _, pool := range pools {
+		if pool.Spent < pool.Total {
+			options = append(options, fmt.Sprintf("d%d (%d left)", pool.Die, pool.Total-pool.Spent))
+			dice = append(dice, pool.Die)
+		}
+	}
+	switch len(dice) {
+	case 0:
+		m.message = "No hit dice left"
+	case 1:
+		m.spendHitDieOf(dice[0])
+	default:
+		m.picker = &catalogPicker{
+			title:   "Spend Hit Die",
+			options: options,
+			choose: func(m *Model, index int) {
+				m.spendHitDieOf(dice[index])
+			},
+		}
+	}
+}
+
+// spendHitDieOf rolls a hit die of a size plus Constitution and heals that
+// much, the way a short rest does
+func (m *Model) spendHitDieOf(die int) {
+	combat := &m.character.Combat
+	if combat.HitDiceSpent == nil {
+		combat.HitDiceSpent = make(map[int]int)
+	}
+	combat.HitDiceSpent[die]++
+	roll := rollDie(die)
+	amount := max(roll+m.ruleset().AbilityModifier(m.character.Abilities.Constitution), 0)
+	m.heal(amount)
+	m.message = fmt.Sprintf("Rolled %d on a d%d: %s", roll, die, m.message)
+}
+
+// regainHitDice gives back up to n spent hit dice, the largest first
+func (m *Model) regainHitDice(n int) {
+	pools := m.hitDicePools()
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Die > pools[j].Die })
+	for _, pool := range pools {
+		if back := min(pool.Spent, n); back > 0 {
+			m.character.Combat.HitDiceSpent[pool.Die] -= back
+			n -= back
+		}
+	}
+}
+
+// applyCombatInputs reads "-7" (damage) or "+5" (healing) from the HP
+// change input and a new temporary HP total from its input. It reports
+// whether either was filled in
+func (m *Model) applyCombatInputs() bool {
+	applied := false
+	if value := strings.TrimSpace(m.inputs["hp_change"].Value()); value != "" {
+		applied = true
+		// "-7c" is damage from a critical hit
+		critical := strings.HasPrefix(value, "-") && strings.HasSuffix(strings.ToLower(value), "c")
+		amount, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(value), "c"))
+		switch {
+		case err != nil || !strings.ContainsAny(value[:1], "+-"):
+			m.message = fmt.Sprintf("Type -N for damage, -Nc for a critical hit or +N for healing, not %q", value)
+			return true
+		case amount < 0:
+			m.takeDamage(-amount, critical)
+		default:
+			m.heal(amount)
+		}
+		m.setInput("hp_change", "")
+	}
+	if value := strings.TrimSpace(m.inputs["temp_hp"].Value()); value != "" {
+		applied = true
+		temp, err := strconv.Atoi(value)
+		if err != nil || temp < 0 {
+			m.message = fmt.Sprintf("Can't read temporary HP %q", value)
+			return true
+		}
+		m.character.Combat.TempHP = temp
+		m.setInput("temp_hp", "")
+		m.message = fmt.Sprintf("%d temporary HP", temp)
+	}
+	return applied
+}
+
+// hitDieHP is what the nth hit die after 1st level gives: its level-up
+// roll, or the rounded-up average, with the Constitution modifier and at
+// least 1
+func hitDieHP(rolls []int, n, die, con int) int {
+	roll := die/2 + 1
+	if n <= len(rolls) && rolls[n-1] > 0 {
+		roll = rolls[n-1]
+	}
+	return max(roll+con, 1)
+}
+
+// renderCombat is the Combat tab
+func (m Model) renderCombat() string {
+	combat := m.character.Combat
+	view := titleStyle.Render("Combat") + "\n\n"
+
+	maxHP := m.maxHP()
+	if maxHP == 0 {
+		view += fmt.Sprintf("Hit Points: unknown class %q\n", m.character.Class)
+	} else {
+		view += fmt.Sprintf("Hit Points: %d / %d", m.currentHP(), maxHP)
+		if combat.TempHP > 0 {
+			view += fmt.Sprintf(" (+%d temporary)", combat.TempHP)
+		}
+		view += "\n"
+	}
+	ac := m.armorClass()
+	view += fmt.Sprintf("Armor Class: %s (%s)\n", ac, ac.Breakdown())
+	view += fmt.Sprintf("Initiative: %+d\n", m.initiative())
+	view += fmt.Sprintf("Speed: %d ft.", m.speed())
+	if encumbrance := m.encumbrance(); encumbrance.overloaded() {
+		view += " " + warningStyle.Render("("+encumbrance.Status+")")
+	} else if encumbrance.Status != "" {
+		view += " (" + encumbrance.Status + ")"
+	}
+	view += "\n"
+	if pools := m.hitDicePools(); len(pools) > 0 {
+		var dice []string
+		for _, pool := range pools {
+			dice = append(dice, fmt.Sprintf("%d/%dd%d", pool.Total-pool.Spent, pool.Total, pool.Die))
+		}
+		view += "Hit Dice: " + strings.Join(dice, ", ") + "\n"
+	}
+	_, ose := m.ruleset().(oseRuleset)
+	if combat.Dead {
+		view += warningStyle.Render("Dead") + "\n"
+	} else if maxHP > 0 && m.currentHP() == 0 && !ose {
+		view += fmt.Sprintf("Death Saves: %s Success %s Failure\n",
+			deathSaveBoxes(combat.DeathSaves.Successes), deathSaveBoxes(combat.DeathSaves.Failures))
+	}
+
+	if m.mode == "edit" {
+		view += "\n" + m.inputs["hp_change"].View() + "\n"
+		view += m.inputs["temp_hp"].View() + "\n"
+		view += "enter to apply, ctrl+s/ctrl+f for a death save success/failure, ctrl+k to spend a hit die, ctrl+v to revive"
+	}
+	return sectionStyle.Render(view)
+}
+
+// deathSaveBoxes renders a count out of three as "[x][x][ ]"
+func deathSaveBoxes(n int) string {
+	return strings.Repeat("[x]", n) + strings.Repeat("[ ]", 3-n)
+}
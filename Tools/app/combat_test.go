@@ -0,0 +1,229 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// combatModel is a 5e character of the given class and level with the
+// embedded SRD data
+func combatModel(t *testing.T, class string, level int) Model {
+	t.Helper()
+	data, err := loadSRDData(nil)
+	if err != nil {
+		t.Fatalf("loadSRDData: %v", err)
+	}
+	m := Model{rulesets: []Ruleset{dnd5eRuleset{armor: data.Armor}}, srdData: data, inputs: map[string]textinput.Model{}}
+	m.character.Ruleset = dnd5eRuleset{}.ID()
+	m.character.Class = class
+	m.character.Level = level
+	m.character.Abilities = Abilities{Strength: 10, Dexterity: 10, Constitution: 10, Intelligence: 10, Wisdom: 10, Charisma: 10}
+	return m
+}
+
+// unconscious reports whether the character has the Unconscious condition
+func unconscious(m Model) bool {
+	for _, active := range m.character.Conditions {
+		if active.Name == "Unconscious" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMaxHP(t *testing.T) {
+	tests := []struct {
+		class string
+		level int
+		con   int
+		want  int
+	}{
+		{"Fighter", 1, 10, 10},
+		{"Fighter", 3, 14, 28},
+		{"Wizard", 1, 8, 5},
+		{"Wizard", 4, 3, 5}, // at least 1 a level
+		{"Nobody", 5, 10, 0},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, tt.class, tt.level)
+		m.character.Abilities.Constitution = tt.con
+		if got := m.maxHP(); got != tt.want {
+			t.Errorf("%s %d with Con %d: maxHP = %d, want %d", tt.class, tt.level, tt.con, got, tt.want)
+		}
+	}
+}
+
+func TestArmorClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		class     string
+		dexterity int
+		equipped  []string
+		want      int
+		breakdown string
+	}{
+		{"unarmored", "Fighter", 14, nil, 12, "10 + Dex +2"},
+		{"chain mail and shield", "Fighter", 14, []string{"Chain mail", "Shield"}, 18, "Chain mail 16, Shield +2"},
+		{"medium armor caps Dex", "Fighter", 18, []string{"Half plate"}, 17, "Half plate 17"},
+		{"magic armor", "Fighter", 14, []string{"Studded leather, +1"}, 15, "Studded leather 14, magic +1"},
+		{"unarmored defense", "Monk", 16, nil, 15, "10 + Dex +3 + Wis +2"},
+		{"ring of protection", "Fighter", 14, []string{"Ring of Protection"}, 13, "10 + Dex +2, Ring of Protection +1"},
+		{"bracers with a shield", "Fighter", 14, []string{"Bracers of Defense", "Shield"}, 14, "10 + Dex +2, Shield +2"},
+		{"bracers unarmored", "Fighter", 14, []string{"Bracers of Defense"}, 14, "10 + Dex +2, Bracers of Defense +2"},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, tt.class, 1)
+		m.character.Abilities.Dexterity = tt.dexterity
+		m.character.Abilities.Wisdom = 14
+		for _, name := range tt.equipped {
+			m.equipment = append(m.equipment, Item{Name: name, Quantity: 1, Equipped: true})
+		}
+		// Carried but not worn
+		m.equipment = append(m.equipment, Item{Name: "Plate", Quantity: 1})
+		ac := m.armorClass()
+		if ac.Value != tt.want || ac.Breakdown() != tt.breakdown {
+			t.Errorf("%s: armorClass = %d, %q, want %d, %q", tt.name, ac.Value, ac.Breakdown(), tt.want, tt.breakdown)
+		}
+	}
+}
+
+func TestTakeDamageAndHeal(t *testing.T) {
+	m := combatModel(t, "Fighter", 1)
+	m.character.Combat.TempHP = 3
+
+	m.takeDamage(5, false)
+	if m.character.Combat.TempHP != 0 || m.currentHP() != 8 {
+		t.Errorf("after 5 damage: temp HP %d, HP %d, want 0 and 8", m.character.Combat.TempHP, m.currentHP())
+	}
+	m.takeDamage(9, false)
+	if m.currentHP() != 0 || !unconscious(m) {
+		t.Errorf("after 9 damage: HP %d, conditions %+v, want 0 and unconscious", m.currentHP(), m.character.Conditions)
+	}
+	m.takeDamage(1, false)
+	if saves := m.character.Combat.DeathSaves; saves.Failures != 1 {
+		t.Errorf("after damage at 0 HP: death saves %+v, want 1 failure", saves)
+	}
+
+	m.heal(4)
+	if m.currentHP() != 4 || unconscious(m) || m.character.Combat.DeathSaves != (DeathSaves{}) {
+		t.Errorf("after healing: HP %d, conditions %+v, death saves %+v", m.currentHP(), m.character.Conditions, m.character.Combat.DeathSaves)
+	}
+	m.heal(20)
+	if m.currentHP() != 10 {
+		t.Errorf("after healing past the maximum: HP %d, want 10", m.currentHP())
+	}
+
+	m.takeDamage(20, false)
+	if !m.character.Combat.Dead || !strings.Contains(m.message, "killed outright") {
+		t.Errorf("damage past the maximum: dead %v, message %q, want killed outright", m.character.Combat.Dead, m.message)
+	}
+	m.takeDamage(1, false)
+	if m.message != "Already dead" {
+		t.Errorf("damage when dead: message %q", m.message)
+	}
+}
+
+func TestTakeDamageAtZero(t *testing.T) {
+	tests := []struct {
+		name     string
+		saves    DeathSaves
+		amount   int
+		critical bool
+		want     DeathSaves
+		dead     bool
+	}{
+		{"hit", DeathSaves{}, 3, false, DeathSaves{Failures: 1}, false},
+		{"critical hit", DeathSaves{}, 3, true, DeathSaves{Failures: 2}, false},
+		{"massive damage", DeathSaves{}, 10, false, DeathSaves{}, true},
+		{"hit while saving", DeathSaves{Successes: 2}, 3, false, DeathSaves{Successes: 2, Failures: 1}, false},
+		{"hit while stable", DeathSaves{Successes: 3, Failures: 1}, 3, false, DeathSaves{Failures: 2}, false},
+		{"critical hit while stable", DeathSaves{Successes: 3, Failures: 1}, 3, true, DeathSaves{Failures: 3}, true},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, "Fighter", 1)
+		m.character.Combat.Damage = 10
+		m.character.Combat.DeathSaves = tt.saves
+		m.takeDamage(tt.amount, tt.critical)
+		if got := m.character.Combat; got.DeathSaves != tt.want || got.Dead != tt.dead {
+			t.Errorf("%s: death saves %+v, dead %v, want %+v, %v", tt.name, got.DeathSaves, got.Dead, tt.want, tt.dead)
+		}
+	}
+
+	m := combatModel(t, "Nobody", 1)
+	m.takeDamage(3, false)
+	if m.character.Combat.Damage != 0 || !strings.Contains(m.message, "no hit point maximum") {
+		t.Errorf("damage without a maximum: damage %d, message %q", m.character.Combat.Damage, m.message)
+	}
+}
+
+func TestHealAndRevive(t *testing.T) {
+	m := combatModel(t, "Fighter", 1)
+	m.takeDamage(10, false)
+	m.addDeathSave(false)
+	m.addDeathSave(false)
+	m.addDeathSave(false)
+	if !m.character.Combat.Dead {
+		t.Fatal("alive after three failed death saves")
+	}
+
+	m.heal(5)
+	if !m.character.Combat.Dead || m.currentHP() != 0 {
+		t.Errorf("healing the dead: dead %v, HP %d, want still dead at 0", m.character.Combat.Dead, m.currentHP())
+	}
+
+	m.revive()
+	combat := m.character.Combat
+	if combat.Dead || m.currentHP() != 1 || combat.DeathSaves != (DeathSaves{}) || unconscious(m) {
+		t.Errorf("after reviving: dead %v, HP %d, death saves %+v, conditions %+v", combat.Dead, m.currentHP(), combat.DeathSaves, m.character.Conditions)
+	}
+	m.revive()
+	if m.currentHP() != 1 || m.message != "Only the dead can be revived" {
+		t.Errorf("reviving the living: HP %d, message %q", m.currentHP(), m.message)
+	}
+}
+
+func TestOSEDeathAtZero(t *testing.T) {
+	m := combatModel(t, "Fighter", 1)
+	ruleset := oseRuleset{classes: m.srdData.OSEClasses}
+	m.rulesets = []Ruleset{ruleset}
+	m.character.Ruleset = ruleset.ID()
+
+	m.takeDamage(m.maxHP()-1, false)
+	if m.character.Combat.Dead {
+		t.Fatalf("dead at %d HP", m.currentHP())
+	}
+	m.takeDamage(1, false)
+	if !m.character.Combat.Dead || unconscious(m) {
+		t.Errorf("at 0 HP: dead %v, conditions %+v, want dead", m.character.Combat.Dead, m.character.Conditions)
+	}
+}
+
+func TestAddDeathSave(t *testing.T) {
+	tests := []struct {
+		name  string
+		saves []bool
+		want  DeathSaves
+	}{
+		{"mixed", []bool{true, false, true}, DeathSaves{Successes: 2, Failures: 1}},
+		{"stable", []bool{true, true, true, false}, DeathSaves{Successes: 3}},
+		{"dead", []bool{false, false, false, true}, DeathSaves{Failures: 3}},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, "Fighter", 1)
+		m.character.Combat.Damage = 10
+		for _, success := range tt.saves {
+			m.addDeathSave(success)
+		}
+		if got := m.character.Combat.DeathSaves; got != tt.want {
+			t.Errorf("%s: death saves %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	m := combatModel(t, "Fighter", 1)
+	m.addDeathSave(false)
+	if m.character.Combat.DeathSaves != (DeathSaves{}) {
+		t.Errorf("death save above 0 HP counted: %+v", m.character.Combat.DeathSaves)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ActiveCondition is a condition the character is currently under
+type ActiveCondition struct {
+	Name   string `json:"name"`
+	Level  int    `json:"level,omitempty"`  // for leveled conditions like exhaustion
+	Rounds int    `json:"rounds,omitempty"` // rounds left, 0 until removed
+}
+
+// String renders "Exhaustion 2" or "Poisoned (10 rounds)"
+func (c ActiveCondition) String() string {
+	s := c.Name
+	if c.Level > 0 {
+		s += fmt.Sprintf(" %d", c.Level)
+	}
+	if c.Rounds > 0 {
+		s += fmt.Sprintf(" (%d rounds)", c.Rounds)
+	}
+	return s
+}
+
+var conditionDurationPattern = regexp.MustCompile(`^(\d+)\s*(round|minute|hour|day)?s?$`)
+
+// roundsPerUnit converts durations to 6-second combat rounds
+var roundsPerUnit = map[string]int{"": 1, "round": 1, "minute": 10, "hour": 600, "day": 14400}
+
+// parseConditionDuration reads "3", "10 rounds", "1 minute" or "8 hours" as
+// a number of rounds. An empty duration lasts until the condition is removed
+func parseConditionDuration(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	match := conditionDurationPattern.FindStringSubmatch(s)
+	if match == nil {
+		return 0, fmt.Errorf("can't read duration %q", s)
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n * roundsPerUnit[match[2]], nil
+}
+
+// condition finds a catalog condition by name
+func (d SRDData) condition(name string) (SRDCondition, bool) {
+	for _, condition := range d.Conditions {
+		if strings.EqualFold(condition.Name, name) {
+			return condition, true
+		}
+	}
+	return SRDCondition{}, false
+}
+
+// conditionEffects collects the effects of the character's conditions. A
+// leveled condition brings the effects of its level and every level below.
+// Being heavily encumbered under the variant rules counts as well
+func (m Model) conditionEffects() []ConditionEffect {
+	var effects []ConditionEffect
+	for _, active := range m.character.Conditions {
+		effects = append(effects, m.conditionEffectsOf(active)...)
+	}
+	return append(effects, m.encumbranceEffects()...)
+}
+
+// conditionEffectsOf is the effects of one of the character's conditions
+func (m Model) conditionEffectsOf(active ActiveCondition) []ConditionEffect {
+	condition, ok := m.srdData.condition(active.Name)
+	if !ok {
+		return nil
+	}
+	effects := append([]ConditionEffect(nil), condition.Effects...)
+	for _, level := range condition.Levels {
+		if level.Level <= active.Level {
+			effects = append(effects, level.Effects...)
+		}
+	}
+	return effects
+}
+
+// rollMode is "advantage", "disadvantage" or "" for a d20 roll of the given
+// kind ("attack", "check" or "save"). Ability narrows saving throw effects;
+// effects without one apply to every roll. Advantage and disadvantage cancel
+func rollMode(effects []ConditionEffect, kind, ability string) string {
+	advantage, disadvantage := false, false
+	for _, effect := range effects {
+		if effect.Ability != "" && effect.Ability != ability {
+			continue
+		}
+		switch effect.Type {
+		case kind + "_advantage":
+			advantage = true
+		case kind + "_disadvantage":
+			disadvantage = true
+		}
+	}
+	switch {
+	case advantage && !disadvantage:
+		return "advantage"
+	case disadvantage && !advantage:
+		return "disadvantage"
+	}
+	return ""
+}
+
+// rollModeSuffix marks a number rolled with advantage or disadvantage
+func rollModeSuffix(mode string) string {
+	switch mode {
+	case "advantage":
+		return " (adv)"
+	case "disadvantage":
+		return " (dis)"
+	}
+	return ""
+}
+
+// hasEffect reports whether any effect is of type kind for ability
+func hasEffect(effects []ConditionEffect, kind, ability string) bool {
+	for _, effect := range effects {
+		if effect.Type == kind && (effect.Ability == "" || effect.Ability == ability) {
+			return true
+		}
+	}
+	return false
+}
+
+// addCondition puts the character under a condition. Adding a leveled
+// condition again raises its level; anything else just resets the duration
+func (m *Model) addCondition(condition SRDCondition, rounds int) {
+	for i, active := range m.character.Conditions {
+		if active.Name != condition.Name {
+			continue
+		}
+		if len(condition.Levels) > 0 {
+			m.character.Conditions[i].Level = min(active.Level+1, len(condition.Levels))
+		}
+		m.character.Conditions[i].Rounds = rounds
+		m.message = fmt.Sprintf("Now %s", m.character.Conditions[i])
+		return
+	}
+
+	active := ActiveCondition{Name: condition.Name, Rounds: rounds}
+	if len(condition.Levels) > 0 {
+		active.Level = 1
+	}
+	m.character.Conditions = append(m.character.Conditions, active)
+	m.message = fmt.Sprintf("Now %s", active)
+}
+
+// removeCondition ends a condition, or lowers a leveled one by a level
+func (m *Model) removeCondition(index int) {
+	active := m.character.Conditions[index]
+	if active.Level > 1 {
+		m.character.Conditions[index].Level--
+		m.message = fmt.Sprintf("Now %s", m.character.Conditions[index])
+		return
+	}
+	m.character.Conditions = append(m.character.Conditions[:index], m.character.Conditions[index+1:]...)
+	m.message = fmt.Sprintf("No longer %s", active.Name)
+}
+
+// nextRound counts down the timed conditions and ends those that run out
+func (m *Model) nextRound() {
+	var remaining []ActiveCondition
+	var ended []string
+	for _, active := range m.character.Conditions {
+		if active.Rounds > 0 {
+			active.Rounds--
+			if active.Rounds == 0 {
+				ended = append(ended, active.Name)
+				continue
+			}
+		}
+		remaining = append(remaining, active)
+	}
+	m.character.Conditions = remaining
+
+	m.message = "Next round"
+	if len(ended) > 0 {
+		m.message += ": " + strings.Join(ended, ", ") + " ended"
+	}
+}
+
+// openConditionPicker lists the SRD conditions to put the character under,
+// lasting for the duration typed on the Basic Info tab
+func (m *Model) openConditionPicker() {
+	if len(m.srdData.Conditions) == 0 {
+		m.message = "No SRD conditions loaded"
+		return
+	}
+	rounds, err := parseConditionDuration(m.inputs["duration"].Value())
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+
+	var options []string
+	for _, condition := range m.srdData.Conditions {
+		options = append(options, condition.Name)
+	}
+	m.picker = &catalogPicker{
+		title:   "Add Condition",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.addCondition(m.srdData.Conditions[index], rounds)
+		},
+	}
+}
+
+// openRemoveConditionPicker lists the character's conditions to end
+func (m *Model) openRemoveConditionPicker() {
+	if len(m.character.Conditions) == 0 {
+		m.message = "No conditions to remove"
+		return
+	}
+
+	var options []string
+	for _, active := range m.character.Conditions {
+		options = append(options, active.String())
+	}
+	m.picker = &catalogPicker{
+		title:   "Remove Condition",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.removeCondition(index)
+		},
+	}
+}
+
+// renderConditions lists the character's conditions and what they do
+func (m Model) renderConditions() string {
+	view := titleStyle.Render("Conditions") + "\n"
+	if len(m.character.Conditions) == 0 {
+		view += "None\n"
+	}
+	for _, active := range m.character.Conditions {
+		var effects []string
+		for _, effect := range m.conditionEffectsOf(active) {
+			effects = append(effects, effect.String())
+		}
+		view += active.String()
+		if len(effects) > 0 {
+			view += ": " + strings.Join(effects, ", ")
+		}
+		view += "\n"
+	}
+	if m.mode == "edit" {
+		view += m.inputs["duration"].View() + "\n"
+		view += "ctrl+n to add a condition for the duration, ctrl+x to remove one, "
+	}
+	view += "ctrl+t for the next round\n"
+	return view
+}
@@ -0,0 +1,900 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// creationWizard walks through making a new character one step at a time.
+// Most steps are catalog pickers; the wizard screen shows between them, for
+// point buy and for the name. The character is built in place and Esc puts
+// back the one that was open before
+type creationWizard struct {
+	step     int
+	screen   string // creationPointBuy, creationName, or "" while a step's pickers run
+	backup   Model
+	selected int  // ability on the point-buy screen
+	unspent  bool // Enter was pressed once with points left to buy
+	name     textinput.Model
+}
+
+const (
+	creationPointBuy = "point-buy"
+	creationName     = "name"
+)
+
+// creationStep is one stage of the wizard. start opens its pickers or
+// screen and calls nextCreationStep when done, straight away if the step
+// doesn't apply to the character
+type creationStep struct {
+	title string
+	start func(m *Model)
+}
+
+func creationSteps() []creationStep {
+	return []creationStep{
+		{"Ruleset", (*Model).chooseCreationRuleset},
+		{"Ability scores", (*Model).chooseAbilityMethod},
+		{"Race", (*Model).chooseCreationRace},
+		{"Class", (*Model).chooseCreationClass},
+		{"Background", (*Model).chooseCreationBackground},
+		{"Skills", (*Model).chooseCreationSkills},
+		{"Languages and tools", (*Model).chooseCreationProficiencies},
+		{"Equipment", (*Model).chooseStartingEquipment},
+		{"Spells", (*Model).chooseStartingSpells},
+		{"Name", func(m *Model) { m.creation.screen = creationName }},
+	}
+}
+
+// Ability generation outside the SRD: the standard array and 27-point buy
+var (
+	standardArray  = []int{15, 14, 13, 12, 10, 8}
+	pointBuyCosts  = map[int]int{8: 0, 9: 1, 10: 2, 11: 3, 12: 4, 13: 5, 14: 7, 15: 9}
+	pointBuyBudget = 27
+)
+
+// startingWealth is the 5e starting gold by class when taking gold instead
+// of the class equipment: dice d4s times multiplier
+var startingWealth = map[string][2]int{
+	"Barbarian": {2, 10}, "Bard": {5, 10}, "Cleric": {5, 10}, "Druid": {2, 10},
+	"Fighter": {5, 10}, "Monk": {5, 1}, "Paladin": {5, 10}, "Ranger": {5, 10},
+	"Rogue": {4, 10}, "Sorcerer": {3, 10}, "Warlock": {4, 10}, "Wizard": {4, 10},
+}
+
+// standardLanguages are the languages of Characterizations/Languages.md,
+// the standard ones first, then the exotic
+var standardLanguages = []string{
+	"Common", "Dwarvish", "Elvish", "Giant", "Gnomish", "Goblin", "Halfling", "Orc",
+	"Abyssal", "Celestial", "Draconic", "Deep Speech", "Infernal", "Primordial", "Sylvan", "Undercommon",
+}
+
+// toolCategories are the equipment categories a tool proficiency can name
+var toolCategories = []string{"artisan's tools", "gaming set", "musical instrument", "tools"}
+
+var (
+	equipmentOptionPattern = regexp.MustCompile(`\(\w\)\s*`)
+	equipmentSplitPattern  = regexp.MustCompile(`,? and |, `)
+	oseRequirementPattern  = regexp.MustCompile(`(?i)minimum (STR|DEX|CON|INT|WIS|CHA) (\d+)`)
+)
+
+// openCreation starts the wizard on a blank 1st level character
+func (m *Model) openCreation() {
+	m.creation = &creationWizard{backup: *m, name: createInput("Name: ", "")}
+	m.character = Character{Ruleset: m.ruleset().ID(), Level: 1, Size: "Medium", Speed: 30}
+	m.skillList, m.equipment, m.weapons, m.spells, m.proficiencies = []Skill{}, []Item{}, []Weapon{}, []Spell{}, []string{}
+	m.selectedSpell, m.spellbookView = 0, false
+	m.startCreationStep()
+}
+
+// startCreationStep (re)starts the current step
+func (m *Model) startCreationStep() {
+	m.creation.screen = ""
+	creationSteps()[m.creation.step].start(m)
+}
+
+// nextCreationStep moves the wizard on
+func (m *Model) nextCreationStep() {
+	if m.creation == nil {
+		return
+	}
+	m.creation.step++
+	m.startCreationStep()
+}
+
+// continueCreation makes the picker a step opened move the wizard on once
+// something is chosen
+func (m *Model) continueCreation() {
+	m.afterPicker((*Model).nextCreationStep)
+}
+
+// cancelCreation puts back the character that was open
+func (m *Model) cancelCreation() {
+	*m = m.creation.backup
+	m.creation = nil
+	m.message = "Character creation cancelled"
+}
+
+// finishCreation names the character and opens it in the editor
+func (m *Model) finishCreation() {
+	name := strings.TrimSpace(m.creation.name.Value())
+	if name == "" {
+		m.message = "The character needs a name"
+		return
+	}
+	m.character.Name = name
+	m.character.Skills = m.skillList
+	m.character.Equipment = m.equipment
+	m.character.Weapons = m.weapons
+	m.character.Spells = m.spells
+	m.character.Proficiencies = m.proficiencies
+	m.creation = nil
+	m.activeTab = 0
+	m.updateInputsFromCharacter()
+
+	m.message = fmt.Sprintf("Created %s, a level 1 %s", name, m.character.Class)
+	var left Grants
+	for _, grants := range m.character.Granted {
+		left.AbilityChoices += grants.AbilityChoices
+		left.SkillChoices += grants.SkillChoices
+		left.ToolChoices += grants.ToolChoices
+		left.LanguageChoices += grants.LanguageChoices
+		left.CantripChoices += grants.CantripChoices
+	}
+	if choices := left.choices(); choices != "" {
+		m.message += " (still to choose: " + choices + ")"
+	}
+}
+
+func (m *Model) chooseCreationRuleset() {
+	var options []string
+	for _, ruleset := range m.rulesets {
+		options = append(options, ruleset.Name())
+	}
+	m.picker = &catalogPicker{
+		title:   "New Character: Ruleset",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.character.Ruleset = m.rulesets[index].ID()
+		},
+	}
+	m.continueCreation()
+}
+
+// chooseAbilityMethod offers the ways to generate ability scores: OSE rolls
+// 3d6 in order, 5e rolls 4d6 dropping the lowest, takes the standard array
+// or buys scores with points
+func (m *Model) chooseAbilityMethod() {
+	methods := []string{"Roll 4d6, drop the lowest", "Standard array (15, 14, 13, 12, 10, 8)", fmt.Sprintf("Point buy (%d points)", pointBuyBudget)}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		methods = []string{"Roll 3d6 in order", "Roll 4d6, drop the lowest"}
+	}
+	m.picker = &catalogPicker{
+		title:   "New Character: Ability Scores",
+		options: methods,
+		choose: func(m *Model, index int) {
+			switch {
+			case strings.HasPrefix(methods[index], "Roll 3d6"):
+				var rolls []string
+				for _, ability := range abilityNames {
+					score := sum(rollDice(3, 6))
+					m.character.Abilities.set(ability, score)
+					rolls = append(rolls, strconv.Itoa(score))
+				}
+				m.message = "Rolled " + strings.Join(rolls, ", ")
+				m.nextCreationStep()
+			case strings.HasPrefix(methods[index], "Roll 4d6"):
+				var scores []int
+				for range abilityNames {
+					scores = append(scores, roll4d6DropLowest())
+				}
+				sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+				m.assignAbilities(scores, abilityNames)
+			case strings.HasPrefix(methods[index], "Standard array"):
+				m.assignAbilities(append([]int(nil), standardArray...), abilityNames)
+			default:
+				for _, ability := range abilityNames {
+					m.character.Abilities.set(ability, 8)
+				}
+				m.creation.screen = creationPointBuy
+				m.creation.selected = 0
+			}
+		},
+	}
+}
+
+// roll4d6DropLowest rolls four d6 and adds up the highest three
+func roll4d6DropLowest() int {
+	rolls := rollDice(4, 6)
+	sort.Ints(rolls)
+	return sum(rolls[1:])
+}
+
+// assignAbilities has the player place each score on an ability in turn
+func (m *Model) assignAbilities(scores []int, abilities []string) {
+	if len(abilities) == 0 {
+		m.nextCreationStep()
+		return
+	}
+	if len(scores) == 0 {
+		m.nextCreationStep()
+		return
+	}
+	var options []string
+	for _, score := range scores {
+		options = append(options, strconv.Itoa(score))
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("New Character: %s (scores left: %s)", abilities[0], strings.Join(options, ", ")),
+		options: options,
+		choose: func(m *Model, index int) {
+			m.character.Abilities.set(abilities[0], scores[index])
+			rest := append(append([]int(nil), scores[:index]...), scores[index+1:]...)
+			m.assignAbilities(rest, abilities[1:])
+		},
+	}
+}
+
+// pointBuySpent is what the character's scores cost, false when one is
+// outside the 8 to 15 point-buy range
+func pointBuySpent(a Abilities) (int, bool) {
+	spent := 0
+	for _, ability := range abilityNames {
+		cost, ok := pointBuyCosts[a.score(ability)]
+		if !ok {
+			return 0, false
+		}
+		spent += cost
+	}
+	return spent, true
+}
+
+// adjustPointBuy raises or lowers the selected ability, refusing to leave
+// the 8 to 15 range or go over the budget
+func (m *Model) adjustPointBuy(step int) {
+	ability := abilityNames[m.creation.selected]
+	abilities := m.character.Abilities
+	abilities.add(ability, step)
+	spent, ok := pointBuySpent(abilities)
+	switch {
+	case !ok:
+		m.message = "Point buy scores run from 8 to 15"
+	case spent > pointBuyBudget:
+		m.message = fmt.Sprintf("Not enough points to raise %s", ability)
+	default:
+		m.character.Abilities = abilities
+		m.creation.unspent = false
+		m.message = fmt.Sprintf("%d of %d points left", pointBuyBudget-spent, pointBuyBudget)
+	}
+}
+
+// leavePointBuy moves on once the budget is spent. With points left it
+// asks first, moving on at a second Enter
+func (m *Model) leavePointBuy() {
+	spent, _ := pointBuySpent(m.character.Abilities)
+	if left := pointBuyBudget - spent; left > 0 && !m.creation.unspent {
+		m.creation.unspent = true
+		m.message = fmt.Sprintf("%d points left to spend: Enter again to leave them", left)
+		return
+	}
+	m.nextCreationStep()
+}
+
+// chooseCreationRace picks a 5e race, then any +1s its grants leave to the
+// player. OSE characters take their race from their class
+func (m *Model) chooseCreationRace() {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		m.nextCreationStep()
+		return
+	}
+	m.openRacePicker()
+	if m.picker == nil {
+		m.nextCreationStep()
+		return
+	}
+	choose := m.picker.choose
+	m.picker.choose = func(m *Model, index int) {
+		choose(m, index)
+		m.chooseAbilityIncreases(m.character.Granted["race"].AbilityChoices)
+	}
+}
+
+// chooseAbilityIncreases asks for n +1 increases to different abilities
+// the race doesn't already raise, recording them with the race's grants
+func (m *Model) chooseAbilityIncreases(n int) {
+	grants := m.character.Granted["race"]
+	if n == 0 {
+		m.nextCreationStep()
+		return
+	}
+	var options []string
+	for _, ability := range abilityNames {
+		if grants.AbilityIncreases[ability] == 0 {
+			options = append(options, ability)
+		}
+	}
+	if len(options) == 0 {
+		m.message = "Every ability is already raised by the race"
+		m.nextCreationStep()
+		return
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("New Character: +1 to an ability (%d left)", n),
+		options: options,
+		choose: func(m *Model, index int) {
+			grants := m.character.Granted["race"]
+			increases := map[string]int{options[index]: 1}
+			for ability, increase := range grants.AbilityIncreases {
+				increases[ability] = increase
+			}
+			grants.AbilityIncreases = increases
+			grants.AbilityChoices--
+			m.grantFrom("race", grants)
+			m.chooseAbilityIncreases(n - 1)
+		},
+	}
+}
+
+// chooseCreationClass picks a class. OSE classes the scores don't qualify
+// for are left out, and the class decides an OSE character's race. A 5e
+// class grants its armor, weapon and tool proficiencies
+func (m *Model) chooseCreationClass() {
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		var classes []OSEClass
+		var options []string
+		for _, class := range ruleset.classes {
+			if class.meetsRequirements(m.character.Abilities) {
+				classes = append(classes, class)
+				options = append(options, fmt.Sprintf("%s (prime requisite %s, %s)", class.Name, strings.Join(class.PrimeRequisites, " and "), class.Requirements))
+			}
+		}
+		if len(options) == 0 {
+			m.message = "No class accepts these ability scores"
+			m.nextCreationStep()
+			return
+		}
+		m.picker = &catalogPicker{
+			title:   "New Character: Class",
+			options: options,
+			choose: func(m *Model, index int) {
+				class := classes[index]
+				m.character.Class = class.Name
+				m.character.Race = "Human"
+				if class.Demihuman {
+					m.character.Race = class.Name
+				}
+			},
+		}
+		m.continueCreation()
+		return
+	}
+
+	classes := m.srdData.Classes
+	if len(classes) == 0 {
+		m.message = "No SRD classes loaded"
+		m.nextCreationStep()
+		return
+	}
+	var options []string
+	for _, class := range classes {
+		options = append(options, fmt.Sprintf("%s (d%d, %s)", class.Name, class.HitDie, strings.Join(class.SavingThrows, " and ")))
+	}
+	m.picker = &catalogPicker{
+		title:   "New Character: Class",
+		options: options,
+		choose: func(m *Model, index int) {
+			class := classes[index]
+			m.character.Class = class.Name
+			var proficiencies []string
+			proficiencies = append(proficiencies, class.Armor...)
+			proficiencies = append(proficiencies, class.Weapons...)
+			proficiencies = append(proficiencies, class.Tools...)
+			m.grantFrom("class", Grants{Proficiencies: proficiencies, SkillChoices: class.SkillChoices})
+		},
+	}
+	m.continueCreation()
+}
+
+// meetsRequirements checks the class's "Minimum CON 9" requirements
+func (c OSEClass) meetsRequirements(a Abilities) bool {
+	for _, match := range oseRequirementPattern.FindAllStringSubmatch(c.Requirements, -1) {
+		minimum, _ := strconv.Atoi(match[2])
+		if a.score(match[1]) < minimum {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Model) chooseCreationBackground() {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		m.nextCreationStep()
+		return
+	}
+	m.openBackgroundPicker()
+	m.continueCreation()
+}
+
+// chooseCreationSkills picks the class's skills from its list, then the
+// skills of the player's choice the race and background give. Each is
+// recorded with the class's, race's or background's grants
+func (m *Model) chooseCreationSkills() {
+	class, ok := m.srdData.class(m.character.Class)
+	if _, ose := m.ruleset().(oseRuleset); ose || !ok {
+		m.nextCreationStep()
+		return
+	}
+	chosen := func(source string) func(m *Model, skill string) {
+		return func(m *Model, skill string) {
+			grants := m.character.Granted[source]
+			grants.Skills = append(grants.Skills, skill)
+			grants.SkillChoices--
+			m.character.Granted[source] = grants
+		}
+	}
+	m.chooseSkills("New Character: Skill proficiency", class.SkillChoices, class.SkillOptions, chosen("class"), func(m *Model) {
+		m.chooseSkills("New Character: Skill proficiency", m.character.Granted["race"].SkillChoices, nil, chosen("race"), func(m *Model) {
+			m.chooseSkills("New Character: Skill proficiency", m.character.Granted["background"].SkillChoices, nil, chosen("background"), (*Model).nextCreationStep)
+		})
+	})
+}
+
+// chooseSkills asks for n skills the character isn't proficient in yet,
+// from options or from every skill, telling chosen about each
+func (m *Model) chooseSkills(title string, n int, options []string, chosen func(m *Model, skill string), then func(m *Model)) {
+	if n == 0 {
+		then(m)
+		return
+	}
+	if len(options) == 0 {
+		for _, skill := range m.ruleset().Skills() {
+			options = append(options, skill.Name)
+		}
+	}
+	proficient := make(map[string]bool)
+	for _, skill := range m.skillList {
+		proficient[strings.ToLower(skill.Name)] = skill.Proficient
+	}
+	var open []string
+	for _, name := range options {
+		if !proficient[strings.ToLower(name)] {
+			open = append(open, name)
+		}
+	}
+	if len(open) == 0 {
+		then(m)
+		return
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("%s (%d left)", title, n),
+		options: open,
+		choose: func(m *Model, index int) {
+			m.setSkillProficient(open[index], true)
+			if chosen != nil {
+				chosen(m, open[index])
+			}
+			m.chooseSkills(title, n-1, options, chosen, then)
+		},
+	}
+}
+
+// chooseCreationProficiencies picks the languages and tools of the
+// player's choice the race and background give, recording each with their
+// grants
+func (m *Model) chooseCreationProficiencies() {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		m.nextCreationStep()
+		return
+	}
+	chosen := func(source string, tool bool) func(m *Model, name string) {
+		return func(m *Model, name string) {
+			grants := m.character.Granted[source]
+			if tool {
+				grants.Proficiencies = append(grants.Proficiencies, name)
+				grants.ToolChoices--
+			} else {
+				grants.Languages = append(grants.Languages, name)
+				grants.LanguageChoices--
+			}
+			m.character.Granted[source] = grants
+		}
+	}
+	var steps []func(m *Model, then func(m *Model))
+	for _, source := range []string{"race", "background"} {
+		source, grants := source, m.character.Granted[source]
+		tools := grants.ToolOptions
+		if len(tools) == 0 {
+			tools = m.srdData.toolNames()
+		}
+		steps = append(steps, func(m *Model, then func(m *Model)) {
+			m.chooseProficiencies("New Character: Language", grants.LanguageChoices, standardLanguages, chosen(source, false), then)
+		}, func(m *Model, then func(m *Model)) {
+			m.chooseProficiencies("New Character: Tool proficiency", grants.ToolChoices, tools, chosen(source, true), then)
+		})
+	}
+	steps = append(steps, func(m *Model, _ func(m *Model)) { m.nextCreationStep() })
+	runSteps(m, steps)
+}
+
+// chooseProficiencies asks for n of the options the character doesn't have
+// yet, adding each to the proficiencies and telling chosen about it
+func (m *Model) chooseProficiencies(title string, n int, options []string, chosen func(m *Model, name string), then func(m *Model)) {
+	var open []string
+	for _, name := range options {
+		if !containsFold(m.proficiencies, name) {
+			open = append(open, name)
+		}
+	}
+	if n <= 0 || len(open) == 0 {
+		then(m)
+		return
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("%s (%d left)", title, n),
+		options: open,
+		choose: func(m *Model, index int) {
+			m.proficiencies = append(m.proficiencies, open[index])
+			chosen(m, open[index])
+			m.chooseProficiencies(title, n-1, options, chosen, then)
+		},
+	}
+}
+
+// toolNames are the catalog's tools, gaming sets and instruments
+func (d SRDData) toolNames() []string {
+	var names []string
+	for _, item := range d.Equipment {
+		if containsFold(toolCategories, item.Category) {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
+// chooseStartingEquipment takes the class's starting equipment or rolls
+// starting gold. OSE characters start with 3d6 × 10 gp to buy their gear
+func (m *Model) chooseStartingEquipment() {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		m.rollStartingGold(3, 6, 10)
+		m.nextCreationStep()
+		return
+	}
+	class, _ := m.srdData.class(m.character.Class)
+	options := []string{"Starting equipment"}
+	wealth, known := startingWealth[class.Name]
+	if known {
+		options = append(options, fmt.Sprintf("Starting gold (%dd4 × %d gp) instead", wealth[0], wealth[1]))
+	}
+	m.picker = &catalogPicker{
+		title:   "New Character: Equipment",
+		options: options,
+		choose: func(m *Model, index int) {
+			if index == 1 {
+				m.rollStartingGold(wealth[0], 4, wealth[1])
+				m.nextCreationStep()
+				return
+			}
+			m.chooseEquipmentEntries(class.Equipment)
+		},
+	}
+}
+
+// rollStartingGold adds dice × multiplier gold
+func (m *Model) rollStartingGold(dice, sides, multiplier int) {
+	gold := sum(rollDice(dice, sides)) * multiplier
+	m.character.Currency.GP += gold
+	m.message = fmt.Sprintf("Rolled %d gp", gold)
+}
+
+// chooseEquipmentEntries goes through the class's equipment lines, asking
+// which option to take where a line gives a choice
+func (m *Model) chooseEquipmentEntries(entries []string) {
+	if len(entries) == 0 {
+		m.nextCreationStep()
+		return
+	}
+	next := func(m *Model) { m.chooseEquipmentEntries(entries[1:]) }
+	options := equipmentOptions(entries[0])
+	if len(options) == 0 {
+		next(m)
+		return
+	}
+	if len(options) == 1 {
+		m.addStartingEquipment(options[0], next)
+		return
+	}
+	m.picker = &catalogPicker{
+		title:   "New Character: Equipment",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.addStartingEquipment(options[index], next)
+		},
+	}
+}
+
+// equipmentOptions splits "(a) a rapier, (b) a longsword, or (c) any simple
+// weapon" into its options
+func equipmentOptions(text string) []string {
+	var options []string
+	for _, part := range equipmentOptionPattern.Split(text, -1) {
+		part = strings.TrimSuffix(strings.TrimSpace(part), ",")
+		part = strings.TrimSuffix(strings.TrimSuffix(part, " or"), ",")
+		if part != "" {
+			options = append(options, part)
+		}
+	}
+	return options
+}
+
+// addStartingEquipment adds "a light crossbow and 20 bolts" to the sheet:
+// weapons to the weapons, armor and gear to the inventory. "Any simple
+// weapon" asks which
+func (m *Model) addStartingEquipment(text string, then func(m *Model)) {
+	var anyWeapon string
+	for _, piece := range equipmentSplitPattern.Split(trailingParenPattern.ReplaceAllString(text, ""), -1) {
+		piece = strings.TrimSpace(piece)
+		if words := strings.Fields(piece); len(words) > 1 && numberWords[strings.ToLower(words[0])] > 0 {
+			piece = strconv.Itoa(numberWords[strings.ToLower(words[0])]) + " " + strings.Join(words[1:], " ")
+		}
+		switch {
+		case piece == "":
+		case strings.HasPrefix(strings.ToLower(piece), "any ") && strings.Contains(piece, "weapon"):
+			anyWeapon = piece
+		default:
+			m.addEquipmentPiece(piece)
+		}
+	}
+	if anyWeapon != "" {
+		m.openAnyWeaponPicker(anyWeapon, then)
+		return
+	}
+	then(m)
+}
+
+// addEquipmentPiece adds one named piece of starting equipment
+func (m *Model) addEquipmentPiece(piece string) {
+	item := m.grantItem(piece)
+	if armor, _, ok := m.srdData.armor(item.Name); ok {
+		m.equipment = append(m.equipment, itemFromArmor(armor))
+		return
+	}
+	if weapon, ok := m.srdData.weaponNamed(item.Name); ok {
+		entry := weaponFromSRD(weapon)
+		if item.Quantity > 1 {
+			entry.Description = strings.TrimSpace(fmt.Sprintf("%d carried. %s", item.Quantity, entry.Description))
+		}
+		m.weapons = append(m.weapons, entry)
+		return
+	}
+	m.equipment = append(m.equipment, item)
+}
+
+// weaponNamed finds a weapon by a name as the class lists word it: "light
+// crossbow" for "Crossbow, light", "handaxes" for "Handaxe"
+func (d SRDData) weaponNamed(name string) (SRDWeapon, bool) {
+	candidates := []string{name, strings.TrimSuffix(name, "s")}
+	if words := strings.Fields(name); len(words) == 2 {
+		candidates = append(candidates, words[1]+", "+words[0])
+	}
+	for _, weapon := range d.Weapons {
+		if containsFold(candidates, weapon.Name) {
+			return weapon, true
+		}
+	}
+	return SRDWeapon{}, false
+}
+
+// openAnyWeaponPicker offers the weapons "any martial melee weapon" allows
+func (m *Model) openAnyWeaponPicker(text string, then func(m *Model)) {
+	text = strings.ToLower(text)
+	var weapons []SRDWeapon
+	var options []string
+	for _, w := range m.srdData.Weapons {
+		if (strings.Contains(text, "simple") && w.Category != "simple") ||
+			(strings.Contains(text, "martial") && w.Category != "martial") ||
+			(strings.Contains(text, "melee") && w.Ranged) ||
+			(strings.Contains(text, "ranged") && !w.Ranged) {
+			continue
+		}
+		weapons = append(weapons, w)
+		options = append(options, fmt.Sprintf("%s (%s, %s)", w.Name, w.Damage, w.Cost))
+	}
+	if len(weapons) == 0 {
+		then(m)
+		return
+	}
+	m.picker = &catalogPicker{
+		title:   "New Character: " + capitalize(text),
+		options: options,
+		choose: func(m *Model, index int) {
+			m.weapons = append(m.weapons, weaponFromSRD(weapons[index]))
+			then(m)
+		},
+	}
+}
+
+// chooseStartingSpells picks the cantrips a race gives the choice of, like
+// the high elf's wizard cantrip, then a 1st level caster's cantrips and
+// spells: six for a wizard's spellbook and the ones to prepare from it,
+// the spells known of bards, sorcerers and warlocks, or the prepared
+// spells of clerics and druids. OSE magic-users and elves pick the
+// beginning spells of their spell book
+func (m *Model) chooseStartingSpells() {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		if !m.hasSpellbook() {
+			m.nextCreationStep()
+			return
+		}
+		beginning := 0
+		for _, slots := range m.bookSlots() {
+			beginning += slots
+		}
+		m.pickSpells("New Character: Beginning spell", beginning, false, (*Model).learnableSpells, func(m *Model, s Spell) {
+			m.learnSpell(SpellbookEntry{Name: s.Name, Level: s.Level, Learned: learnedStarting})
+		}, (*Model).nextCreationStep)
+		return
+	}
+
+	race := m.character.Granted["race"]
+	m.pickSpells("New Character: Racial cantrip", race.CantripChoices, false, func(m *Model) []Spell {
+		return m.classSpellChoices(race.CantripList, 0, 0)
+	}, func(m *Model, s Spell) {
+		grants := m.character.Granted["race"]
+		grants.CantripChoices--
+		m.character.Granted["race"] = grants
+		m.spells = append(m.spells, s)
+	}, (*Model).chooseClassStartingSpells)
+}
+
+// chooseClassStartingSpells is the class's part of chooseStartingSpells
+func (m *Model) chooseClassStartingSpells() {
+	casters := m.spellcasters()
+	if len(casters) == 0 {
+		m.nextCreationStep()
+		return
+	}
+	caster := casters[0]
+	classSpells := func(level int) func(m *Model) []Spell {
+		return func(m *Model) []Spell {
+			return m.classSpellChoices(caster.Class.Name, level, level)
+		}
+	}
+	addSpell := func(prepared bool) func(m *Model, s Spell) {
+		return func(m *Model, s Spell) {
+			s.Prepared = prepared
+			s.Class = caster.Class.Name
+			m.spells = append(m.spells, s)
+		}
+	}
+
+	var spells func(m *Model)
+	switch {
+	case caster.Class.Spellbook:
+		spells = func(m *Model) {
+			m.pickSpells("New Character: Spellbook spell", m.freeSpellbookSpells(), false, (*Model).learnableSpells, func(m *Model, s Spell) {
+				m.learnSpell(SpellbookEntry{Name: s.Name, Level: s.Level, Learned: learnedLevelUp})
+			}, func(m *Model) {
+				m.pickSpells("New Character: Prepare", m.preparedLimit(), true, (*Model).unpreparedBookSpells, addSpell(true), (*Model).nextCreationStep)
+			})
+		}
+	case caster.Row.SpellsKnown > 0:
+		spells = func(m *Model) {
+			m.pickSpells("New Character: Spell known", caster.Row.SpellsKnown, false, classSpells(1), addSpell(false), (*Model).nextCreationStep)
+		}
+	case caster.Class.PreparesSpells && len(caster.Row.SpellSlots) > 0 && caster.Row.SpellSlots[0] > 0:
+		spells = func(m *Model) {
+			m.pickSpells("New Character: Prepare", m.preparedLimit(), true, classSpells(1), addSpell(true), (*Model).nextCreationStep)
+		}
+	default:
+		spells = (*Model).nextCreationStep
+	}
+	m.pickSpells("New Character: Cantrip", caster.Row.CantripsKnown, false, classSpells(0), addSpell(false), spells)
+}
+
+// unpreparedBookSpells are the spellbook's spells not on the spell list yet
+func (m Model) unpreparedBookSpells() []Spell {
+	var spells []Spell
+	for _, entry := range m.character.Spellbook {
+		if m.hasSpell(entry.Name) {
+			continue
+		}
+		spell, ok := m.catalogSpell(entry.Name)
+		if !ok {
+			spell = Spell{Name: entry.Name, Level: entry.Level}
+		}
+		spells = append(spells, spell)
+	}
+	return spells
+}
+
+// set changes an ability score by name or abbreviation
+func (a *Abilities) set(ability string, score int) {
+	a.add(ability, score-a.score(ability))
+}
+
+func (m Model) updateCreation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.cancelCreation()
+		return m, nil
+	}
+
+	switch m.creation.screen {
+	case creationPointBuy:
+		switch msg.String() {
+		case "up":
+			m.creation.selected = (m.creation.selected + len(abilityNames) - 1) % len(abilityNames)
+		case "down":
+			m.creation.selected = (m.creation.selected + 1) % len(abilityNames)
+		case "right", "+":
+			m.adjustPointBuy(1)
+		case "left", "-":
+			m.adjustPointBuy(-1)
+		case "enter":
+			m.leavePointBuy()
+		}
+	case creationName:
+		if msg.String() == "enter" {
+			m.finishCreation()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.creation.name, cmd = m.creation.name.Update(msg)
+		return m, cmd
+	default:
+		// A picker was cancelled: Enter opens the step again
+		if msg.String() == "enter" {
+			m.startCreationStep()
+		}
+	}
+	return m, nil
+}
+
+// renderCreation is the wizard screen: the character so far and the
+// current step's point buy or name input
+func (m Model) renderCreation() string {
+	steps := creationSteps()
+	view := titleStyle.Render(fmt.Sprintf("New Character: %s (%d/%d)", steps[m.creation.step].title, m.creation.step+1, len(steps))) + "\n\n"
+
+	c := m.character
+	view += fmt.Sprintf("Ruleset: %s\n", m.ruleset().Name())
+	var scores []string
+	for _, ability := range abilityNames {
+		scores = append(scores, fmt.Sprintf("%s %d", ability[:3], c.Abilities.score(ability)))
+	}
+	view += strings.Join(scores, "  ") + "\n"
+	if c.Race != "" || c.Class != "" {
+		view += fmt.Sprintf("%s %s", c.Race, c.Class)
+		if c.Background != "" {
+			view += ", " + c.Background
+		}
+		view += "\n"
+	}
+	view += fmt.Sprintf("%d skills, %d items, %d weapons, %d gp, %d spells\n\n", len(m.skillList), len(m.equipment), len(m.weapons), c.Currency.GP, len(m.spells))
+
+	switch m.creation.screen {
+	case creationPointBuy:
+		spent, _ := pointBuySpent(c.Abilities)
+		view += fmt.Sprintf("Points left: %d/%d\n", pointBuyBudget-spent, pointBuyBudget)
+		for i, ability := range abilityNames {
+			line := fmt.Sprintf("%-13s %2d (cost %d)", ability, c.Abilities.score(ability), pointBuyCosts[c.Abilities.score(ability)])
+			if i == m.creation.selected {
+				line = selectedItemStyle.Render(line)
+			}
+			view += line + "\n"
+		}
+		view += "\n↑/↓ to select, ←/→ to lower or raise, Enter when done, Esc to abandon"
+	case creationName:
+		view += m.creation.name.View() + "\n\nEnter to create the character, Esc to abandon"
+	default:
+		view += "Enter to pick again, Esc to abandon"
+	}
+	return sectionStyle.Render(view)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// creationStepIndex is the wizard step with the given title
+func creationStepIndex(t *testing.T, title string) int {
+	t.Helper()
+	for i, step := range creationSteps() {
+		if step.title == title {
+			return i
+		}
+	}
+	t.Fatalf("no creation step %q", title)
+	return 0
+}
+
+func TestPointBuySpent(t *testing.T) {
+	tests := []struct {
+		name      string
+		abilities Abilities
+		want      int
+		ok        bool
+	}{
+		{"all 8s", Abilities{8, 8, 8, 8, 8, 8}, 0, true},
+		{"standard spread", Abilities{15, 14, 13, 12, 10, 8}, 27, true},
+		{"even", Abilities{13, 13, 13, 12, 12, 12}, 27, true},
+		{"below 8", Abilities{7, 8, 8, 8, 8, 8}, 0, false},
+		{"above 15", Abilities{16, 8, 8, 8, 8, 8}, 0, false},
+	}
+	for _, tt := range tests {
+		spent, ok := pointBuySpent(tt.abilities)
+		if spent != tt.want || ok != tt.ok {
+			t.Errorf("%s: pointBuySpent = %d, %v, want %d, %v", tt.name, spent, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAdjustPointBuy(t *testing.T) {
+	tests := []struct {
+		name  string
+		start Abilities
+		step  int
+		want  int // the selected Strength after the step
+	}{
+		{"raise", Abilities{8, 8, 8, 8, 8, 8}, 1, 9},
+		{"lower", Abilities{12, 8, 8, 8, 8, 8}, -1, 11},
+		{"floor", Abilities{8, 8, 8, 8, 8, 8}, -1, 8},
+		{"ceiling", Abilities{15, 8, 8, 8, 8, 8}, 1, 15},
+		{"over budget", Abilities{14, 15, 15, 10, 8, 8}, 1, 14},
+	}
+	for _, tt := range tests {
+		m := Model{creation: &creationWizard{}}
+		m.character.Abilities = tt.start
+		m.adjustPointBuy(tt.step)
+		if got := m.character.Abilities.Strength; got != tt.want {
+			t.Errorf("%s: Strength %d, want %d (%s)", tt.name, got, tt.want, m.message)
+		}
+	}
+}
+
+func TestEquipmentOptions(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"(a) a rapier, (b) a longsword, or (c) any simple weapon", []string{"a rapier", "a longsword", "any simple weapon"}},
+		{"(a) chain mail or (b) leather armor, longbow, and 20 arrows", []string{"chain mail", "leather armor, longbow, and 20 arrows"}},
+		{"An explorer's pack", []string{"An explorer's pack"}},
+	}
+	for _, tt := range tests {
+		if got := equipmentOptions(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("equipmentOptions(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMeetsRequirements(t *testing.T) {
+	dwarf := OSEClass{Requirements: "Minimum CON 9"}
+	elf := OSEClass{Requirements: "Minimum INT 9"}
+	abilities := Abilities{Constitution: 9, Intelligence: 8}
+	if !dwarf.meetsRequirements(abilities) {
+		t.Error("CON 9 doesn't meet Minimum CON 9")
+	}
+	if elf.meetsRequirements(abilities) {
+		t.Error("INT 8 meets Minimum INT 9")
+	}
+	if !(OSEClass{}).meetsRequirements(abilities) {
+		t.Error("a class without requirements refused")
+	}
+}
+
+func TestLeavePointBuy(t *testing.T) {
+	tests := []struct {
+		name      string
+		abilities Abilities
+		presses   int
+		left      bool // moved on to the next step
+	}{
+		{"budget spent", Abilities{15, 14, 13, 12, 10, 8}, 1, true},
+		{"points left", Abilities{8, 8, 8, 8, 8, 8}, 1, false},
+		{"points left, confirmed", Abilities{8, 8, 8, 8, 8, 8}, 2, true},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, "", 1)
+		step := creationStepIndex(t, "Ability scores")
+		m.creation = &creationWizard{step: step, screen: creationPointBuy}
+		m.character.Abilities = tt.abilities
+		for i := 0; i < tt.presses; i++ {
+			m.leavePointBuy()
+		}
+		if left := m.creation.step > step; left != tt.left {
+			t.Errorf("%s: moved on %v, want %v (%s)", tt.name, left, tt.left, m.message)
+		}
+	}
+
+	// Buying more points asks again
+	m := combatModel(t, "", 1)
+	m.creation = &creationWizard{screen: creationPointBuy}
+	m.character.Abilities = Abilities{8, 8, 8, 8, 8, 8}
+	m.leavePointBuy()
+	m.adjustPointBuy(1)
+	m.leavePointBuy()
+	if m.creation.step != 0 {
+		t.Errorf("moved on with points left after buying more")
+	}
+}
+
+func TestChooseCreationProficiencies(t *testing.T) {
+	m := combatModel(t, "Fighter", 1)
+	step := creationStepIndex(t, "Languages and tools")
+	m.creation = &creationWizard{step: step}
+	dwarf, _ := m.srdData.race("Hill Dwarf")
+	m.grantFrom("race", dwarf.Grants)
+	m.grantFrom("background", Grants{LanguageChoices: 2})
+
+	m.chooseCreationProficiencies()
+	for i := 0; i < 3 && m.creation.step == step; i++ {
+		if m.picker == nil {
+			t.Fatalf("no picker after %d choices", i)
+		}
+		updated, _ := m.updatePicker(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+	}
+	if m.creation.step != step+1 {
+		t.Fatalf("still on step %d, want %d", m.creation.step, step+1)
+	}
+	want := []string{"Battleaxe", "Handaxe", "Light hammer", "Warhammer", "Common", "Dwarvish", "Smith's tools", "Elvish", "Giant"}
+	if !reflect.DeepEqual(m.proficiencies, want) {
+		t.Errorf("proficiencies = %q, want %q", m.proficiencies, want)
+	}
+	race, background := m.character.Granted["race"], m.character.Granted["background"]
+	if race.ToolChoices != 0 || background.LanguageChoices != 0 || !reflect.DeepEqual(background.Languages, []string{"Elvish", "Giant"}) {
+		t.Errorf("grants left: race %+v, background %+v", race, background)
+	}
+}
+
+func TestChooseRacialCantrip(t *testing.T) {
+	m := combatModel(t, "Fighter", 1)
+	m.creation = &creationWizard{step: creationStepIndex(t, "Spells")}
+	elf, _ := m.srdData.race("High Elf")
+	m.grantFrom("race", elf.Grants)
+
+	m.chooseStartingSpells()
+	if m.picker == nil {
+		t.Fatal("no cantrip picker for the high elf")
+	}
+	updated, _ := m.updatePicker(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if len(m.spells) != 1 || m.spells[0].Level != 0 || m.character.Granted["race"].CantripChoices != 0 {
+		t.Errorf("spells %+v, cantrip choices left %d, want one cantrip chosen", m.spells, m.character.Granted["race"].CantripChoices)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// rollDie rolls one die with the given number of sides
+func rollDie(sides int) int {
+	return rand.Intn(sides) + 1
+}
+
+// rollD20 rolls a d20, twice with advantage or disadvantage keeping the
+// higher or lower. The text shows both rolls when there were two: "7 (7, 15)"
+func rollD20(mode string) (int, string) {
+	first := rollDie(20)
+	if mode == "" {
+		return first, fmt.Sprintf("%d", first)
+	}
+	second := rollDie(20)
+	roll := max(first, second)
+	if mode == "disadvantage" {
+		roll = min(first, second)
+	}
+	return roll, fmt.Sprintf("%d (%d, %d)", roll, first, second)
+}
+
+// rollDice rolls count dice with the given number of sides
+func rollDice(count, sides int) []int {
+	rolls := make([]int, count)
+	for i := range rolls {
+		rolls[i] = rollDie(sides)
+	}
+	return rolls
+}
+
+// sum adds up rolls
+func sum(rolls []int) int {
+	total := 0
+	for _, roll := range rolls {
+		total += roll
+	}
+	return total
+}
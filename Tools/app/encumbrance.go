@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Encumbrance follows Gameplay/Abilities.md (Lifting and Carrying, and the
+// variant) for 5e, and 5. Adventures/2. Time, Weight, Movement.md for OSE,
+// whose two options are both optional rules. Weights are kept in pounds
+// and shown in coins for OSE, where ten coins make a pound
+
+// Character.Encumbrance values. "" is plain carrying capacity for 5e and
+// no tracking for OSE
+const (
+	encumbranceVariant  = "variant"  // 5e variant encumbrance
+	encumbranceBasic    = "basic"    // OSE option 1: treasure and armor
+	encumbranceDetailed = "detailed" // OSE option 2: everything significant
+)
+
+// weightPattern matches the number of an SRD weight: "5 lb.", "1/2 lb.",
+// "1½ lb.", "5 lb. (full)", or "50 coins"
+var weightPattern = regexp.MustCompile(`^(\d+)?(?:\s*(½|¼|/\d+))?\s*(lb|coin)`)
+
+const (
+	coinsPerPound5e    = 50 // Equipment/Coinage.md
+	coinsPerPoundOSE   = 10
+	oseMaxLoad         = 1600 // coins
+	oseGearWeight      = 80   // coins, for miscellaneous adventuring gear
+	oseTreasureCarried = 100  // coins of treasure the sheet counts as significant; the rules leave it to the referee
+)
+
+// oseTreasureWeights are the treasure table's weights in coins for the
+// magic item types it lists
+var oseTreasureWeights = map[string]int{
+	"Potion": 10,
+	"Rod":    20,
+	"Scroll": 1,
+	"Staff":  40,
+	"Wand":   10,
+}
+
+// parseWeight reads a weight string as pounds, 0 when it has none
+func parseWeight(weight string) float64 {
+	match := weightPattern.FindStringSubmatch(strings.TrimSpace(weight))
+	if match == nil {
+		return 0
+	}
+	pounds, _ := strconv.ParseFloat(match[1], 64)
+	switch fraction := match[2]; {
+	case fraction == "½":
+		pounds += 0.5
+	case fraction == "¼":
+		pounds += 0.25
+	case strings.HasPrefix(fraction, "/"):
+		divisor, _ := strconv.ParseFloat(fraction[1:], 64)
+		if divisor > 0 {
+			pounds /= divisor
+		}
+	}
+	if match[3] == "coin" {
+		pounds /= coinsPerPoundOSE
+	}
+	return pounds
+}
+
+// Encumbrance is what the character carries against what it can
+type Encumbrance struct {
+	Load     float64 // in Unit
+	Capacity float64 // the most the character can carry and still move, 0 when untracked
+	Unit     string  // "lb." or "coins"
+	Status   string  // "", "encumbered", "heavily encumbered", "carrying treasure", "over capacity", "cannot move"
+	Speed    int     // walking speed in feet after the load, -1 when the load doesn't change it
+}
+
+// overloaded reports whether the load is past what the character can carry
+func (e Encumbrance) overloaded() bool {
+	return e.Status == "over capacity" || e.Status == "cannot move"
+}
+
+// encumbrance works out the character's load under the ruleset's rules
+func (m Model) encumbrance() Encumbrance {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		return m.oseEncumbrance()
+	}
+
+	load := float64(m.coinCount()) / coinsPerPound5e
+	for _, item := range m.equipment {
+		load += parseWeight(item.Weight) * float64(max(item.Quantity, 1))
+	}
+	for _, weapon := range m.weapons {
+		load += parseWeight(weapon.Weight)
+	}
+
+	strength := float64(m.character.Abilities.Strength) * sizeCarryingFactor(m.character.Size)
+	e := Encumbrance{Load: load, Capacity: strength * 15, Unit: "lb.", Speed: -1}
+	switch {
+	case load > strength*30:
+		e.Status, e.Speed = "cannot move", 0
+	case load > strength*15:
+		e.Status, e.Speed = "over capacity", 5
+	case m.character.Encumbrance != encumbranceVariant:
+	case load > strength*10:
+		e.Status, e.Speed = "heavily encumbered", max(m.character.Speed-20, 0)
+	case load > strength*5:
+		e.Status, e.Speed = "encumbered", max(m.character.Speed-10, 0)
+	}
+	return e
+}
+
+// sizeCarryingFactor doubles carrying capacity for each size above Medium
+// and halves it for Tiny creatures
+func sizeCarryingFactor(size string) float64 {
+	switch strings.ToLower(size) {
+	case "tiny":
+		return 0.5
+	case "large":
+		return 2
+	case "huge":
+		return 4
+	case "gargantuan":
+		return 8
+	}
+	return 1
+}
+
+// oseEncumbrance counts treasure under both options, and armor at the OSE
+// armor table's weights, weapons and 80 coins for any other gear under the
+// detailed one. Movement is in feet
+// per round, a third of the exploration rate
+func (m Model) oseEncumbrance() Encumbrance {
+	e := Encumbrance{Unit: "coins", Speed: -1}
+	if m.character.Encumbrance != encumbranceBasic && m.character.Encumbrance != encumbranceDetailed {
+		return e
+	}
+	e.Capacity = oseMaxLoad
+
+	treasure := m.coinCount()
+	armor, gear := "", false
+	var equipment float64
+	for _, item := range m.equipment {
+		if magic, ok := m.srdData.magicItem(item.Name); ok {
+			if weight, ok := oseTreasureWeights[magic.Type]; ok {
+				treasure += weight * max(item.Quantity, 1)
+				continue
+			}
+		}
+		if oseArmor, _, ok := findOSEArmor(item.Name); ok {
+			if item.Equipped && oseArmor.Name != "Shield" && armor != "heavy" {
+				armor = "light"
+				if oseArmor.Heavy {
+					armor = "heavy"
+				}
+			}
+			equipment += float64(oseArmor.Weight * max(item.Quantity, 1))
+			continue
+		}
+		// Armor the OSE table lacks weighs what the 5e one says
+		if srdArmor, _, ok := m.srdData.armor(item.Name); ok {
+			if item.Equipped && srdArmor.Category != "shield" && armor != "heavy" {
+				armor = "light"
+				if srdArmor.Category != "light" {
+					armor = "heavy"
+				}
+			}
+			equipment += parseWeight(item.Weight) * coinsPerPoundOSE * float64(max(item.Quantity, 1))
+			continue
+		}
+		gear = true
+	}
+	for _, weapon := range m.weapons {
+		equipment += parseWeight(weapon.Weight) * coinsPerPoundOSE
+	}
+
+	e.Load = float64(treasure)
+	if m.character.Encumbrance == encumbranceBasic {
+		rates := map[string][]int{"": {40, 30}, "light": {30, 20}, "heavy": {20, 10}}[armor]
+		e.Speed = rates[0]
+		if treasure >= oseTreasureCarried {
+			e.Status, e.Speed = "carrying treasure", rates[1]
+		}
+	} else {
+		e.Load += equipment
+		if gear {
+			e.Load += oseGearWeight
+		}
+		switch {
+		case e.Load <= 400:
+			e.Speed = 40
+		case e.Load <= 600:
+			e.Status, e.Speed = "encumbered", 30
+		case e.Load <= 800:
+			e.Status, e.Speed = "encumbered", 20
+		default:
+			e.Status, e.Speed = "heavily encumbered", 10
+		}
+	}
+	if e.Load > oseMaxLoad {
+		e.Status, e.Speed = "cannot move", 0
+	}
+	return e
+}
+
+// coinCount is the number of coins the character carries, whatever their
+// denomination
+func (m Model) coinCount() int {
+	c := m.character.Currency
+	return c.CP + c.SP + c.EP + c.GP + c.PP
+}
+
+// encumbranceEffects are the heavily encumbered variant's disadvantage on
+// attacks and on Strength, Dexterity and Constitution checks and saves
+func (m Model) encumbranceEffects() []ConditionEffect {
+	if m.character.Encumbrance != encumbranceVariant || m.encumbrance().Status != "heavily encumbered" {
+		return nil
+	}
+	effects := []ConditionEffect{{Type: effectAttackDisadvantage}}
+	for _, ability := range []string{"Strength", "Dexterity", "Constitution"} {
+		effects = append(effects,
+			ConditionEffect{Type: effectCheckDisadvantage, Ability: ability},
+			ConditionEffect{Type: effectSaveDisadvantage, Ability: ability})
+	}
+	return effects
+}
+
+// openEncumbrancePicker chooses the ruleset's encumbrance rule
+func (m *Model) openEncumbrancePicker() {
+	rules := []string{"", encumbranceVariant}
+	options := []string{"Carrying capacity", "Variant encumbrance"}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		rules = []string{"", encumbranceBasic, encumbranceDetailed}
+		options = []string{"Not tracked", "Basic encumbrance", "Detailed encumbrance"}
+	}
+	m.picker = &catalogPicker{
+		title:   "Encumbrance",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.character.Encumbrance = rules[index]
+			m.message = "Encumbrance: " + options[index]
+		},
+	}
+}
+
+// renderEncumbrance is the load line of the Equipment tab, flagged when
+// the character carries more than it can
+func (m Model) renderEncumbrance() string {
+	e := m.encumbrance()
+	if e.Capacity == 0 {
+		return ""
+	}
+	view := fmt.Sprintf("Load: %s / %s %s", formatWeight(e.Load), formatWeight(e.Capacity), e.Unit)
+	if e.Status != "" {
+		view += " (" + e.Status + ")"
+	}
+	if e.overloaded() {
+		view = warningStyle.Render(view)
+	}
+	return view + "\n"
+}
+
+// formatWeight rounds a weight to two places, dropping the decimals from
+// whole weights
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(math.Round(weight*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestParseWeight(t *testing.T) {
+	tests := []struct {
+		weight string
+		want   float64
+	}{
+		{"5 lb.", 5},
+		{"1½ lb.", 1.5},
+		{"½ lb.", 0.5},
+		{"1/4 lb.", 0.25},
+		{"1/2 lb.", 0.5},
+		{"5 lb. (full)", 5},
+		{"50 coins", 5},
+		{"—", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseWeight(tt.weight); got != tt.want {
+			t.Errorf("parseWeight(%q) = %v, want %v", tt.weight, got, tt.want)
+		}
+	}
+}
+
+func TestOSEEncumbrance(t *testing.T) {
+	tests := []struct {
+		name   string
+		option string
+		worn   []string
+		load   float64
+		speed  int
+	}{
+		{"chainmail weighs 400 coins", encumbranceDetailed, []string{"Chain mail"}, 400, 40},
+		{"plate mail and shield", encumbranceDetailed, []string{"Plate mail", "Shield"}, 600, 30},
+		{"basic, light armor", encumbranceBasic, []string{"Leather"}, 0, 30},
+		{"basic, heavy armor", encumbranceBasic, []string{"Chainmail", "Shield"}, 0, 20},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, "Fighter", 1)
+		ruleset := oseRuleset{classes: m.srdData.OSEClasses}
+		m.rulesets = []Ruleset{ruleset}
+		m.character.Ruleset = ruleset.ID()
+		m.character.Encumbrance = tt.option
+		for _, name := range tt.worn {
+			// The 5e weight, which OSE ignores
+			m.equipment = append(m.equipment, Item{Name: name, Quantity: 1, Equipped: true, Weight: "55 lb."})
+		}
+		if e := m.oseEncumbrance(); e.Load != tt.load || e.Speed != tt.speed {
+			t.Errorf("%s: load %v, speed %d, want %v, %d", tt.name, e.Load, e.Speed, tt.load, tt.speed)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Grants is what a race, background or feat gives a character. Choices
+// ("two skills of your choice") are counted but left to the player
+type Grants struct {
+	AbilityIncreases map[string]int `json:"ability_increases,omitempty"` // "Constitution": 2
+	AbilityChoices   int            `json:"ability_choices,omitempty"`   // further +1s to abilities of the player's choice
+	Skills           []string       `json:"skills,omitempty"`
+	SkillChoices     int            `json:"skill_choices,omitempty"`
+	Proficiencies    []string       `json:"proficiencies,omitempty"` // weapons, armor and tools
+	ToolChoices      int            `json:"tool_choices,omitempty"`
+	ToolOptions      []string       `json:"tool_options,omitempty"` // the tools to choose from, any when empty
+	Languages        []string       `json:"languages,omitempty"`
+	LanguageChoices  int            `json:"language_choices,omitempty"`
+	CantripChoices   int            `json:"cantrip_choices,omitempty"`
+	CantripList      string         `json:"cantrip_list,omitempty"` // the class spell list they come from: "Wizard"
+	Equipment        []string       `json:"equipment,omitempty"`    // "5 sticks of incense"
+	Gold             int            `json:"gold,omitempty"`
+}
+
+var grantItemPattern = regexp.MustCompile(`(?i)^(?:(\d+)|a set of|an?|one) (.+?)(?: \((.*)\))?$`)
+
+// choices lists what the player still has to pick: "2 skills, 1 tool,
+// 1 language, 1 wizard cantrip"
+func (g Grants) choices() string {
+	var choices []string
+	if g.AbilityChoices > 0 {
+		choices = append(choices, plural(g.AbilityChoices, "ability increase"))
+	}
+	if g.SkillChoices > 0 {
+		choices = append(choices, plural(g.SkillChoices, "skill"))
+	}
+	if g.ToolChoices > 0 {
+		choices = append(choices, plural(g.ToolChoices, "tool"))
+	}
+	if g.LanguageChoices > 0 {
+		choices = append(choices, plural(g.LanguageChoices, "language"))
+	}
+	if g.CantripChoices > 0 {
+		choices = append(choices, plural(g.CantripChoices, strings.TrimSpace(strings.ToLower(g.CantripList)+" cantrip")))
+	}
+	return strings.Join(choices, ", ")
+}
+
+// plural renders "1 skill" or "2 skills"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// proficiencies are the proficiencies and languages, which share the
+// Proficiencies tab
+func (g Grants) proficiencies() []string {
+	return append(append([]string(nil), g.Proficiencies...), g.Languages...)
+}
+
+// grantFrom applies what a source ("race", "background", "feat:Grappler")
+// gives, first taking back what the same source gave before
+func (m *Model) grantFrom(source string, g Grants) {
+	m.revokeFrom(source)
+	if m.character.Granted == nil {
+		m.character.Granted = make(map[string]Grants)
+	}
+	m.character.Granted[source] = g
+	m.grant(g)
+}
+
+// revokeFrom takes back what a source gave, as recorded when it was granted
+func (m *Model) revokeFrom(source string) {
+	if g, ok := m.character.Granted[source]; ok {
+		delete(m.character.Granted, source)
+		m.revoke(g)
+	}
+}
+
+// grant applies g to the character being edited
+func (m *Model) grant(g Grants) {
+	for ability, increase := range g.AbilityIncreases {
+		m.character.Abilities.add(ability, increase)
+	}
+	for _, name := range g.Skills {
+		m.setSkillProficient(name, true)
+	}
+	for _, proficiency := range g.proficiencies() {
+		if !containsFold(m.proficiencies, proficiency) {
+			m.proficiencies = append(m.proficiencies, proficiency)
+		}
+	}
+	for _, text := range g.Equipment {
+		m.equipment = append(m.equipment, m.grantItem(text))
+	}
+	m.character.Currency.GP += g.Gold
+	m.updateInputsFromCharacter()
+}
+
+// revoke takes back what grant gave. Skills, proficiencies and languages
+// another source still grants stay. Ones the character has from elsewhere
+// still go, since the sheet doesn't record where those came from
+func (m *Model) revoke(g Grants) {
+	for ability, increase := range g.AbilityIncreases {
+		m.character.Abilities.add(ability, -increase)
+	}
+	for _, name := range g.Skills {
+		if !m.skillGranted(name) {
+			m.setSkillProficient(name, false)
+		}
+	}
+	for _, proficiency := range g.proficiencies() {
+		if !m.proficiencyGranted(proficiency) {
+			m.proficiencies = removeFold(m.proficiencies, proficiency)
+		}
+	}
+	for _, text := range g.Equipment {
+		name := m.grantItem(text).Name
+		for i, item := range m.equipment {
+			if item.Name == name {
+				m.equipment = append(m.equipment[:i], m.equipment[i+1:]...)
+				break
+			}
+		}
+	}
+	m.character.Currency.GP = max(m.character.Currency.GP-g.Gold, 0)
+	m.updateInputsFromCharacter()
+}
+
+// setSkillProficient marks a skill proficient, adding it to the list if
+// needed, or clears the mark, keeping the skill's expertise and override
+func (m *Model) setSkillProficient(name string, proficient bool) {
+	for i, skill := range m.skillList {
+		if strings.EqualFold(skill.Name, name) {
+			m.skillList[i].Proficient = proficient
+			return
+		}
+	}
+	if proficient {
+		m.skillList = append(m.skillList, Skill{Name: name, Proficient: true})
+	}
+}
+
+// skillGranted reports whether any recorded source gives the skill
+func (m Model) skillGranted(name string) bool {
+	for _, grants := range m.character.Granted {
+		if containsFold(grants.Skills, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// proficiencyGranted reports whether any recorded source gives the
+// proficiency or language
+func (m Model) proficiencyGranted(name string) bool {
+	for _, grants := range m.character.Granted {
+		if containsFold(grants.proficiencies(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// grantItem turns "5 sticks of incense" or "a holy symbol (a gift...)" into
+// an inventory item, copying weight and cost from the SRD catalog when the
+// name matches an entry
+func (m Model) grantItem(text string) Item {
+	item := Item{Name: capitalize(text), Quantity: 1}
+	if match := grantItemPattern.FindStringSubmatch(text); match != nil {
+		if match[1] != "" {
+			item.Quantity, _ = strconv.Atoi(match[1])
+		}
+		item.Name = capitalize(match[2])
+		item.Description = match[3]
+	}
+	// The catalog lists "common clothes" as "Clothes, common"
+	names := []string{item.Name}
+	if words := strings.Fields(item.Name); len(words) == 2 {
+		names = append(names, words[1]+", "+words[0])
+	}
+	for _, equipment := range m.srdData.Equipment {
+		if containsFold(names, equipment.Name) {
+			catalog := itemFromEquipment(equipment)
+			catalog.Quantity = item.Quantity
+			if item.Description != "" {
+				catalog.Description = item.Description
+			}
+			return catalog
+		}
+	}
+	return item
+}
+
+// add raises an ability score by name ("Strength") or abbreviation ("STR")
+func (a *Abilities) add(ability string, n int) {
+	switch strings.ToUpper(ability) {
+	case "STRENGTH", "STR":
+		a.Strength += n
+	case "DEXTERITY", "DEX":
+		a.Dexterity += n
+	case "CONSTITUTION", "CON":
+		a.Constitution += n
+	case "INTELLIGENCE", "INT":
+		a.Intelligence += n
+	case "WISDOM", "WIS":
+		a.Wisdom += n
+	case "CHARISMA", "CHA":
+		a.Charisma += n
+	}
+}
+
+// containsFold reports whether list holds s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(entry, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFold drops the first entry equal to s, ignoring case
+func removeFold(list []string, s string) []string {
+	for i, entry := range list {
+		if strings.EqualFold(entry, s) {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+func TestGrantFrom(t *testing.T) {
+	m := Model{inputs: map[string]textinput.Model{}}
+	m.character.Abilities = Abilities{Strength: 10, Constitution: 12}
+	m.proficiencies = []string{"Light armor"}
+
+	dwarf := Grants{
+		AbilityIncreases: map[string]int{"Constitution": 2},
+		Skills:           []string{"History"},
+		Proficiencies:    []string{"battleaxe"},
+		Languages:        []string{"Common", "Dwarvish"},
+	}
+	m.grantFrom("race", dwarf)
+	if m.character.Abilities.Constitution != 14 {
+		t.Errorf("Constitution after the dwarf = %d, want 14", m.character.Abilities.Constitution)
+	}
+	if want := []string{"Light armor", "battleaxe", "Common", "Dwarvish"}; !reflect.DeepEqual(m.proficiencies, want) {
+		t.Errorf("proficiencies after the dwarf = %q, want %q", m.proficiencies, want)
+	}
+	if len(m.skillList) != 1 || !m.skillList[0].Proficient {
+		t.Errorf("skills after the dwarf = %+v, want History proficient", m.skillList)
+	}
+
+	// Choosing another race takes back the dwarf's grants first
+	m.grantFrom("race", Grants{AbilityIncreases: map[string]int{"Strength": 1}, Languages: []string{"Common"}})
+	if got := m.character.Abilities; got.Constitution != 12 || got.Strength != 11 {
+		t.Errorf("abilities after the human = %+v, want Str 11, Con 12", got)
+	}
+	if want := []string{"Light armor", "Common"}; !reflect.DeepEqual(m.proficiencies, want) {
+		t.Errorf("proficiencies after the human = %q, want %q", m.proficiencies, want)
+	}
+	if len(m.skillList) != 1 || m.skillList[0].Proficient {
+		t.Errorf("skills after the human = %+v, want History kept but not proficient", m.skillList)
+	}
+
+	m.revokeFrom("race")
+	if m.character.Abilities.Strength != 10 || len(m.character.Granted) != 0 {
+		t.Errorf("after revoking: Strength %d, granted %v", m.character.Abilities.Strength, m.character.Granted)
+	}
+}
+
+func TestRevokeKeepsGrantsFromElsewhere(t *testing.T) {
+	m := Model{inputs: map[string]textinput.Model{}}
+	m.grantFrom("race", Grants{Skills: []string{"Perception"}, Proficiencies: []string{"Longsword"}, Languages: []string{"Common", "Elvish"}})
+	m.grantFrom("background", Grants{Skills: []string{"perception", "Stealth"}, Proficiencies: []string{"longsword", "Thieves' tools"}, Languages: []string{"Common"}})
+
+	m.revokeFrom("background")
+	want := []Skill{{Name: "Perception", Proficient: true}, {Name: "Stealth"}}
+	if !reflect.DeepEqual(m.skillList, want) {
+		t.Errorf("skills after revoking the background = %+v, want %+v", m.skillList, want)
+	}
+	if want := []string{"Longsword", "Common", "Elvish"}; !reflect.DeepEqual(m.proficiencies, want) {
+		t.Errorf("proficiencies after revoking the background = %q, want %q", m.proficiencies, want)
+	}
+}
+
+func TestGrantsChoices(t *testing.T) {
+	tests := []struct {
+		grants Grants
+		want   string
+	}{
+		{Grants{}, ""},
+		{Grants{SkillChoices: 1}, "1 skill"},
+		{Grants{AbilityChoices: 2, SkillChoices: 2, LanguageChoices: 1}, "2 ability increases, 2 skills, 1 language"},
+		{Grants{ToolChoices: 1, CantripChoices: 1, CantripList: "Wizard"}, "1 tool, 1 wizard cantrip"},
+	}
+	for _, tt := range tests {
+		if got := tt.grants.choices(); got != tt.want {
+			t.Errorf("%+v.choices() = %q, want %q", tt.grants, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// runImport regenerates the srd_*.json files from the Rules wiki. Writing
+// them to ./srd updates the dataset embedded at the next build (go generate);
+// anywhere else they act as overrides when listed in SRD_DATA_PATH, see
+// loadSRDData.
+// Usage: app import [-rules ../../Rules] [-out srd]
+// The Rules folder defaults to RULES_PATH or the repository's, see wikiDir
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	rulesDir := flags.String("rules", wikiDir(rulesPathEnv, "Rules"), "path to the Rules wiki folder")
+	outDir := flags.String("out", "srd", "directory to write the srd_*.json files to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	equipment, issues, err := importEquipment(equipmentDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("equipment", issues)
+	if err := writeJSON(*outDir, "srd_equipment.json", equipment); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d equipment items\n", len(equipment))
+
+	weapons, issues, err := importWeapons(weaponsFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("weapons", issues)
+	if err := writeJSON(*outDir, "srd_weapons.json", weapons); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d weapons\n", len(weapons))
+
+	armor, issues, err := importArmor(armorFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("armor", issues)
+	if err := writeJSON(*outDir, "srd_armor.json", armor); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d armor\n", len(armor))
+
+	spells, issues, err := importSpells(spellsDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("spells", issues)
+	issues, err = assignSpellClasses(spells, spellListsFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("spell lists", issues)
+	if err := writeJSON(*outDir, "srd_spells.json", spells); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d spells\n", len(spells))
+
+	monsters, issues, err := importMonsters(monstersDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("monsters", issues)
+	if err := writeJSON(*outDir, "srd_monsters.json", monsters); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d monsters\n", len(monsters))
+
+	oseMonsters, issues, err := importOSEMonsters(oseMonstersDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("OSE monsters", issues)
+	if err := writeJSON(*outDir, "srd_ose_monsters.json", oseMonsters); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d OSE monsters\n", len(oseMonsters))
+
+	var oseSpells []OSESpell
+	for _, dir := range oseSpellDirs(*rulesDir) {
+		spells, issues, err := importOSESpells(dir)
+		if err != nil {
+			return err
+		}
+		reportIssues("OSE spells", issues)
+		oseSpells = append(oseSpells, spells...)
+	}
+	if err := writeJSON(*outDir, "srd_ose_spells.json", oseSpells); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d OSE spells\n", len(oseSpells))
+
+	magicItems, issues, err := importMagicItems(magicItemsDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("magic items", issues)
+	if err := writeJSON(*outDir, "srd_magic_items.json", magicItems); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d magic items\n", len(magicItems))
+
+	oseClasses, issues, err := importOSEClasses(oseClassesDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("OSE classes", issues)
+	if err := writeJSON(*outDir, "srd_ose_classes.json", oseClasses); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d OSE classes\n", len(oseClasses))
+
+	conditions, issues, err := importConditions(conditionsFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("conditions", issues)
+	if err := writeJSON(*outDir, "srd_conditions.json", conditions); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d conditions\n", len(conditions))
+
+	races, issues, err := importRaces(racesDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("races", issues)
+	if err := writeJSON(*outDir, "srd_races.json", races); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d races\n", len(races))
+
+	backgrounds, issues, err := importBackgrounds(backgroundsFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("backgrounds", issues)
+	if err := writeJSON(*outDir, "srd_backgrounds.json", backgrounds); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d backgrounds\n", len(backgrounds))
+
+	feats, issues, err := importFeats(featsFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("feats", issues)
+	if err := writeJSON(*outDir, "srd_feats.json", feats); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d feats\n", len(feats))
+
+	classes, issues, err := importClasses(classesDir(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("classes", issues)
+	if err := writeJSON(*outDir, "srd_classes.json", classes); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d classes\n", len(classes))
+
+	multiclassSlots, issues, err := importMulticlassSlots(multiclassingFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("multiclass slots", issues)
+	if err := writeJSON(*outDir, "srd_multiclass_slots.json", multiclassSlots); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d multiclass caster levels\n", len(multiclassSlots))
+
+	multiclassClasses, issues, err := importMulticlassClasses(multiclassingFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("multiclassing", issues)
+	if err := writeJSON(*outDir, "srd_multiclassing.json", multiclassClasses); err != nil {
+		return err
+	}
+	fmt.Printf("Imported multiclassing rules for %d classes\n", len(multiclassClasses))
+
+	advancement, issues, err := importAdvancement(advancementFile(*rulesDir))
+	if err != nil {
+		return err
+	}
+	reportIssues("advancement", issues)
+	if err := writeJSON(*outDir, "srd_advancement.json", advancement); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d experience levels\n", len(advancement))
+
+	return nil
+}
+
+func reportIssues(dataset string, issues []ImportIssue) {
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", dataset, issue)
+	}
+}
+
+func writeJSON(dir, name string, v interface{}) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %v", name, err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, name), data, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", name, err)
+	}
+
+	return nil
+}
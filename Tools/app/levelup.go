@@ -0,0 +1,492 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Experience and levelling up. 5e characters share the advancement table
+// in Characterizations/Beyond 1st Level.md; each OSE class has its own,
+// with a bonus or penalty for its prime requisites
+
+// xpForLevel is the XP a character needs to reach a level. ok is false
+// past the last level of the table, or of the OSE class
+func (m Model) xpForLevel(level int) (int, bool) {
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		class, _ := ruleset.class(m.character.Class)
+		for _, row := range class.Levels {
+			if row.Level == level {
+				return row.XP, true
+			}
+		}
+		return 0, false
+	}
+	row, ok := m.srdData.advancement(level)
+	return row.XP, ok
+}
+
+// canLevelUp reports whether the character has the XP for the next level
+func (m Model) canLevelUp() bool {
+	need, ok := m.xpForLevel(max(m.character.Level, 1) + 1)
+	return ok && m.character.XP >= need
+}
+
+// xpModifier is the OSE class's prime requisite adjustment to XP earned,
+// as a percentage. 5e has none
+func (m Model) xpModifier() int {
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		if class, ok := ruleset.class(m.character.Class); ok {
+			return class.xpModifier(m.character.Abilities)
+		}
+	}
+	return 0
+}
+
+// awardXP adds XP earned, adjusted for prime requisites. An OSE character
+// advances at most one level a session, so XP that would go further is
+// lost, leaving them 1 XP short of the level after next
+func (m *Model) awardXP(xp int) {
+	modifier := m.xpModifier()
+	gained := xp + xp*modifier/100
+	m.character.XP += gained
+
+	m.message = fmt.Sprintf("+%d XP", gained)
+	if modifier != 0 {
+		m.message += fmt.Sprintf(" (%+d%% prime requisite)", modifier)
+	}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		if limit, ok := m.xpForLevel(max(m.character.Level, 1) + 2); ok && m.character.XP >= limit {
+			m.character.XP = limit - 1
+			m.message += ", past one level a session is lost"
+		}
+	}
+	m.message += fmt.Sprintf(", %d XP in all", m.character.XP)
+	if m.canLevelUp() {
+		m.message += "; ctrl+u to level up"
+	}
+}
+
+// openXPPrompt asks for the XP earned, for awardXP
+func (m *Model) openXPPrompt() {
+	input := textinput.New()
+	input.Placeholder = "XP earned"
+	input.CharLimit = 10
+	input.Focus()
+	m.xpPrompt = &input
+}
+
+func (m Model) updateXPPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.xpPrompt = nil
+		m.message = "Cancelled"
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.xpPrompt.Value())
+		xp, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+		if err != nil || xp <= 0 {
+			m.message = fmt.Sprintf("Can't read XP %q", value)
+			return m, nil
+		}
+		m.xpPrompt = nil
+		m.awardXP(xp)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	*m.xpPrompt, cmd = m.xpPrompt.Update(msg)
+	return m, cmd
+}
+
+// levelUp takes the next level once the XP is there. A 5e character
+// chooses which class it goes to, or a new class they qualify for, then
+// walks through what the level brings: hit points, class features and a
+// subclass, an ability score improvement or feat, and spells. Cancelling a
+// step keeps the level and skips the rest, with the average hit points if
+// none were rolled
+func (m *Model) levelUp() {
+	level := max(m.character.Level, 1) + 1
+	need, ok := m.xpForLevel(level)
+	switch {
+	case !ok:
+		m.message = fmt.Sprintf("%s can't advance past level %d", m.character.Class, level-1)
+		return
+	case m.character.XP < need:
+		m.message = fmt.Sprintf("Level %d needs %d XP, %d to go", level, need, need-m.character.XP)
+		return
+	}
+
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		m.advanceClass(0)
+		return
+	}
+	classes := m.classLevels()
+	newClasses := m.multiclassOptions()
+	if len(classes) == 1 && len(newClasses) == 0 {
+		m.advanceClass(0)
+		return
+	}
+	var options []string
+	for _, class := range classes {
+		options = append(options, fmt.Sprintf("%s %d", class.Name, class.Level+1))
+	}
+	for _, rules := range newClasses {
+		options = append(options, fmt.Sprintf("New class: %s 1 (%s)", rules.Name, rules.prerequisite()))
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("Level %d: Which Class?", level),
+		options: options,
+		choose: func(m *Model, index int) {
+			if index < len(classes) {
+				m.advanceClass(index)
+				return
+			}
+			m.addClass(newClasses[index-len(classes)], func(m *Model) {
+				m.advanceClass(len(m.character.Classes))
+			})
+		},
+	}
+}
+
+// advanceClass raises the character level and the ith class's level
+func (m *Model) advanceClass(i int) {
+	before := *m
+	before.character.Classes = append([]CharacterClass(nil), m.character.Classes...)
+	m.character.Level = max(m.character.Level, 1) + 1
+	if i > 0 {
+		m.character.Classes[i-1].Level++
+		if m.character.Classes[i-1].Level == 1 {
+			before.character.Classes = before.character.Classes[:i-1]
+		}
+	}
+	m.updateInputsFromCharacter()
+
+	level := m.character.Level
+	var notes []string
+	note := func(format string, args ...interface{}) {
+		notes = append(notes, fmt.Sprintf(format, args...))
+	}
+	steps := []func(m *Model, then func(m *Model)){
+		func(m *Model, then func(m *Model)) { m.levelUpHitPoints(i, note, then) },
+		func(m *Model, then func(m *Model)) { m.levelUpFeatures(i, note, then) },
+		func(m *Model, then func(m *Model)) { m.levelUpAbilities(i, then) },
+		func(m *Model, then func(m *Model)) { m.levelUpSpells(before, note, then) },
+		func(m *Model, then func(m *Model)) {
+			m.character.Spells = m.spells
+			m.message = fmt.Sprintf("Level %d", level)
+			if i > 0 {
+				class := m.classLevels()[i]
+				m.message += fmt.Sprintf(" (%s %d)", class.Name, class.Level)
+			}
+			if len(notes) > 0 {
+				m.message += ": " + strings.Join(notes, "; ")
+			}
+		},
+	}
+	runSteps(m, steps)
+}
+
+// runSteps runs each step once the one before it calls then
+func runSteps(m *Model, steps []func(m *Model, then func(m *Model))) {
+	if len(steps) == 0 {
+		return
+	}
+	steps[0](m, func(m *Model) { runSteps(m, steps[1:]) })
+}
+
+// levelUpHitPoints rolls the ith class's hit die or takes its average.
+// OSE characters past 9th level gain a flat amount instead of a die
+func (m *Model) levelUpHitPoints(i int, note func(string, ...interface{}), then func(m *Model)) {
+	class := m.classLevels()[i]
+	die := m.classHitDie(class.Name)
+	n := class.Level // the class's dice after the character's first level
+	if i == 0 {
+		n--
+	}
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		die = m.hitDie()
+		oseClass, _ := ruleset.class(m.character.Class)
+		row, _ := oseClass.level(m.character.Level)
+		match := hitDicePattern.FindStringSubmatch(row.HitDice)
+		if match == nil {
+			then(m)
+			return
+		}
+		dice, _ := strconv.Atoi(match[1])
+		if dice < m.character.Level {
+			previous, _ := oseClass.level(m.character.Level - 1)
+			if previous.HitDice != row.HitDice {
+				note("hit dice %s", strings.TrimSuffix(row.HitDice, "*"))
+			}
+			then(m)
+			return
+		}
+		n = dice - 1
+	}
+	if die == 0 || n < 1 {
+		then(m)
+		return
+	}
+
+	con := m.ruleset().AbilityModifier(m.character.Abilities.Constitution)
+	average := die/2 + 1
+	m.picker = &catalogPicker{
+		title: fmt.Sprintf("Level %d: Hit Points", m.character.Level),
+		options: []string{
+			fmt.Sprintf("Roll 1d%d%+d", die, con),
+			fmt.Sprintf("Take the average (%d%+d)", average, con),
+		},
+		choose: func(m *Model, index int) {
+			if index == 0 {
+				roll := rollDice(1, die)[0]
+				m.recordHPRoll(i, n, roll)
+				note("+%d HP (rolled %d)", max(roll+con, 1), roll)
+			} else {
+				m.recordHPRoll(i, n, 0)
+				note("+%d HP", max(average+con, 1))
+			}
+			then(m)
+		},
+	}
+}
+
+// levelUpFeatures lists the class features the ith class's new level
+// brings, and has the character choose its subclass once they reach the
+// subclass's level. OSE characters note their new attack and saving throw
+// numbers
+func (m *Model) levelUpFeatures(i int, note func(string, ...interface{}), then func(m *Model)) {
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		level := m.character.Level
+		class, _ := ruleset.class(m.character.Class)
+		row, _ := class.level(level)
+		previous, _ := class.level(level - 1)
+		if row.THAC0 != previous.THAC0 {
+			note("THAC0 %d [%+d]", row.THAC0, row.AttackBonus)
+		}
+		if row.Saves != previous.Saves {
+			note("better saving throws")
+		}
+		then(m)
+		return
+	}
+
+	cl := m.classLevels()[i]
+	class, ok := m.srdData.class(cl.Name)
+	if !ok {
+		then(m)
+		return
+	}
+	if rules, ok := m.srdData.multiclassClass(cl.Name); ok && i > 0 && cl.Level == 1 && len(rules.Choices) > 0 {
+		note("also choose %s", strings.Join(rules.Choices, ", "))
+	}
+	var features []string
+	for _, feature := range class.featuresAt(cl.Level, cl.Subclass) {
+		if slices.Contains(feature.Levels, cl.Level) {
+			features = append(features, feature.Name)
+		}
+	}
+	if len(features) > 0 {
+		note("%s", strings.Join(features, ", "))
+	}
+	if len(class.Subclasses) > 0 && cl.Subclass == "" && cl.Level >= class.subclassLevel() {
+		m.openClassSubclassPicker(i)
+		m.afterPicker(then)
+		return
+	}
+	then(m)
+}
+
+// levelUpAbilities offers the Ability Score Improvement the ith class's
+// new level may bring: +2 to one ability or +1 to two, none past 20, or a
+// feat instead. The increases are granted by the level, so they show where
+// they came from
+func (m *Model) levelUpAbilities(i int, then func(m *Model)) {
+	level := m.character.Level
+	cl := m.classLevels()[i]
+	class, ok := m.srdData.class(cl.Name)
+	if _, ose := m.ruleset().(oseRuleset); ose || !ok {
+		then(m)
+		return
+	}
+	improves := false
+	for _, feature := range class.Features {
+		if feature.Name == "Ability Score Improvement" && slices.Contains(feature.Levels, cl.Level) {
+			improves = true
+		}
+	}
+	if !improves {
+		then(m)
+		return
+	}
+
+	source := fmt.Sprintf("level %d", level)
+	raise := func(increases map[string]int) func(m *Model) {
+		return func(m *Model) {
+			m.grantFrom(source, Grants{AbilityIncreases: increases})
+			then(m)
+		}
+	}
+	options := []string{"+2 to one ability", "+1 to two abilities"}
+	if len(m.srdData.Feats) > 0 {
+		options = append(options, "A feat instead")
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("Level %d: Ability Score Improvement", level),
+		options: options,
+		choose: func(m *Model, index int) {
+			switch index {
+			case 0:
+				m.pickAbility(level, 2, "", func(m *Model, ability string) {
+					raise(map[string]int{ability: 2})(m)
+				})
+			case 1:
+				m.pickAbility(level, 1, "", func(m *Model, first string) {
+					m.pickAbility(level, 1, first, func(m *Model, second string) {
+						raise(map[string]int{first: 1, second: 1})(m)
+					})
+				})
+			default:
+				m.openFeatPicker()
+				m.afterPicker(then)
+			}
+		},
+	}
+}
+
+// pickAbility chooses an ability that can take the increase without going
+// past 20, other than one already chosen
+func (m *Model) pickAbility(level, increase int, chosen string, then func(m *Model, ability string)) {
+	var options []string
+	for _, ability := range abilityNames {
+		if ability != chosen && m.character.Abilities.score(ability)+increase <= 20 {
+			options = append(options, fmt.Sprintf("%s (%d)", ability, m.character.Abilities.score(ability)))
+		}
+	}
+	if len(options) == 0 {
+		m.message = "No ability can go higher"
+		return
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("Level %d: +%d to an ability", level, increase),
+		options: options,
+		choose: func(m *Model, index int) {
+			then(m, strings.Fields(options[index])[0])
+		},
+	}
+}
+
+// levelUpSpells notes the new spell slots, then has the character learn
+// what the level brings: a wizard's free spellbook spells, six with a
+// first wizard level and two after, and the cantrips and spells known of the classes that know a set number. OSE
+// spell books fill through research and mentors, see learnOptions
+func (m *Model) levelUpSpells(before Model, note func(string, ...interface{}), then func(m *Model)) {
+	if slots := m.spellSlots(); !slices.Equal(slots, before.spellSlots()) {
+		note("spell slots %s", slotsText(slots))
+	}
+	if slots, level := m.pactSlots(); level > 0 {
+		if oldSlots, oldLevel := before.pactSlots(); slots != oldSlots || level != oldLevel {
+			note("%d pact slots of %s level", slots, ordinal(level))
+		}
+	}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		then(m)
+		return
+	}
+
+	steps := []func(m *Model, then func(m *Model)){}
+	previous := make(map[string]ClassLevel)
+	for _, caster := range before.spellcasters() {
+		previous[caster.Class.Name] = caster.Row
+	}
+	addSpell := func(class string) func(m *Model, s Spell) {
+		return func(m *Model, s Spell) {
+			s.Class = class
+			m.spells = append(m.spells, s)
+		}
+	}
+	for _, caster := range m.spellcasters() {
+		caster, old := caster, previous[caster.Class.Name]
+		title := fmt.Sprintf("Level %d: ", m.character.Level)
+		if n := caster.Row.CantripsKnown - old.CantripsKnown; n > 0 {
+			steps = append(steps, func(m *Model, then func(m *Model)) {
+				m.pickSpells(title+"Cantrip", n, false, func(m *Model) []Spell {
+					return m.classSpellChoices(caster.Class.Name, 0, 0)
+				}, addSpell(caster.Class.Name), then)
+			})
+		}
+		if n := caster.Row.SpellsKnown - old.SpellsKnown; n > 0 {
+			steps = append(steps, func(m *Model, then func(m *Model)) {
+				m.pickSpells(title+"Spell known", n, false, func(m *Model) []Spell {
+					return m.classSpellChoices(caster.Class.Name, 1, caster.maxSpellLevel())
+				}, addSpell(caster.Class.Name), then)
+			})
+		}
+		if caster.Class.Spellbook && caster.Row.Level != old.Level {
+			steps = append(steps, func(m *Model, then func(m *Model)) {
+				free := m.freeSpellbookSpells() - m.spellbookCount(func(e SpellbookEntry) bool { return e.Learned == learnedLevelUp })
+				if caster.Level > 1 {
+					free = min(free, 2)
+				}
+				m.pickSpells(title+"Spellbook spell", free, false, (*Model).learnableSpells, func(m *Model, s Spell) {
+					m.learnSpell(SpellbookEntry{Name: s.Name, Level: s.Level, Learned: learnedLevelUp})
+				}, then)
+			})
+		}
+	}
+	steps = append(steps, func(m *Model, _ func(m *Model)) { then(m) })
+	runSteps(m, steps)
+}
+
+// maxSpellLevel is the highest spell level the caster has slots for
+func (c spellcaster) maxSpellLevel() int {
+	top := c.Row.PactSlotLevel
+	for level, slots := range c.Row.SpellSlots {
+		if slots > 0 {
+			top = max(top, level+1)
+		}
+	}
+	return top
+}
+
+// slotsText lists slots per spell level: "1st 4, 2nd 3, 3rd 2"
+func slotsText(slots []int) string {
+	var parts []string
+	for level, n := range slots {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", ordinal(level+1), n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderExperience is the XP line of the Basic Info tab, with the award
+// prompt when it's open
+func (m Model) renderExperience() string {
+	view := fmt.Sprintf("XP: %d", m.character.XP)
+	if need, ok := m.xpForLevel(max(m.character.Level, 1) + 1); ok {
+		view += fmt.Sprintf(" (level %d at %d)", max(m.character.Level, 1)+1, need)
+	} else {
+		view += " (highest level)"
+	}
+	if modifier := m.xpModifier(); modifier != 0 {
+		view += fmt.Sprintf(" %+d%% prime requisite", modifier)
+	}
+	if m.mode == "edit" {
+		view += " (ctrl+p to award"
+		if m.canLevelUp() {
+			view += ", ctrl+u to level up"
+		}
+		view += ")"
+	}
+	view += "\n"
+	if m.xpPrompt != nil {
+		view += "Award XP: " + m.xpPrompt.View() + "\n"
+	}
+	return view
+}
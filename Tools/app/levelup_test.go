@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestXPForLevel(t *testing.T) {
+	tests := []struct {
+		ose   bool
+		class string
+		level int
+		want  int
+		ok    bool
+	}{
+		{false, "Fighter", 2, 300, true},
+		{false, "Wizard", 5, 6500, true},
+		{false, "Wizard", 20, 355000, true},
+		{false, "Wizard", 21, 0, false},
+		{true, "Fighter", 2, 2000, true},
+		{true, "Magic-User", 3, 5000, true},
+		{true, "Halfling", 9, 0, false},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, tt.ose, tt.class, 1)
+		got, ok := m.xpForLevel(tt.level)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s level %d: xpForLevel = %d, %v, want %d, %v", tt.class, tt.level, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOSEXPModifier(t *testing.T) {
+	tests := []struct {
+		class     string
+		abilities Abilities
+		want      int
+	}{
+		{"Fighter", Abilities{Strength: 5}, -20},
+		{"Fighter", Abilities{Strength: 8}, -10},
+		{"Fighter", Abilities{Strength: 12}, 0},
+		{"Fighter", Abilities{Strength: 13}, 5},
+		{"Fighter", Abilities{Strength: 16}, 10},
+		{"Elf", Abilities{Intelligence: 13, Strength: 13}, 5},
+		{"Elf", Abilities{Intelligence: 16, Strength: 13}, 10},
+		{"Elf", Abilities{Intelligence: 16, Strength: 12}, 0},
+		{"Halfling", Abilities{Dexterity: 13, Strength: 9}, 5},
+		{"Halfling", Abilities{Dexterity: 13, Strength: 13}, 10},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, true, tt.class, 1)
+		m.character.Abilities = tt.abilities
+		if got := m.xpModifier(); got != tt.want {
+			t.Errorf("%s with %+v: xpModifier = %d, want %d", tt.class, tt.abilities, got, tt.want)
+		}
+	}
+}
+
+func TestAwardXP(t *testing.T) {
+	tests := []struct {
+		name     string
+		ose      bool
+		class    string
+		strength int
+		award    int
+		want     int
+		levelUp  bool
+	}{
+		{"5e", false, "Fighter", 10, 1000, 1000, true},
+		{"OSE prime requisite bonus", true, "Fighter", 16, 1000, 1100, false},
+		{"OSE one level a session", true, "Fighter", 10, 10000, 3999, true},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, tt.ose, tt.class, 1)
+		m.character.Abilities.Strength = tt.strength
+		m.awardXP(tt.award)
+		if m.character.XP != tt.want || m.canLevelUp() != tt.levelUp {
+			t.Errorf("%s: %d XP, can level up %v, want %d, %v", tt.name, m.character.XP, m.canLevelUp(), tt.want, tt.levelUp)
+		}
+	}
+}
@@ -25,12 +25,16 @@ type SRDEquipment struct {
 }
 
 type SRDWeapon struct {
-	Name        string `json:"name"`
-	Cost        string `json:"cost"`
-	Damage      string `json:"damage"`
-	Weight      string `json:"weight"`
-	Properties  string `json:"properties"`
-	Description string `json:"description"`
+	Name        string           `json:"name"`
+	Cost        string           `json:"cost"`
+	Damage      string           `json:"damage"`
+	DamageDice  string           `json:"damage_dice"`
+	DamageType  string           `json:"damage_type"`
+	Weight      string           `json:"weight"`
+	Properties  WeaponProperties `json:"properties"`
+	Category    string           `json:"category"` // simple or martial
+	Ranged      bool             `json:"ranged"`
+	Description string           `json:"description"`
 }
 
 type SRDSpell struct {
@@ -42,21 +46,46 @@ type SRDSpell struct {
 	Components  string `json:"components"`
 	Duration    string `json:"duration"`
 	Description string `json:"description"`
+	HigherLevel string `json:"higher_level"`
+	Ritual      bool   `json:"ritual"`
 	Classes     string `json:"classes"`
 }
 
 type SRDData struct {
 	Equipment []SRDEquipment `json:"equipment"`
 	Weapons   []SRDWeapon    `json:"weapons"`
+	Armor     []SRDArmor     `json:"armor"`
 	Spells    []SRDSpell     `json:"spells"`
+	Monsters  []SRDMonster   `json:"monsters"`
+	OSEMonsters []OSEMonster `json:"ose_monsters"`
+	OSESpells []OSESpell `json:"ose_spells"`
+	MagicItems []SRDMagicItem `json:"magic_items"`
+	OSEClasses []OSEClass `json:"ose_classes"`
+	Conditions []SRDCondition `json:"conditions"`
+	Races []SRDRace `json:"races"`
+	Backgrounds []SRDBackground `json:"backgrounds"`
+	Feats []SRDFeat `json:"feats"`
+	Classes []ClassDefinition `json:"classes"`
+	MulticlassSlots []MulticlassSlots `json:"multiclass_slots"`
+	MulticlassClasses []MulticlassClass `json:"multiclass_classes"`
+	Advancement []AdvancementLevel `json:"advancement"`
+
+	Sources map[string]string `json:"-"` // dataset name -> "embedded + dir/srd_x.json"
 }
 
 // Character represents a D&D character
 type Character struct {
 	Name          string     `json:"name"`
+	Ruleset       string     `json:"ruleset"` // Ruleset ID, "5e" when empty
 	Race          string     `json:"race"`
+	Size          string     `json:"size"`
+	Speed         int        `json:"speed"` // walking speed in feet
 	Class         string     `json:"class"`
-	Level         int        `json:"level"`
+	Subclass      string     `json:"subclass"`
+	Level         int        `json:"level"` // character level, all classes together
+	Classes       []CharacterClass `json:"classes,omitempty"` // classes taken by multiclassing, after Class
+	XP            int        `json:"xp"`
+	HPRolls       []int      `json:"hp_rolls,omitempty"` // Class's hit die rolled at each level after the first, 0 for the average
 	Abilities     Abilities  `json:"abilities"`
 	Skills        []Skill    `json:"skills"`
 	Equipment     []Item     `json:"equipment"`
@@ -66,6 +95,14 @@ type Character struct {
 	Proficiencies []string   `json:"proficiencies"`
 	Currency      Currency   `json:"currency"`
 	Equipped      Equipped   `json:"equipped"`
+	Conditions    []ActiveCondition `json:"conditions"`
+	Feats         []string   `json:"feats"`
+	Granted       map[string]Grants `json:"granted,omitempty"` // what each race, background or feat gave, so it can be taken back
+	Combat        Combat     `json:"combat"`
+	SlotsUsed     SlotsUsed  `json:"slots_used"`
+	Spellbook     []SpellbookEntry `json:"spellbook,omitempty"` // wizards and OSE magic-users
+	Encumbrance   string     `json:"encumbrance,omitempty"` // the encumbrance rule in use, one of the encumbrance* constants
+	SellRate      int        `json:"sell_rate,omitempty"`   // percent of the cost the shop pays, defaultSellRate when 0
 }
 
 type Equipped struct {
@@ -100,6 +137,8 @@ type Abilities struct {
 type Skill struct {
 	Name       string `json:"name"`
 	Proficient bool   `json:"proficient"`
+	Expertise  bool   `json:"expertise,omitempty"` // proficiency bonus counts twice
+	Override   bool   `json:"override,omitempty"`  // Modifier was typed in rather than computed
 	Modifier   int    `json:"modifier"`
 }
 
@@ -130,6 +169,8 @@ type Spell struct {
 	Level       int    `json:"level"`
 	School      string `json:"school"`
 	Prepared    bool   `json:"prepared"`
+	Ritual      bool   `json:"ritual,omitempty"`
+	Class       string `json:"class,omitempty"` // the class it is known or prepared for, "" until prepared
 }
 
 // Model represents the application state
@@ -148,14 +189,27 @@ type Model struct {
 	message       string
 	equipMode     string // "inventory" or "equipped"
 	selectedItem  int    // Index of selected item in equipment list
+	selectedSkill int    // Index of selected row on the Skills tab
+	selectedSave  int    // Index of selected saving throw on the Abilities tab
+	selectedSpell int    // Index of selected spell on the Spells tab
+	spellbookView bool   // the Spells tab shows the spellbook
 	selectedWeapon int   // Index of selected weapon in weapons list
+	picker        *catalogPicker // Open SRD catalog picker, if any
+	search        *searchScreen  // Open rules search, if any
+	creation      *creationWizard // Open character creation wizard, if any
+	xpPrompt      *textinput.Model // Open XP award prompt on the Basic Info tab, if any
+	searchIndex   *SearchIndex   // Built on the first search
+	rulesets      []Ruleset
 }
 
 // Initialization
 func initialModel() Model {
 	char := Character{
 		Name:   "New Character",
+		Ruleset: defaultRulesetID,
 		Race:   "Human",
+		Size:   "Medium",
+		Speed:  30,
 		Class:  "Fighter",
 		Level:  1,
 		Abilities: Abilities{
@@ -193,15 +247,18 @@ func initialModel() Model {
 		"Background",
 		"Proficiencies",
 		"Currency",
+		"Combat",
 	}
 
 	// Initialize text inputs
 	inputs := make(map[string]textinput.Model)
 	inputs["name"] = createInput("Name: ", char.Name)
-	inputs["race"] = createInput("Race: ", char.Race)
 	inputs["class"] = createInput("Class: ", char.Class)
 	inputs["level"] = createInput("Level: ", fmt.Sprintf("%d", char.Level))
-	inputs["background"] = createInput("Background: ", char.Background)
+	inputs["duration"] = createInput("Duration: ", "")
+	inputs["skill_override"] = createInput("Override: ", "")
+	inputs["hp_change"] = createInput("Damage/healing (-7, -7c crit, +5): ", "")
+	inputs["temp_hp"] = createInput("Temporary HP: ", "")
 
 	// Initialize ability inputs
 	inputs["str"] = createInput("Strength: ", fmt.Sprintf("%d", char.Abilities.Strength))
@@ -219,9 +276,10 @@ func initialModel() Model {
 	inputs["pp"] = createInput("Platinum: ", fmt.Sprintf("%d", char.Currency.PP))
 
 	// Load SRD data
-	srdData, err := loadSRDData()
+	srdData, err := loadSRDData(srdOverrideDirs())
+	message := "Welcome to D&D Character Editor!"
 	if err != nil {
-		fmt.Printf("Error loading SRD data: %v\n", err)
+		message = fmt.Sprintf("Error loading SRD data: %v", err)
 	}
 
 	return Model{
@@ -235,8 +293,9 @@ func initialModel() Model {
 		spells:        char.Spells,
 		proficiencies: char.Proficiencies,
 		srdData:       srdData,
+		rulesets:      newRulesets(srdData),
 		mode:          "view",
-		message:       "Welcome to D&D Character Editor!",
+		message:       message,
 		equipMode:     "inventory",
 		selectedItem:  -1,
 		selectedWeapon: -1,
@@ -253,42 +312,6 @@ func createInput(placeholder, value string) textinput.Model {
 	return input
 }
 
-func loadSRDData() (SRDData, error) {
-	var srdData SRDData
-	
-	// Load equipment data
-	equipmentFile, err := os.ReadFile("srd_equipment.json")
-	if err != nil {
-		return srdData, fmt.Errorf("error reading equipment file: %v", err)
-	}
-	err = json.Unmarshal(equipmentFile, &srdData.Equipment)
-	if err != nil {
-		return srdData, fmt.Errorf("error parsing equipment JSON: %v", err)
-	}
-	
-	// Load weapons data
-	weaponsFile, err := os.ReadFile("srd_weapons.json")
-	if err != nil {
-		return srdData, fmt.Errorf("error reading weapons file: %v", err)
-	}
-	err = json.Unmarshal(weaponsFile, &srdData.Weapons)
-	if err != nil {
-		return srdData, fmt.Errorf("error parsing weapons JSON: %v", err)
-	}
-	
-	// Load spells data
-	spellsFile, err := os.ReadFile("srd_spells.json")
-	if err != nil {
-		return srdData, fmt.Errorf("error reading spells file: %v", err)
-	}
-	err = json.Unmarshal(spellsFile, &srdData.Spells)
-	if err != nil {
-		return srdData, fmt.Errorf("error parsing spells JSON: %v", err)
-	}
-	
-	return srdData, nil
-}
-
 func saveCharacter(character Character) error {
 	// Create characters directory if it doesn't exist
 	err := os.MkdirAll("characters", 0755)
@@ -375,6 +398,10 @@ var (
 			Background(lipgloss.Color("#5A23C8")).
 			Padding(0, 1)
 
+	statusStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#888888")).
+			Padding(0, 1)
+
 	buttonStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFFFFF")).
 			Background(lipgloss.Color("#7D56F4")).
@@ -391,6 +418,10 @@ var (
 	equippedStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#00FF00")).
 			Bold(true)
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF5555")).
+			Bold(true)
 )
 
 // Init function for Bubble Tea
@@ -402,10 +433,25 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.picker != nil {
+			return m.updatePicker(msg)
+		}
+		if m.search != nil {
+			return m.updateSearch(msg)
+		}
+		if m.creation != nil {
+			return m.updateCreation(msg)
+		}
+		if m.xpPrompt != nil {
+			return m.updateXPPrompt(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
-		case "right", "l", "n", "tab":
+		case "/":
+			m.openSearch()
+			return m, textinput.Blink
+		case "right", "n", "tab":
 			if m.activeTab == 3 && m.equipMode == "inventory" && len(m.equipment) > 0 {
 				// In equipment tab, navigate items
 				m.selectedItem = (m.selectedItem + 1) % len(m.equipment)
@@ -415,7 +461,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectedWeapon = (m.selectedWeapon + 1) % len(m.weapons)
 				return m, nil
 			} else {
-				m.activeTab = (m.activeTab + 1) % len(m.tabs)
+				m.activeTab = m.nextTab(1)
 				m.selectedItem = -1
 				m.selectedWeapon = -1
 				return m, nil
@@ -430,7 +476,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectedWeapon = (m.selectedWeapon - 1 + len(m.weapons)) % len(m.weapons)
 				return m, nil
 			} else {
-				m.activeTab = (m.activeTab - 1 + len(m.tabs)) % len(m.tabs)
+				m.activeTab = m.nextTab(-1)
 				m.selectedItem = -1
 				m.selectedWeapon = -1
 				return m, nil
@@ -465,6 +511,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					m.character = character
 					m.message = fmt.Sprintf("Loaded character: %s", character.Name)
+					if m.ruleset().TabTitle(m.tabs[m.activeTab]) == "" {
+						m.activeTab = m.nextTab(1)
+					}
 					// Update inputs with loaded character data
 					m.updateInputsFromCharacter()
 				}
@@ -488,27 +537,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.equipment = append(m.equipment, newItem)
 					m.message = "Added backpack to equipment"
 				} else if m.activeTab == 4 {
-					// Add sample weapon
-					newWeapon := Weapon{
-						Name:        "Longsword",
-						Description: "A versatile martial weapon",
-						Damage:      "1d8 slashing",
-						Properties:  "Versatile (1d10)",
-						Weight:      "3 lb.",
-						Cost:        "15 gp",
-						Equipped:    false,
-						Hand:        "",
-					}
-					m.weapons = append(m.weapons, newWeapon)
-					m.message = "Added longsword to weapons"
+					// Pick a weapon from the SRD catalog
+					m.openWeaponPicker()
+				} else if m.activeTab == 5 && m.spellbookView {
+					// Write a spell into the spellbook
+					m.openLearnSpellPicker()
+				} else if m.activeTab == 5 {
+					// Pick a spell from the SRD lists
+					m.openSpellPicker()
 				}
 			}
 			return m, nil
+		case "up", "down":
+			step := map[string]int{"up": -1, "down": 1}[msg.String()]
+			if m.activeTab == 1 {
+				m.moveSaveSelection(step)
+				return m, nil
+			} else if m.activeTab == 2 {
+				m.moveSkillSelection(step)
+				return m, nil
+			} else if m.activeTab == 5 {
+				m.moveSpellSelection(step)
+				return m, nil
+			}
+		case "ctrl+d":
+			if m.activeTab == 1 {
+				m.rollSave()
+			}
+			return m, nil
 		case "enter":
 			if m.mode == "edit" {
 				return m.updateCharacterData()
 			}
 		case " ":
+			if m.mode == "edit" && m.activeTab == 2 {
+				m.cycleSkillProficiency()
+				return m, nil
+			}
+			if m.mode == "edit" && m.activeTab == 5 {
+				if m.spellbookView {
+					m.prepareFromSpellbook()
+				} else {
+					m.togglePrepared()
+				}
+				return m, nil
+			}
 			if m.mode == "edit" {
 				if m.activeTab == 3 && m.selectedItem >= 0 && m.selectedItem < len(m.equipment) {
 					// Toggle equipment equipped status
@@ -606,6 +679,104 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+		case "ctrl+r":
+			if m.mode == "edit" {
+				m.switchRuleset()
+			}
+			return m, nil
+		case "ctrl+o":
+			if m.mode == "edit" && m.activeTab == 0 {
+				m.openRacePicker()
+			} else if m.mode == "edit" && m.activeTab == 6 {
+				m.openBackgroundPicker()
+			}
+			return m, nil
+		case "f":
+			if m.mode == "edit" && m.activeTab == 6 {
+				m.openFeatPicker()
+				return m, nil
+			}
+		case "ctrl+g":
+			if m.mode == "edit" && m.activeTab == 0 {
+				m.openSubclassPicker()
+			}
+			return m, nil
+		case "ctrl+p":
+			if m.mode == "edit" && m.activeTab == 0 {
+				m.openXPPrompt()
+			}
+			return m, nil
+		case "ctrl+u":
+			if m.mode == "edit" && m.activeTab == 0 {
+				m.levelUp()
+			}
+			return m, nil
+		case "ctrl+n":
+			if m.mode == "edit" && m.activeTab == 0 {
+				m.openConditionPicker()
+			}
+			return m, nil
+		case "ctrl+x":
+			if m.mode == "edit" && m.activeTab == 0 {
+				m.openRemoveConditionPicker()
+			}
+			return m, nil
+		case "ctrl+t":
+			m.nextRound()
+			return m, nil
+		case "ctrl+l":
+			m.openRestPicker()
+			return m, nil
+		case "ctrl+w":
+			m.openCreation()
+			return m, nil
+		case "c":
+			if m.activeTab == 5 && !m.spellbookView {
+				m.openCastPicker()
+				return m, nil
+			}
+		case "b":
+			if m.activeTab == 5 {
+				m.toggleSpellbookView()
+				return m, nil
+			}
+		case "ctrl+s", "ctrl+f":
+			if m.mode == "edit" && m.activeTab == 9 {
+				m.addDeathSave(msg.String() == "ctrl+s")
+			}
+			return m, nil
+		case "ctrl+b":
+			if m.mode == "edit" && (m.activeTab == 3 || m.activeTab == 4 || m.activeTab == 8) {
+				m.openShop()
+			}
+			return m, nil
+		case "ctrl+e":
+			if m.mode == "edit" && m.activeTab == 3 {
+				m.openEncumbrancePicker()
+			}
+			return m, nil
+		case "ctrl+k":
+			if m.mode == "edit" && m.activeTab == 9 {
+				m.spendHitDie()
+			}
+			return m, nil
+		case "ctrl+v":
+			if m.mode == "edit" && m.activeTab == 9 {
+				m.revive()
+			}
+			return m, nil
+		case "r":
+			if m.mode == "edit" && m.activeTab == 3 {
+				// Pick armor from the SRD catalog
+				m.openArmorPicker()
+				return m, nil
+			}
+		case "m":
+			if m.mode == "edit" && m.activeTab == 3 {
+				// Pick a magic item from the SRD catalog
+				m.openMagicItemPicker()
+				return m, nil
+			}
 		case "i":
 			if m.activeTab == 3 {
 				m.equipMode = "inventory"
@@ -630,24 +801,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) updateInputsFromCharacter() {
-	m.inputs["name"].SetValue(m.character.Name)
-	m.inputs["race"].SetValue(m.character.Race)
-	m.inputs["class"].SetValue(m.character.Class)
-	m.inputs["level"].SetValue(fmt.Sprintf("%d", m.character.Level))
-	m.inputs["background"].SetValue(m.character.Background)
+	m.setInput("name", m.character.Name)
+	m.setInput("class", m.classesText())
+	m.setInput("level", fmt.Sprintf("%d", m.character.Level))
 	
-	m.inputs["str"].SetValue(fmt.Sprintf("%d", m.character.Abilities.Strength))
-	m.inputs["dex"].SetValue(fmt.Sprintf("%d", m.character.Abilities.Dexterity))
-	m.inputs["con"].SetValue(fmt.Sprintf("%d", m.character.Abilities.Constitution))
-	m.inputs["int"].SetValue(fmt.Sprintf("%d", m.character.Abilities.Intelligence))
-	m.inputs["wis"].SetValue(fmt.Sprintf("%d", m.character.Abilities.Wisdom))
-	m.inputs["cha"].SetValue(fmt.Sprintf("%d", m.character.Abilities.Charisma))
+	m.setInput("str", fmt.Sprintf("%d", m.character.Abilities.Strength))
+	m.setInput("dex", fmt.Sprintf("%d", m.character.Abilities.Dexterity))
+	m.setInput("con", fmt.Sprintf("%d", m.character.Abilities.Constitution))
+	m.setInput("int", fmt.Sprintf("%d", m.character.Abilities.Intelligence))
+	m.setInput("wis", fmt.Sprintf("%d", m.character.Abilities.Wisdom))
+	m.setInput("cha", fmt.Sprintf("%d", m.character.Abilities.Charisma))
 	
-	m.inputs["cp"].SetValue(fmt.Sprintf("%d", m.character.Currency.CP))
-	m.inputs["sp"].SetValue(fmt.Sprintf("%d", m.character.Currency.SP))
-	m.inputs["ep"].SetValue(fmt.Sprintf("%d", m.character.Currency.EP))
-	m.inputs["gp"].SetValue(fmt.Sprintf("%d", m.character.Currency.GP))
-	m.inputs["pp"].SetValue(fmt.Sprintf("%d", m.character.Currency.PP))
+	m.setInput("cp", fmt.Sprintf("%d", m.character.Currency.CP))
+	m.setInput("sp", fmt.Sprintf("%d", m.character.Currency.SP))
+	m.setInput("ep", fmt.Sprintf("%d", m.character.Currency.EP))
+	m.setInput("gp", fmt.Sprintf("%d", m.character.Currency.GP))
+	m.setInput("pp", fmt.Sprintf("%d", m.character.Currency.PP))
+}
+
+// setInput sets an input's text. The inputs are stored by value, so the
+// changed copy goes back in the map
+func (m *Model) setInput(key, value string) {
+	input := m.inputs[key]
+	input.SetValue(value)
+	m.inputs[key] = input
 }
 
 func (m *Model) updateInputs(msg tea.Msg) tea.Cmd {
@@ -655,7 +832,7 @@ func (m *Model) updateInputs(msg tea.Msg) tea.Cmd {
 
 	// Update basic info inputs
 	if m.activeTab == 0 {
-		for key := range map[string]bool{"name": true, "race": true, "class": true, "level": true} {
+		for key := range map[string]bool{"name": true, "class": true, "level": true, "duration": true} {
 			var cmd tea.Cmd
 			m.inputs[key], cmd = m.inputs[key].Update(msg)
 			cmds = append(cmds, cmd)
@@ -671,13 +848,22 @@ func (m *Model) updateInputs(msg tea.Msg) tea.Cmd {
 		}
 	}
 
-	// Update background input
-	if m.activeTab == 6 {
+	// Update the skill override input
+	if m.activeTab == 2 {
 		var cmd tea.Cmd
-		m.inputs["background"], cmd = m.inputs["background"].Update(msg)
+		m.inputs["skill_override"], cmd = m.inputs["skill_override"].Update(msg)
 		cmds = append(cmds, cmd)
 	}
-	
+
+	// Update combat inputs
+	if m.activeTab == 9 {
+		for key := range map[string]bool{"hp_change": true, "temp_hp": true} {
+			var cmd tea.Cmd
+			m.inputs[key], cmd = m.inputs[key].Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
 	// Update currency inputs
 	if m.activeTab == 8 {
 		for key := range map[string]bool{"cp": true, "sp": true, "ep": true, "gp": true, "pp": true} {
@@ -697,11 +883,10 @@ func (m Model) updateCharacterData() (tea.Model, tea.Cmd) {
 	// Update basic info
 	if m.activeTab == 0 {
 		m.character.Name = m.inputs["name"].Value()
-		m.character.Race = m.inputs["race"].Value()
-		m.character.Class = m.inputs["class"].Value()
 		if level, err := strconv.Atoi(m.inputs["level"].Value()); err == nil {
 			m.character.Level = level
 		}
+		m.setClasses(m.inputs["class"].Value())
 	}
 
 	// Update abilities
@@ -726,11 +911,16 @@ func (m Model) updateCharacterData() (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Update background
-	if m.activeTab == 6 {
-		m.character.Background = m.inputs["background"].Value()
+	// Override the selected skill's modifier, or apply damage, healing and
+	// temporary HP. These leave their own message
+	applied := false
+	switch m.activeTab {
+	case 2:
+		applied = m.applySkillOverride()
+	case 9:
+		applied = m.applyCombatInputs()
 	}
-	
+
 	// Update currency
 	if m.activeTab == 8 {
 		if val, err := strconv.Atoi(m.inputs["cp"].Value()); err == nil {
@@ -757,7 +947,9 @@ func (m Model) updateCharacterData() (tea.Model, tea.Cmd) {
 	m.character.Spells = m.spells
 	m.character.Proficiencies = m.proficiencies
 
-	m.message = "Character data updated!"
+	if !applied {
+		m.message = "Character data updated!"
+	}
 	return m, nil
 }
 
@@ -766,10 +958,14 @@ func (m Model) View() string {
 	// Render tabs
 	var tabs []string
 	for i, tab := range m.tabs {
+		title := m.ruleset().TabTitle(tab)
+		if title == "" {
+			continue
+		}
 		if i == m.activeTab {
-			tabs = append(tabs, activeTabStyle.Render(tab))
+			tabs = append(tabs, activeTabStyle.Render(title))
 		} else {
-			tabs = append(tabs, tabStyle.Render(tab))
+			tabs = append(tabs, tabStyle.Render(title))
 		}
 	}
 	tabsRow := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
@@ -801,6 +997,18 @@ func (m Model) View() string {
 		content = m.renderProficiencies()
 	case 8:
 		content = m.renderCurrency()
+	case 9:
+		content = m.renderCombat()
+	}
+
+	if m.creation != nil {
+		content = m.renderCreation()
+	}
+	if m.picker != nil {
+		content = m.renderPicker()
+	}
+	if m.search != nil {
+		content = m.renderSearch()
 	}
 
 	// Render message
@@ -813,17 +1021,36 @@ func (m Model) View() string {
 		content,
 		"",
 		message,
+		statusStyle.Render(m.srdData.sourceStatus()),
 		"",
-		"Press ←/→ to switch tabs, e to edit, v to view, s to save, l to load, q to quit",
+		"Press ←/→ to switch tabs, e to edit, v to view, s to save, l to load, / to search rules, ctrl+w for a new character, q to quit",
 	)
 }
 
 func (m Model) renderBasicInfo() string {
 	basicInfo := titleStyle.Render("Character Basics") + "\n\n"
 	basicInfo += m.inputs["name"].View() + "\n"
-	basicInfo += m.inputs["race"].View() + "\n"
+	basicInfo += fmt.Sprintf("Race: %s (%s, %d ft.)", m.character.Race, m.character.Size, m.character.Speed)
+	if m.mode == "edit" {
+		basicInfo += " (ctrl+o to choose)"
+	}
+	basicInfo += "\n"
 	basicInfo += m.inputs["class"].View() + "\n"
 	basicInfo += m.inputs["level"].View() + "\n"
+	basicInfo += m.renderExperience() + "\n"
+	basicInfo += fmt.Sprintf("Ruleset: %s", m.ruleset().Name())
+	if m.mode == "edit" {
+		basicInfo += " (ctrl+r to switch)"
+	}
+	basicInfo += "\nClasses: " + strings.Join(m.ruleset().Classes(), ", ") + "\n\n"
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		if sheet := m.renderOSESheet(ruleset); sheet != "" {
+			basicInfo += sheet + "\n"
+		}
+	} else if features := m.renderClassFeatures(); features != "" {
+		basicInfo += features + "\n"
+	}
+	basicInfo += m.renderConditions()
 	return sectionStyle.Render(basicInfo)
 }
 
@@ -834,20 +1061,59 @@ func (m Model) renderAbilities() string {
 	abilities += m.inputs["con"].View() + "  "
 	abilities += m.inputs["int"].View() + "\n"
 	abilities += m.inputs["wis"].View() + "  "
-	abilities += m.inputs["cha"].View() + "\n"
+	abilities += m.inputs["cha"].View() + "\n\n"
+
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		effects := ruleset.abilityEffects(m.character.Abilities)
+		for _, ability := range abilityNames {
+			abilities += fmt.Sprintf("%s: %s\n", ability[:3], effects[ability])
+		}
+	} else {
+		ruleset := m.ruleset()
+		var modifiers []string
+		for _, ability := range abilityNames {
+			modifiers = append(modifiers, fmt.Sprintf("%s %+d", ability[:3], ruleset.AbilityModifier(m.character.Abilities.score(ability))))
+		}
+		abilities += "Modifiers: " + strings.Join(modifiers, "  ") + "\n"
+	}
+
+	abilities += "\n" + m.renderSavingThrows()
 	return sectionStyle.Render(abilities)
 }
 
 func (m Model) renderSkills() string {
 	skills := titleStyle.Render("Skills") + "\n\n"
-	for _, skill := range m.skillList {
+	if bonus := m.ruleset().ProficiencyBonus(m.character.Level); bonus > 0 {
+		skills += fmt.Sprintf("Proficiency Bonus: %+d\n\n", bonus)
+	}
+
+	effects := m.conditionEffects()
+	for i, row := range m.skillRows() {
 		proficiency := " "
-		if skill.Proficient {
+		if row.Expertise {
+			proficiency = "E"
+		} else if row.Proficient {
 			proficiency = "✓"
 		}
-		skills += fmt.Sprintf("[%s] %s: %+d\n", proficiency, skill.Name, skill.Modifier)
+		skillStr := fmt.Sprintf("[%s] %s", proficiency, row.Name)
+		if row.Ability != "" {
+			skillStr += fmt.Sprintf(" (%s)", row.Ability[:3])
+		}
+		skillStr += fmt.Sprintf(": %+d", m.skillModifier(row))
+		if row.Override {
+			skillStr += " (manual)"
+		}
+		skillStr += rollModeSuffix(rollMode(effects, "check", row.Ability))
+		if m.mode == "edit" && i == m.selectedSkill {
+			skillStr = selectedItemStyle.Render(skillStr)
+		}
+		skills += skillStr + "\n"
+	}
+
+	if m.mode == "edit" {
+		skills += "\n" + m.inputs["skill_override"].View() + "\n"
+		skills += "↑/↓ to select, space for proficient/expertise, enter to set the typed override (empty to clear)"
 	}
-	skills += "\nPress 'a' to add a new skill"
 	return sectionStyle.Render(skills)
 }
 
@@ -863,6 +1129,7 @@ func (m Model) renderEquipment() string {
 		equippedBtn = selectedButtonStyle.Render("Equipped")
 	}
 	equipmentView += lipgloss.JoinHorizontal(lipgloss.Top, inventoryBtn, " ", equippedBtn) + "\n\n"
+	equipmentView += m.renderEncumbrance()
 	
 	if m.equipMode == "inventory" {
 		// Show inventory
@@ -882,7 +1149,7 @@ func (m Model) renderEquipment() string {
 				equipmentView += itemStr + "\n"
 			}
 		}
-		equipmentView += "\nPress Space to equip/unequip, a to add item, i/w to switch view"
+		equipmentView += "\nPress Space to equip/unequip, a to add item, r to add armor, m to add magic item, ctrl+b to shop, ctrl+e for the encumbrance rule, i/w to switch view"
 	} else {
 		// Show equipped items
 		equipmentView += "Head: " + m.character.Equipped.Head.Name + "\n"
@@ -901,11 +1168,12 @@ func (m Model) renderEquipment() string {
 func (m Model) renderWeapons() string {
 	weapons := titleStyle.Render("Weapons") + "\n\n"
 	
+	roll := rollModeSuffix(rollMode(m.conditionEffects(), "attack", ""))
 	if len(m.weapons) == 0 {
 		weapons += "No weapons\n"
 	} else {
 		for i, weapon := range m.weapons {
-			weaponStr := fmt.Sprintf("%d. %s (%s, %s)", i+1, weapon.Name, weapon.Damage, weapon.Cost)
+			weaponStr := fmt.Sprintf("%d. %s (%+d to hit%s, %s, %s)", i+1, weapon.Name, m.ruleset().AttackBonus(m.character, weapon), roll, weapon.Damage, weapon.Cost)
 			if weapon.Equipped {
 				weaponStr = equippedStyle.Render(weaponStr + " [EQUIPPED]")
 			}
@@ -917,26 +1185,40 @@ func (m Model) renderWeapons() string {
 			weapons += weaponStr + "\n"
 		}
 	}
-	weapons += "\nPress Space to equip/unequip, a to add weapon"
+	weapons += "\nPress Space to equip/unequip, a to add weapon, ctrl+b to shop"
 	return sectionStyle.Render(weapons)
 }
 
-func (m Model) renderSpells() string {
-	spells := titleStyle.Render("Spells") + "\n\n"
-	for _, spell := range m.spells {
-		prepared := " "
-		if spell.Prepared {
-			prepared = "✓"
+func (m Model) renderBackground() string {
+	bg := titleStyle.Render("Background & Traits") + "\n\n"
+	bg += "Background: " + m.character.Background + "\n"
+	if background, ok := m.srdData.background(m.character.Background); ok {
+		bg += fmt.Sprintf("Feature: %s\n", background.Feature.Name)
+		bg += fmt.Sprintf("Skills: %s\n", strings.Join(background.Skills, ", "))
+	}
+
+	if race, ok := m.srdData.race(m.character.Race); ok {
+		bg += "\n" + titleStyle.Render("Racial Traits") + "\n"
+		for _, trait := range race.Traits {
+			bg += trait.Name + "\n"
 		}
-		spells += fmt.Sprintf("[%s] Level %d: %s (%s)\n", prepared, spell.Level, spell.Name, spell.School)
 	}
-	spells += "\nPress 'a' to add a new spell from SRD"
-	return sectionStyle.Render(spells)
-}
 
-func (m Model) renderBackground() string {
-	bg := titleStyle.Render("Background & Traits") + "\n\n"
-	bg += m.inputs["background"].View() + "\n"
+	bg += "\n" + titleStyle.Render("Feats") + "\n"
+	if len(m.character.Feats) == 0 {
+		bg += "None\n"
+	}
+	for _, name := range m.character.Feats {
+		bg += name + "\n"
+		if feat, ok := m.srdData.feat(name); ok {
+			for _, benefit := range feat.Benefits {
+				bg += "  - " + truncate(benefit, 70) + "\n"
+			}
+		}
+	}
+	if m.mode == "edit" {
+		bg += "\nPress ctrl+o to choose a background, f to take or remove a feat"
+	}
 	return sectionStyle.Render(bg)
 }
 
@@ -951,16 +1233,42 @@ func (m Model) renderProficiencies() string {
 
 func (m Model) renderCurrency() string {
 	currency := titleStyle.Render("Currency") + "\n\n"
-	currency += m.inputs["cp"].View() + "  "
-	currency += m.inputs["sp"].View() + "\n"
-	currency += m.inputs["ep"].View() + "  "
-	currency += m.inputs["gp"].View() + "\n"
-	currency += m.inputs["pp"].View() + "\n"
+	coins := m.ruleset().Coins()
+	for _, coin := range coins {
+		currency += fmt.Sprintf("%-9s %s\n", coin.Name+":", m.inputs[coin.Abbrev].View())
+	}
+	currency += fmt.Sprintf("\nTotal: %s\n", formatCopper(m.character.Currency.value(coins)))
+	if m.mode == "edit" {
+		currency += fmt.Sprintf("\nctrl+b to shop (selling at %d%% of cost)\n", m.sellRate())
+	}
 	return sectionStyle.Render(currency)
 }
 
 // Main function
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			fmt.Printf("Import failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		if err := runReconcile(os.Args[2:]); err != nil {
+			fmt.Printf("Reconcile failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "search" {
+		if err := runSearch(os.Args[2:]); err != nil {
+			fmt.Printf("Search failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	p := tea.NewProgram(initialModel())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Multiclassing follows Characterizations/Multiclassing.md. Character.Class
+// is the first class, which gave the saving throws and full starting
+// proficiencies; Character.Classes are the ones taken since. Level is the
+// character level, so the proficiency bonus and XP go by the total
+
+// CharacterClass is one of a character's classes and the levels taken in it
+type CharacterClass struct {
+	Name     string `json:"name"`
+	Level    int    `json:"level"`
+	Subclass string `json:"subclass,omitempty"`
+	HPRolls  []int  `json:"hp_rolls,omitempty"` // hit die rolled at each of the class's levels after the character's first, 0 for the average
+}
+
+var classLevelPattern = regexp.MustCompile(`^(.*?)\s+(\d+)$`) // "Fighter 2"
+
+// classLevels lists the character's classes with their levels. The first
+// class has the levels the classes taken since don't
+func (m Model) classLevels() []CharacterClass {
+	first := CharacterClass{
+		Name:     m.character.Class,
+		Level:    max(m.character.Level, 1),
+		Subclass: m.character.Subclass,
+		HPRolls:  m.character.HPRolls,
+	}
+	for _, class := range m.character.Classes {
+		first.Level -= class.Level
+	}
+	first.Level = max(first.Level, 1)
+	return append([]CharacterClass{first}, m.character.Classes...)
+}
+
+// classesText is the class field: the class name, or "Fighter 2 / Wizard 3"
+// once the character has multiclassed
+func (m Model) classesText() string {
+	if len(m.character.Classes) == 0 {
+		return m.character.Class
+	}
+	var parts []string
+	for _, class := range m.classLevels() {
+		parts = append(parts, fmt.Sprintf("%s %d", class.Name, class.Level))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// setClasses reads the class field. A name alone sets a single class;
+// "Fighter 2 / Wizard 3" sets each class's levels and the character level
+// from them, keeping the subclasses and hit point rolls of classes the
+// character already had
+func (m *Model) setClasses(text string) {
+	parts := strings.Split(text, "/")
+	if len(parts) == 1 && !classLevelPattern.MatchString(strings.TrimSpace(text)) {
+		m.character.Class = text
+		m.character.Classes = nil
+		return
+	}
+
+	previous := m.classLevels()
+	var classes []CharacterClass
+	level := 0
+	for _, part := range parts {
+		class := CharacterClass{Name: strings.TrimSpace(part), Level: 1}
+		if match := classLevelPattern.FindStringSubmatch(class.Name); match != nil {
+			class.Name = match[1]
+			class.Level, _ = strconv.Atoi(match[2])
+			class.Level = max(class.Level, 1)
+		}
+		if class.Name == "" {
+			continue
+		}
+		for _, had := range previous {
+			if strings.EqualFold(had.Name, class.Name) {
+				class.Subclass, class.HPRolls = had.Subclass, had.HPRolls
+			}
+		}
+		classes = append(classes, class)
+		level += class.Level
+	}
+	if len(classes) == 0 {
+		return
+	}
+	m.character.Class, m.character.Subclass, m.character.HPRolls = classes[0].Name, classes[0].Subclass, classes[0].HPRolls
+	m.character.Classes = nil
+	if len(classes) > 1 {
+		m.character.Classes = classes[1:]
+	}
+	m.character.Level = level
+}
+
+// setSubclass sets the subclass of the character's ith class
+func (m *Model) setSubclass(i int, subclass string) {
+	if i == 0 {
+		m.character.Subclass = subclass
+		return
+	}
+	m.character.Classes[i-1].Subclass = subclass
+}
+
+// recordHPRoll keeps the hit die rolled for the nth of the ith class's
+// levels after the character's first, 0 for the average
+func (m *Model) recordHPRoll(i, n, roll int) {
+	rolls := append([]int(nil), m.classLevels()[i].HPRolls...)
+	for len(rolls) < n {
+		rolls = append(rolls, 0)
+	}
+	rolls[n-1] = roll
+	if i == 0 {
+		m.character.HPRolls = rolls
+		return
+	}
+	m.character.Classes[i-1].HPRolls = rolls
+}
+
+// classFeatures are the features of each of the character's classes at
+// its level, in the order the classes were taken
+func (m Model) classFeatures() []ClassFeature {
+	var features []ClassFeature
+	for _, cl := range m.classLevels() {
+		if class, ok := m.srdData.class(cl.Name); ok {
+			features = append(features, class.featuresAt(cl.Level, cl.Subclass)...)
+		}
+	}
+	return features
+}
+
+// multiclassOptions are the classes the character qualifies to take as a
+// new class: the scores have to meet the prerequisites of the new class
+// and of every class the character already has
+func (m Model) multiclassOptions() []MulticlassClass {
+	classes := m.classLevels()
+	for _, cl := range classes {
+		if rules, ok := m.srdData.multiclassClass(cl.Name); ok && !rules.qualifies(m.character.Abilities) {
+			return nil
+		}
+	}
+	var options []MulticlassClass
+	for _, rules := range m.srdData.MulticlassClasses {
+		taken := false
+		for _, cl := range classes {
+			taken = taken || strings.EqualFold(cl.Name, rules.Name)
+		}
+		if _, ok := m.srdData.class(rules.Name); ok && !taken && rules.qualifies(m.character.Abilities) {
+			options = append(options, rules)
+		}
+	}
+	return options
+}
+
+// addClass takes a new class at level 0, for levelUp to raise to 1st. The
+// character picks the class's skill, if it gives one, and only then gains
+// the class and its multiclassing proficiencies, so backing out of the pick
+// leaves the sheet as it was
+func (m *Model) addClass(rules MulticlassClass, then func(m *Model)) {
+	var options []string
+	if class, ok := m.srdData.class(rules.Name); ok && rules.ClassSkills {
+		options = class.SkillOptions
+	}
+	var skills []string
+	chosen := func(m *Model, skill string) {
+		skills = append(skills, skill)
+	}
+	taken := false
+	m.chooseSkills(rules.Name+": Skill proficiency", rules.SkillChoices, options, chosen, func(m *Model) {
+		taken = true
+		m.character.Classes = append(m.character.Classes, CharacterClass{Name: rules.Name})
+		m.grantFrom("class:"+rules.Name, Grants{
+			Proficiencies: rules.Proficiencies,
+			Skills:        skills,
+			SkillChoices:  rules.SkillChoices - len(skills),
+		})
+		then(m)
+	})
+	if !taken && m.picker != nil {
+		m.picker.cancel = func(m *Model) {
+			m.message = fmt.Sprintf("Level up cancelled: %s not taken", rules.Name)
+		}
+	}
+}
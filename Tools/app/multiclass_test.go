@@ -0,0 +1,145 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSetClasses(t *testing.T) {
+	tests := []struct {
+		text  string
+		class string
+		level int
+		want  string // classesText afterwards
+	}{
+		{"Wizard", "Wizard", 3, "Wizard"},
+		{"Fighter 2 / Wizard 3", "Fighter", 5, "Fighter 2 / Wizard 3"},
+		{"Rogue 4", "Rogue", 4, "Rogue"},
+		{"Cleric/Druid", "Cleric", 2, "Cleric 1 / Druid 1"},
+		{" / ", "Bard", 3, "Bard"},
+	}
+	for _, tt := range tests {
+		m := Model{}
+		m.character.Class, m.character.Level = "Bard", 3
+		m.setClasses(tt.text)
+		if m.character.Class != tt.class || m.character.Level != tt.level || m.classesText() != tt.want {
+			t.Errorf("setClasses(%q): %s, level %d, %q, want %s, level %d, %q",
+				tt.text, m.character.Class, m.character.Level, m.classesText(), tt.class, tt.level, tt.want)
+		}
+	}
+}
+
+func TestSetClassesKeepsSubclasses(t *testing.T) {
+	m := Model{}
+	m.setClasses("Fighter 2 / Wizard 1")
+	m.setSubclass(1, "School of Evocation")
+	m.recordHPRoll(1, 1, 4)
+	m.setClasses("Fighter 2 / Wizard 2")
+
+	want := []CharacterClass{
+		{Name: "Fighter", Level: 2},
+		{Name: "Wizard", Level: 2, Subclass: "School of Evocation", HPRolls: []int{4}},
+	}
+	if got := m.classLevels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("classLevels = %+v, want %+v", got, want)
+	}
+}
+
+func TestMulticlassSpellSlots(t *testing.T) {
+	tests := []struct {
+		classes   string
+		slots     []int
+		pact      int
+		pactLevel int
+	}{
+		{"Wizard 3 / Cleric 2", []int{4, 3, 2, 0, 0, 0, 0, 0, 0}, 0, 0},
+		{"Paladin 4 / Sorcerer 1", []int{4, 2, 0, 0, 0, 0, 0, 0, 0}, 0, 0},
+		{"Fighter 3 / Wizard 2", []int{3, 0, 0, 0, 0, 0, 0, 0, 0}, 0, 0},
+		{"Wizard 3 / Warlock 2", []int{4, 2, 0, 0, 0, 0, 0, 0, 0}, 2, 1},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, false, "Wizard", 1)
+		m.setClasses(tt.classes)
+		if got := m.spellSlots(); !reflect.DeepEqual(got, tt.slots) {
+			t.Errorf("%s: spellSlots = %v, want %v", tt.classes, got, tt.slots)
+		}
+		if pact, level := m.pactSlots(); pact != tt.pact || level != tt.pactLevel {
+			t.Errorf("%s: pactSlots = %d of level %d, want %d of level %d", tt.classes, pact, level, tt.pact, tt.pactLevel)
+		}
+	}
+}
+
+func TestMulticlassMaxHP(t *testing.T) {
+	m := combatModel(t, "Fighter", 1)
+	m.character.Abilities.Constitution = 14
+	m.setClasses("Fighter 1 / Wizard 2")
+	m.recordHPRoll(1, 1, 6)
+	// 10 + 2 for the fighter, then 6 + 2 rolled and 4 + 2 average for the wizard
+	if got := m.maxHP(); got != 26 {
+		t.Errorf("maxHP = %d, want 26", got)
+	}
+}
+
+func TestMulticlassOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		abilities Abilities
+		want      []string
+	}{
+		{"weak", Abilities{10, 10, 10, 10, 10, 10}, nil},
+		{"first class unqualified", Abilities{Strength: 12, Intelligence: 13}, nil},
+		{"wizard", Abilities{Strength: 13, Intelligence: 13}, []string{"Barbarian", "Wizard"}},
+		{"finesse fighter", Abilities{Strength: 8, Dexterity: 13, Wisdom: 13}, []string{"Cleric", "Druid", "Monk", "Ranger", "Rogue"}},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, "Fighter", 1)
+		m.character.Abilities = tt.abilities
+		var names []string
+		for _, option := range m.multiclassOptions() {
+			names = append(names, option.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s: multiclassOptions = %q, want %q", tt.name, names, tt.want)
+		}
+	}
+}
+
+func TestAddClass(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    tea.KeyType
+		taken  bool
+		skills []string
+	}{
+		{"skill picked", tea.KeyEnter, true, []string{"Animal Handling"}},
+		{"cancelled", tea.KeyEsc, false, nil},
+	}
+	for _, tt := range tests {
+		m := combatModel(t, "Fighter", 1)
+		m.inputs = map[string]textinput.Model{}
+		rules, ok := m.srdData.multiclassClass("Ranger")
+		if !ok {
+			t.Fatal("no multiclassing rules for the Ranger")
+		}
+		then := false
+		m.addClass(rules, func(m *Model) { then = true })
+		if m.picker == nil {
+			t.Fatalf("%s: no skill picker", tt.name)
+		}
+		if len(m.character.Classes) != 0 || len(m.character.Granted) != 0 {
+			t.Errorf("%s: class granted before the skill was picked", tt.name)
+		}
+
+		updated, _ := m.updatePicker(tea.KeyMsg{Type: tt.key})
+		m = updated.(Model)
+		if then != tt.taken || (len(m.character.Classes) == 1) != tt.taken {
+			t.Errorf("%s: then ran %v, classes %+v, want taken %v", tt.name, then, m.character.Classes, tt.taken)
+		}
+		if got := m.character.Granted["class:Ranger"].Skills; !reflect.DeepEqual(got, tt.skills) {
+			t.Errorf("%s: granted skills %q, want %q", tt.name, got, tt.skills)
+		}
+	}
+}
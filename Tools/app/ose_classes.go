@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Old-School Essentials classes from Rules/OSE.SRD.Wiki/2. Classes
+
+// "- **Climb sheer surfaces (CS):** A roll is required for each 100’ climb."
+var oseClassSkillPattern = regexp.MustCompile(`(?m)^- \*\*(.+?) \(([A-Z]{2})\):\*\*(.*)$`)
+
+type OSEClass struct {
+	Name            string          `json:"name"`
+	Demihuman       bool            `json:"demihuman"`
+	Requirements    string          `json:"requirements"`
+	PrimeRequisites []string        `json:"prime_requisites"` // "STR", "INT"
+	HitDie          string          `json:"hit_die"`
+	MaxLevel        int             `json:"max_level"`
+	Armor           string          `json:"armor"`
+	Weapons         string          `json:"weapons"`
+	Languages       string          `json:"languages"`
+	Description     string          `json:"description"`
+	SpellBook       bool            `json:"spell_book,omitempty"` // memorizes spells from a spell book
+	Skills          []OSEClassSkill `json:"skills,omitempty"`     // the thief's skills
+	Levels          []OSEClassLevel `json:"levels"`
+}
+
+// OSEClassSkill is a skill from the class's "## Name Skills" list, such as
+// the thief's climb sheer surfaces. The chance of success is per level
+type OSEClassSkill struct {
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"` // the skills table's column: "CS"
+	Description  string `json:"description"`
+}
+
+// OSEClassLevel is one row of a class's level progression table
+type OSEClassLevel struct {
+	Level       int      `json:"level"`
+	XP          int      `json:"xp"`
+	HitDice     string   `json:"hit_dice"` // "9d8+2*"
+	THAC0       int      `json:"thac0"`
+	AttackBonus int      `json:"attack_bonus"`
+	Saves       OSESaves `json:"saves"`
+	SpellSlots  []int    `json:"spell_slots,omitempty"` // per spell level, for casters
+	Skills      []string `json:"skills,omitempty"`      // chance of each class skill: "87" on d%, "1–2" on 1d6
+}
+
+// importOSEClasses parses every class file in dir
+func importOSEClasses(dir string) ([]OSEClass, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing OSE classes: %v", err)
+	}
+
+	var classes []OSEClass
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		class, err := parseOSEClass(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		classes = append(classes, class)
+	}
+	return classes, issues, nil
+}
+
+// parseOSEClass reads the summary table under the "# Name" heading and the
+// "## Name Level Progression" table
+func parseOSEClass(text string) (OSEClass, error) {
+	var class OSEClass
+
+	paragraphs := wikiParagraphs(text)
+	if len(paragraphs) < 2 || !strings.HasPrefix(paragraphs[0], "# ") {
+		return class, fmt.Errorf("missing class heading")
+	}
+	class.Name = headingText(paragraphs[0])
+
+	summary := strings.Split(paragraphs[1], "\n")
+	if !strings.HasPrefix(summary[0], "|") {
+		return class, fmt.Errorf("missing class summary table")
+	}
+	class.Demihuman = strings.Contains(summary[0], "Demihuman")
+	for _, row := range tableRows(summary) {
+		if len(row) < 2 {
+			continue
+		}
+		switch row[0] {
+		case "Requirements":
+			class.Requirements = row[1]
+		case "Prime requisite":
+			class.PrimeRequisites = strings.Split(row[1], " and ")
+		case "Hit Dice":
+			class.HitDie = row[1]
+		case "Maximum level":
+			class.MaxLevel, _ = strconv.Atoi(row[1])
+		case "Armor":
+			class.Armor = row[1]
+		case "Weapons":
+			class.Weapons = row[1]
+		case "Languages":
+			class.Languages = row[1]
+		}
+	}
+
+	for _, paragraph := range paragraphs[2:] {
+		if strings.HasPrefix(paragraph, "#") {
+			break
+		}
+		class.Description = joinParagraphs(class.Description, paragraph)
+	}
+
+	class.SpellBook = strings.Contains(text, "spell book")
+
+	rows, err := wikiHeadingTable(text, class.Name+" Level Progression")
+	if err != nil {
+		return class, err
+	}
+	for _, row := range rows {
+		level, err := strconv.Atoi(row[0])
+		if err != nil {
+			continue // the "Level | XP | HD" header row under the group headers
+		}
+		entry, err := parseOSEClassLevel(level, row)
+		if err != nil {
+			return class, fmt.Errorf("level %d: %v", level, err)
+		}
+		class.Levels = append(class.Levels, entry)
+	}
+	if len(class.Levels) == 0 {
+		return class, fmt.Errorf("empty level progression table")
+	}
+
+	for _, match := range oseClassSkillPattern.FindAllStringSubmatch(text, -1) {
+		class.Skills = append(class.Skills, OSEClassSkill{Name: match[1], Abbreviation: match[2], Description: strings.TrimSpace(match[3])})
+	}
+	if len(class.Skills) > 0 {
+		rows, err := wikiHeadingTable(text, class.Name+" Skills")
+		if err != nil {
+			return class, err
+		}
+		for _, row := range rows {
+			level, err := strconv.Atoi(row[0])
+			if err != nil || level < 1 || level > len(class.Levels) || len(row) != len(class.Skills)+1 {
+				return class, fmt.Errorf("bad skills row %q", strings.Join(row, " | "))
+			}
+			class.Levels[level-1].Skills = row[1:]
+		}
+	}
+	return class, nil
+}
+
+// parseOSEClassLevel reads "| 4 | 8,000 | 4d8 | 17 [+2] | 10 | 11 | 12 | 13 | 14 | 2 | — |"
+func parseOSEClassLevel(level int, row []string) (OSEClassLevel, error) {
+	entry := OSEClassLevel{Level: level}
+	if len(row) < 9 {
+		return entry, fmt.Errorf("short row")
+	}
+
+	xp, err := strconv.Atoi(strings.ReplaceAll(row[1], ",", ""))
+	if err != nil {
+		return entry, fmt.Errorf("bad XP %q", row[1])
+	}
+	entry.XP = xp
+	entry.HitDice = row[2]
+
+	thac0 := oseTHAC0Pattern.FindStringSubmatch(row[3])
+	if thac0 == nil {
+		return entry, fmt.Errorf("bad THAC0 %q", row[3])
+	}
+	entry.THAC0, _ = strconv.Atoi(thac0[1])
+	entry.AttackBonus, _ = strconv.Atoi(thac0[2])
+
+	var saves [5]int
+	for i := range saves {
+		if saves[i], err = strconv.Atoi(row[4+i]); err != nil {
+			return entry, fmt.Errorf("bad saving throw %q", row[4+i])
+		}
+	}
+	entry.Saves = OSESaves{Death: saves[0], Wands: saves[1], Paralysis: saves[2], Breath: saves[3], Spells: saves[4]}
+
+	for _, cell := range row[9:] {
+		slots, _ := strconv.Atoi(dashToEmpty(cell)) // "—" is no slots
+		entry.SpellSlots = append(entry.SpellSlots, slots)
+	}
+	return entry, nil
+}
+
+// level returns the progression row for a character level, capped at the
+// class's maximum
+func (c OSEClass) level(level int) (OSEClassLevel, bool) {
+	if len(c.Levels) == 0 || level < 1 {
+		return OSEClassLevel{}, false
+	}
+	return c.Levels[min(level, len(c.Levels))-1], true
+}
+
+// xpModifier is the percentage added to or taken from the XP the class
+// earns, following the prime requisite table in 1. Characters/3. Ability
+// Scores.md. Classes with two prime requisites give their own rule: the
+// halfling's 5% for one at 13 or more, the elf's 10% for the first at 16
+func (c OSEClass) xpModifier(a Abilities) int {
+	switch len(c.PrimeRequisites) {
+	case 0:
+		return 0
+	case 1:
+		switch score := a.score(c.PrimeRequisites[0]); {
+		case score <= 5:
+			return -20
+		case score <= 8:
+			return -10
+		case score <= 12:
+			return 0
+		case score <= 15:
+			return 5
+		}
+		return 10
+	}
+
+	high := 0
+	for _, ability := range c.PrimeRequisites {
+		if a.score(ability) >= 13 {
+			high++
+		}
+	}
+	switch {
+	case high == len(c.PrimeRequisites) && (strings.Contains(c.Description, "in one prime requisite") || a.score(c.PrimeRequisites[0]) >= 16):
+		return 10
+	case high == len(c.PrimeRequisites), high > 0 && strings.Contains(c.Description, "in one prime requisite"):
+		return 5
+	}
+	return 0
+}
+
+// oseClassesDir is the OSE classes folder under the Rules folder
+func oseClassesDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "OSE.SRD.Wiki", "2. Classes")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const oseElfFile = `# Elf
+
+| Demihuman Class |                                                     |
+| --------------- | --------------------------------------------------- |
+| Requirements    | Minimum INT 9                                       |
+| Prime requisite | INT and STR                                         |
+| Hit Dice        | 1d6                                                 |
+| Maximum level   | 10                                                  |
+| Armor           | Any, including shields                              |
+| Weapons         | Any                                                 |
+| Languages       | Alignment, Common, Elvish                           |
+
+Elves are slender, fey demihumans with pointed ears.
+
+## Combat
+
+Elves can use all types of weapons and armor.
+
+## Elf Level Progression
+
+|       | Saving Throws | Spells |         |      |      |      |      |      |      |      |
+| :---: | :-----------: | :----: | :-----: | :--: | :--: | :--: | :--: | :--: | :--: | :--: |
+| Level |      XP       |   HD   |  THAC0  |  D   |  W   |  P   |  B   |  S   |  1   |  2   |
+|   1   |       0       |  1d6   | 19 [0]  |  12  |  13  |  13  |  15  |  15  |  1   |  —   |
+|   4   |    16,000     |  4d6   | 17 [+2] |  10  |  11  |  11  |  13  |  12  |  2   |  2   |
+`
+
+func TestParseOSEClass(t *testing.T) {
+	class, err := parseOSEClass(oseElfFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := class.Levels
+	class.Levels = nil
+	want := OSEClass{
+		Name:            "Elf",
+		Demihuman:       true,
+		Requirements:    "Minimum INT 9",
+		PrimeRequisites: []string{"INT", "STR"},
+		HitDie:          "1d6",
+		MaxLevel:        10,
+		Armor:           "Any, including shields",
+		Weapons:         "Any",
+		Languages:       "Alignment, Common, Elvish",
+		Description:     "Elves are slender, fey demihumans with pointed ears.",
+	}
+	if !reflect.DeepEqual(class, want) {
+		t.Errorf("got %+v, want %+v", class, want)
+	}
+
+	wantLevels := []OSEClassLevel{
+		{Level: 1, XP: 0, HitDice: "1d6", THAC0: 19, AttackBonus: 0, Saves: OSESaves{12, 13, 13, 15, 15}, SpellSlots: []int{1, 0}},
+		{Level: 4, XP: 16000, HitDice: "4d6", THAC0: 17, AttackBonus: 2, Saves: OSESaves{10, 11, 11, 13, 12}, SpellSlots: []int{2, 2}},
+	}
+	if !reflect.DeepEqual(levels, wantLevels) {
+		t.Errorf("levels: got %+v, want %+v", levels, wantLevels)
+	}
+}
+
+func TestParseOSEClassErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"no heading", "Some text\n\nMore text"},
+		{"no summary table", "# Elf\n\nElves are slender."},
+		{"no progression table", "# Elf\n\n| Class | |\n| --- | --- |\n| Hit Dice | 1d6 |"},
+	}
+	for _, tt := range tests {
+		if _, err := parseOSEClass(tt.text); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Old-School Essentials monsters from Rules/OSE.SRD.Wiki/7. Monsters/Monster Stats
+
+type OSEMonster struct {
+	Name            string            `json:"name"`
+	Family          string            `json:"family"` // "Dragon" for "Black Dragon", empty for single entries
+	Description     string            `json:"description"`
+	ArmorClass      int               `json:"armor_class"`  // descending
+	AscendingAC     int               `json:"ascending_ac"` // the [bracketed] value
+	HitDice         string            `json:"hit_dice"`     // "4+1*", asterisks mark special abilities
+	HitPoints       int               `json:"hit_points"`   // average
+	Attacks         string            `json:"attacks"`
+	THAC0           int               `json:"thac0"`
+	AttackBonus     int               `json:"attack_bonus"` // the [bracketed] value
+	Movement        string            `json:"movement"`
+	Saves           OSESaves          `json:"saves"`
+	SaveAs          string            `json:"save_as"` // "4", "NH", "Cleric 1"
+	Morale          int               `json:"morale"`
+	Alignment       string            `json:"alignment"`
+	XP              int               `json:"xp"`
+	NumberAppearing string            `json:"number_appearing"`
+	TreasureType    string            `json:"treasure_type"`
+	Abilities       []MonsterFeature  `json:"abilities"`
+	Stats           map[string]string `json:"stats"` // the stat table exactly as written
+}
+
+// OSESaves holds the five classic saving throw categories
+type OSESaves struct {
+	Death     int `json:"death"`     // death ray or poison
+	Wands     int `json:"wands"`     // magic wands
+	Paralysis int `json:"paralysis"` // paralysis or petrification
+	Breath    int `json:"breath"`    // breath attacks
+	Spells    int `json:"spells"`    // rods, staves or spells
+}
+
+var (
+	oseACPattern      = regexp.MustCompile(`^(-?\d+) \[(-?\d+)\]`)
+	oseTHAC0Pattern   = regexp.MustCompile(`(\d+) \[([+-]?\d+)\]`)
+	oseHPPattern      = regexp.MustCompile(`\((\d+)|^(\d+)hp`)
+	oseSavesPattern   = regexp.MustCompile(`^D(\d+) W(\d+) P(\d+) B(\d+) S(\d+)(?: \(([^)]*)\))?`)
+	oseNumberPattern  = regexp.MustCompile(`^[\d,]+`)
+	oseAbilityPattern = regexp.MustCompile(`^- \*\*([^*]+?):?\*\*:?\s*(.*)$`)
+)
+
+// oseMonsterSection is one heading's worth of a monster file: the "# Bat"
+// main entry or a "## Giant Bat" variant
+type oseMonsterSection struct {
+	name        string
+	description []string
+	stats       map[string]string
+	abilities   []MonsterFeature
+}
+
+// importOSEMonsters parses every file in dir. A file can hold several
+// monsters (one per stat table); files without any table are reported
+func importOSEMonsters(dir string) ([]OSEMonster, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing OSE monsters: %v", err)
+	}
+
+	var monsters []OSEMonster
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		parsed, err := parseOSEMonsters(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		monsters = append(monsters, parsed...)
+	}
+	return monsters, issues, nil
+}
+
+// parseOSEMonsters reads every stat table in a file. Variants inherit the
+// main entry's description and abilities, with their own bullets taking
+// precedence unless they just say "See main entry"
+func parseOSEMonsters(text string) ([]OSEMonster, error) {
+	sections := splitOSEMonsterSections(text)
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("missing monster heading")
+	}
+
+	main := sections[0]
+	var monsters []OSEMonster
+	for _, section := range sections {
+		if len(section.stats) == 0 {
+			continue
+		}
+
+		monster := section.monster()
+		if section != main {
+			monster.Family = main.name
+			monster.Abilities = inheritAbilities(main.abilities, section.abilities)
+			if monster.Description == "" {
+				monster.Description = strings.Join(main.description, "\n\n")
+			}
+		}
+		monsters = append(monsters, monster)
+	}
+
+	if len(monsters) == 0 {
+		return nil, fmt.Errorf("no stat table")
+	}
+	return monsters, nil
+}
+
+func splitOSEMonsterSections(text string) []*oseMonsterSection {
+	var sections []*oseMonsterSection
+	var current *oseMonsterSection
+	var paragraph []string
+
+	flush := func() {
+		if current != nil && len(paragraph) > 0 {
+			current.description = append(current.description, strings.Join(paragraph, " "))
+		}
+		paragraph = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			current = &oseMonsterSection{
+				name:  headingText(trimmed),
+				stats: make(map[string]string),
+			}
+			sections = append(sections, current)
+		case current == nil:
+			continue
+		case trimmed == "" || strings.Trim(trimmed, "-") == "":
+			flush()
+		case strings.HasPrefix(trimmed, "|"):
+			flush()
+			cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+			if len(cells) < 2 {
+				continue
+			}
+			label, value := strings.TrimSpace(cells[0]), strings.TrimSpace(cells[1])
+			if label == "" || strings.Trim(label, "-: ") == "" {
+				continue
+			}
+			current.stats[label] = value
+		case strings.HasPrefix(line, "- "):
+			flush()
+			if match := oseAbilityPattern.FindStringSubmatch(trimmed); match != nil {
+				current.abilities = append(current.abilities, MonsterFeature{Name: match[1], Description: match[2]})
+			} else {
+				current.abilities = append(current.abilities, MonsterFeature{Name: strings.TrimPrefix(trimmed, "- ")})
+			}
+		case strings.HasPrefix(line, " ") && len(current.abilities) > 0:
+			// Nested bullets belong to the ability above them
+			last := &current.abilities[len(current.abilities)-1]
+			last.Description = strings.TrimSpace(last.Description + "\n" + trimmed)
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	return sections
+}
+
+// monster converts the section's stat table into an OSEMonster
+func (s *oseMonsterSection) monster() OSEMonster {
+	monster := OSEMonster{
+		Name:            s.name,
+		Description:     strings.Join(s.description, "\n\n"),
+		Attacks:         s.stats["Attacks"],
+		Movement:        s.stats["Movement"],
+		Alignment:       s.stats["Alignment"],
+		NumberAppearing: s.stats["Number Appearing"],
+		TreasureType:    s.stats["Treasure Type"],
+		Abilities:       s.abilities,
+		Stats:           s.stats,
+	}
+
+	// Where a table lists several values ("2 [17] / 0 [19]") the first is kept;
+	// the full text stays in Stats. Slimes and moulds ("No hit roll required")
+	// keep an AC of 0
+	if match := oseACPattern.FindStringSubmatch(s.stats["Armor Class"]); match != nil {
+		monster.ArmorClass, _ = strconv.Atoi(match[1])
+		monster.AscendingAC, _ = strconv.Atoi(match[2])
+	}
+
+	hitDice := s.stats["Hit Dice"]
+	if i := strings.Index(hitDice, " ("); i >= 0 {
+		monster.HitDice = hitDice[:i]
+	}
+	if match := oseHPPattern.FindStringSubmatch(hitDice); match != nil {
+		monster.HitPoints, _ = strconv.Atoi(match[1] + match[2])
+	}
+
+	if match := oseTHAC0Pattern.FindStringSubmatch(s.stats["THAC0"]); match != nil {
+		monster.THAC0, _ = strconv.Atoi(match[1])
+		monster.AttackBonus, _ = strconv.Atoi(match[2])
+	}
+
+	if match := oseSavesPattern.FindStringSubmatch(s.stats["Saving Throws"]); match != nil {
+		monster.Saves.Death, _ = strconv.Atoi(match[1])
+		monster.Saves.Wands, _ = strconv.Atoi(match[2])
+		monster.Saves.Paralysis, _ = strconv.Atoi(match[3])
+		monster.Saves.Breath, _ = strconv.Atoi(match[4])
+		monster.Saves.Spells, _ = strconv.Atoi(match[5])
+		monster.SaveAs = match[6]
+	}
+
+	if match := oseNumberPattern.FindString(s.stats["Morale"]); match != "" {
+		monster.Morale, _ = strconv.Atoi(match)
+	}
+	if match := oseNumberPattern.FindString(s.stats["XP"]); match != "" {
+		monster.XP, _ = strconv.Atoi(strings.ReplaceAll(match, ",", ""))
+	}
+
+	return monster
+}
+
+// inheritAbilities merges a main entry's abilities into a variant's
+func inheritAbilities(main, variant []MonsterFeature) []MonsterFeature {
+	own := make(map[string]string)
+	for _, ability := range variant {
+		if !strings.HasPrefix(ability.Description, "See main entry") {
+			own[strings.ToLower(ability.Name)] = ability.Description
+		}
+	}
+
+	var merged []MonsterFeature
+	inherited := make(map[string]bool)
+	for _, ability := range main {
+		key := strings.ToLower(ability.Name)
+		if description, ok := own[key]; ok {
+			ability.Description = description
+		}
+		merged = append(merged, ability)
+		inherited[key] = true
+	}
+	for _, ability := range variant {
+		if !inherited[strings.ToLower(ability.Name)] {
+			merged = append(merged, ability)
+		}
+	}
+	return merged
+}
+
+// oseMonstersDir is where the OSE monster files live under the Rules folder
+func oseMonstersDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "OSE.SRD.Wiki", "7. Monsters", "Monster Stats")
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const oseBatFile = `# Bat
+
+Nocturnal, flying mammals that roost in caves or ruins.
+
+- **Echolocation:** Unaffected by effects that impair, modify, or rely on sight.
+
+## Giant Bat
+
+Carnivorous bats that may attack adventurers, if hungry.
+
+| Armor Class      | 6 [13]                        |
+| ---------------- | ----------------------------- |
+| Hit Dice         | 2 (9hp)                       |
+| Attacks          | 1 × bite (1d4)                |
+| THAC0            | 18 [+1]                       |
+| Movement         | 30’ (10’) / 180’ (60’) flying |
+| Saving Throws    | D12 W13 P14 B15 S16 (1)       |
+| Morale           | 8                             |
+| Alignment        | Neutral                       |
+| XP               | 20                            |
+| Number Appearing | 1d10 (1d10)                   |
+| Treasure Type    | None                          |
+
+- **Echolocation:** See main entry.
+
+## Normal Bat
+
+| Armor Class      | 6 [13]                      |
+| ---------------- | --------------------------- |
+| Hit Dice         | 1hp                         |
+| THAC0            | 20 [-1]                     |
+| Saving Throws    | D14 W15 P16 B17 S18 (NH)    |
+| Morale           | 6                           |
+| XP               | 1,100                       |
+
+- **Swarm:** 10 bats can swarm around a target’s head.
+`
+
+func TestParseOSEMonsters(t *testing.T) {
+	monsters, err := parseOSEMonsters(oseBatFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monsters) != 2 {
+		t.Fatalf("got %d monsters, want 2", len(monsters))
+	}
+
+	tests := []struct {
+		want      OSEMonster // without abilities and stats
+		abilities []string
+	}{
+		{
+			want:      OSEMonster{Name: "Giant Bat", Family: "Bat", Description: "Carnivorous bats that may attack adventurers, if hungry.", ArmorClass: 6, AscendingAC: 13, HitDice: "2", HitPoints: 9, THAC0: 18, AttackBonus: 1, Saves: OSESaves{12, 13, 14, 15, 16}, SaveAs: "1", Morale: 8, XP: 20},
+			abilities: []string{"Echolocation: Unaffected by effects that impair, modify, or rely on sight."},
+		},
+		{
+			want:      OSEMonster{Name: "Normal Bat", Family: "Bat", Description: "Nocturnal, flying mammals that roost in caves or ruins.", ArmorClass: 6, AscendingAC: 13, HitPoints: 1, THAC0: 20, AttackBonus: -1, Saves: OSESaves{14, 15, 16, 17, 18}, SaveAs: "NH", Morale: 6, XP: 1100},
+			abilities: []string{"Echolocation: Unaffected by effects that impair, modify, or rely on sight.", "Swarm: 10 bats can swarm around a target’s head."},
+		},
+	}
+	for i, tt := range tests {
+		m := monsters[i]
+		got := OSEMonster{Name: m.Name, Family: m.Family, Description: m.Description, ArmorClass: m.ArmorClass, AscendingAC: m.AscendingAC, HitDice: m.HitDice, HitPoints: m.HitPoints, THAC0: m.THAC0, AttackBonus: m.AttackBonus, Saves: m.Saves, SaveAs: m.SaveAs, Morale: m.Morale, XP: m.XP}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("got %+v, want %+v", got, tt.want)
+		}
+		var abilities []string
+		for _, ability := range m.Abilities {
+			abilities = append(abilities, ability.Name+": "+ability.Description)
+		}
+		if len(abilities) != len(tt.abilities) {
+			t.Errorf("%s: abilities %q, want %q", m.Name, abilities, tt.abilities)
+			continue
+		}
+		for j := range abilities {
+			if abilities[j] != tt.abilities[j] {
+				t.Errorf("%s: ability %q, want %q", m.Name, abilities[j], tt.abilities[j])
+			}
+		}
+	}
+}
+
+func TestParseOSEMonstersErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"no heading", "Just some text"},
+		{"no stat table", "# Dragons\n\nDragons come in many colours."},
+	}
+	for _, tt := range tests {
+		if _, err := parseOSEMonsters(tt.text); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
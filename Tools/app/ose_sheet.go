@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderOSESheet is the class block of an OSE character: the class's
+// level limit, THAC0, AC, the saves as a character sheet abbreviates them,
+// the prime requisites and the class's skills at its level
+func (m Model) renderOSESheet(ruleset oseRuleset) string {
+	class, ok := ruleset.class(m.character.Class)
+	if !ok {
+		return ""
+	}
+	level := max(m.character.Level, 1)
+	row, _ := class.level(level)
+	a := m.character.Abilities
+
+	view := titleStyle.Render(class.Name) + "\n"
+	limit := fmt.Sprintf("Level %d of %d", level, class.MaxLevel)
+	if class.Demihuman {
+		limit += " (demihuman level limit)"
+	}
+	if class.MaxLevel > 0 && level > class.MaxLevel {
+		limit = warningStyle.Render(limit + fmt.Sprintf(": a %s cannot advance past level %d", class.Name, class.MaxLevel))
+	}
+	view += limit + "\n"
+	if class.Requirements != "" && class.Requirements != "None" {
+		view += "Requirements: " + class.Requirements + "\n"
+	}
+
+	view += fmt.Sprintf("THAC0 %d [%+d]  Melee %+d  Missile %+d\n", row.THAC0, row.AttackBonus,
+		row.AttackBonus+ruleset.AbilityModifier(a.Strength), row.AttackBonus+ruleset.AbilityModifier(a.Dexterity))
+	ac := m.armorClass()
+	view += fmt.Sprintf("AC %s (%s)\n", ac, ac.Breakdown())
+
+	saves := fmt.Sprintf("Saves: D%d W%d P%d B%d S%d", row.Saves.Death, row.Saves.Wands, row.Saves.Paralysis, row.Saves.Breath, row.Saves.Spells)
+	if modifier := ruleset.AbilityModifier(a.Wisdom); modifier != 0 {
+		saves += fmt.Sprintf(" (WIS %+d vs magic)", modifier)
+	}
+	view += saves + "\n"
+
+	if len(class.PrimeRequisites) > 0 {
+		var scores []string
+		for _, ability := range class.PrimeRequisites {
+			scores = append(scores, fmt.Sprintf("%s %d", ability, a.score(ability)))
+		}
+		view += fmt.Sprintf("Prime requisites: %s (%+d%% XP)\n", strings.Join(scores, ", "), class.xpModifier(a))
+	}
+
+	if len(class.Skills) > 0 && len(row.Skills) == len(class.Skills) {
+		var skills []string
+		for i, skill := range class.Skills {
+			chance := row.Skills[i]
+			if !strings.Contains(chance, "–") {
+				chance += "%"
+			}
+			skills = append(skills, skill.Abbreviation+" "+chance)
+		}
+		view += "Skills: " + strings.Join(skills, "  ") + "\n"
+	}
+	return view
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderOSESheet(t *testing.T) {
+	tests := []struct {
+		class     string
+		level     int
+		abilities Abilities
+		want      []string
+	}{
+		{"Fighter", 1, Abilities{Strength: 16, Dexterity: 9, Wisdom: 7},
+			[]string{"Level 1 of 14", "THAC0 19 [+0]  Melee +2  Missile +0", "AC 9 [10] (Unarmored 9 [10])", "Saves: D12 W13 P14 B15 S16 (WIS -1 vs magic)", "Prime requisites: STR 16 (+10% XP)"}},
+		{"Thief", 1, Abilities{Dexterity: 13, Wisdom: 10},
+			[]string{"Skills: CS 87%", "HN 1–2"}},
+		{"Halfling", 9, Abilities{Dexterity: 13, Strength: 13, Constitution: 9},
+			[]string{"Level 9 of 8 (demihuman level limit): a Halfling cannot advance past level 8", "Requirements: Minimum CON 9, minimum DEX 9"}},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, true, tt.class, tt.level)
+		m.character.Abilities = tt.abilities
+		sheet := m.renderOSESheet(m.ruleset().(oseRuleset))
+		for _, want := range tt.want {
+			if !strings.Contains(sheet, want) {
+				t.Errorf("%s %d sheet lacks %q:\n%s", tt.class, tt.level, want, sheet)
+			}
+		}
+	}
+}
+
+func TestOSEClassSkills(t *testing.T) {
+	m := casterModel(t, true, "Thief", 1)
+	class, _ := m.ruleset().(oseRuleset).class("Thief")
+	if len(class.Skills) == 0 {
+		t.Fatal("the thief has no skills")
+	}
+	for _, level := range class.Levels {
+		if len(level.Skills) != len(class.Skills) {
+			t.Errorf("thief level %d has %d skill chances for %d skills", level.Level, len(level.Skills), len(class.Skills))
+		}
+	}
+	if fighter, _ := m.ruleset().(oseRuleset).class("Fighter"); len(fighter.Skills) != 0 {
+		t.Errorf("the fighter has skills: %+v", fighter.Skills)
+	}
+}
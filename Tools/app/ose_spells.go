@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Old-School Essentials cleric and magic-user spells from Rules/OSE.SRD.Wiki/4. Magic
+
+type OSESpell struct {
+	Name         string `json:"name"`
+	Class        string `json:"class"` // Cleric or Magic-User
+	Level        int    `json:"level"`
+	Duration     string `json:"duration"`
+	Range        string `json:"range"`
+	Description  string `json:"description"`
+	ReversedForm string `json:"reversed_form"` // name of the reversed entry, if the spell can be reversed
+	ReverseOf    string `json:"reverse_of"`    // set on the reversed entry itself
+}
+
+var (
+	oseSpellLevelPattern = regexp.MustCompile(`^\*(\d+(?:st|nd|rd|th)) Level (.+) Spell\*$`)
+	oseSpellClassMarker  = regexp.MustCompile(`\s*\((C|MU)\)$`)
+	trailingParenPattern = regexp.MustCompile(`\s*\([^)]*\)$`)
+)
+
+const reversedHeading = "## Reversed:"
+
+// importOSESpells parses every spell file in dir. A reversible spell yields
+// two entries so either direction can be prepared
+func importOSESpells(dir string) ([]OSESpell, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing OSE spells: %v", err)
+	}
+
+	var spells []OSESpell
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		parsed, err := parseOSESpell(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		spells = append(spells, parsed...)
+	}
+	return spells, issues, nil
+}
+
+// parseOSESpell reads a "# Name", "*1st Level Cleric Spell*", **Duration**/**Range**
+// spell, returning the reversed form as a second entry when there is one
+func parseOSESpell(text string) ([]OSESpell, error) {
+	var spell OSESpell
+
+	paragraphs := wikiParagraphs(text)
+	if len(paragraphs) < 2 || !strings.HasPrefix(paragraphs[0], "#") {
+		return nil, fmt.Errorf("missing spell heading")
+	}
+	name := oseSpellClassMarker.ReplaceAllString(headingText(paragraphs[0]), "")
+
+	match := oseSpellLevelPattern.FindStringSubmatch(paragraphs[1])
+	if match == nil {
+		return nil, fmt.Errorf("unrecognised level/class line %q", paragraphs[1])
+	}
+	level, err := parseOrdinal(match[1])
+	if err != nil {
+		return nil, fmt.Errorf("bad spell level %q", match[1])
+	}
+	spell.Level = level
+	spell.Class = match[2]
+
+	rest := paragraphs[2:]
+	for len(rest) > 0 && isSpellStatBlock(rest[0]) {
+		for _, line := range strings.Split(rest[0], "\n") {
+			label, value, _ := boldField(line)
+			switch label {
+			case "Duration":
+				spell.Duration = value
+			case "Range":
+				spell.Range = value
+			}
+		}
+		rest = rest[1:]
+	}
+	if spell.Duration == "" || spell.Range == "" {
+		return nil, fmt.Errorf("missing duration or range")
+	}
+
+	var description, reversed []string
+	reversedName := ""
+	for _, paragraph := range rest {
+		switch {
+		case strings.HasPrefix(paragraph, reversedHeading):
+			reversedName = strings.TrimSpace(strings.TrimPrefix(firstLine(paragraph), reversedHeading))
+			if body := strings.TrimSpace(strings.TrimPrefix(paragraph, firstLine(paragraph))); body != "" {
+				reversed = append(reversed, body)
+			}
+		case reversedName != "":
+			reversed = append(reversed, paragraph)
+		default:
+			description = append(description, paragraph)
+		}
+	}
+	spell.Description = strings.Join(description, "\n\n")
+
+	// The heading names both directions: "Cure Light Wounds (Cause Light Wounds)".
+	// A few spells ("Quest (Remove Quest)") have no Reversed section of their own
+	paren := trailingParenPattern.FindString(name)
+	if reversedName == "" && paren == "" {
+		spell.Name = name
+		return []OSESpell{spell}, nil
+	}
+	spell.Name = strings.TrimSpace(strings.TrimSuffix(name, paren))
+	if reversedName == "" {
+		reversedName = strings.Trim(strings.TrimSpace(paren), "()")
+		reversed = []string{fmt.Sprintf("The reverse of *%s*.", strings.ToLower(spell.Name))}
+	}
+	spell.ReversedForm = reversedName
+
+	reverse := spell
+	reverse.Name = reversedName
+	reverse.Description = strings.Join(reversed, "\n\n")
+	reverse.ReversedForm = ""
+	reverse.ReverseOf = spell.Name
+
+	return []OSESpell{spell, reverse}, nil
+}
+
+// oseSpellDirs are the cleric and magic-user spell folders under the Rules folder
+func oseSpellDirs(rulesDir string) []string {
+	magic := filepath.Join(rulesDir, "OSE.SRD.Wiki", "4. Magic")
+	return []string{
+		filepath.Join(magic, "Cleric Spells"),
+		filepath.Join(magic, "Magic-User Spells"),
+	}
+}
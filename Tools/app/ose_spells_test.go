@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOSESpell(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []OSESpell
+		err  bool
+	}{
+		{
+			name: "plain spell",
+			text: "# Sleep\n\n*1st Level Magic-User Spell*\n\n**Duration:** 4d4 turns\n**Range:** 240’\n\nPuts creatures to sleep.",
+			want: []OSESpell{{Name: "Sleep", Class: "Magic-User", Level: 1, Duration: "4d4 turns", Range: "240’", Description: "Puts creatures to sleep."}},
+		},
+		{
+			name: "reversed section",
+			text: "# Cure Light Wounds (Cause Light Wounds)\n\n*1st Level Cleric Spell*\n\n**Duration:** Instant\n**Range:** The caster or a creature touched\n\nHeals 1d6+1 hit points.\n\n## Reversed: Cause Light Wounds\n\nInflicts 1d6+1 hit points of damage.",
+			want: []OSESpell{
+				{Name: "Cure Light Wounds", Class: "Cleric", Level: 1, Duration: "Instant", Range: "The caster or a creature touched", Description: "Heals 1d6+1 hit points.", ReversedForm: "Cause Light Wounds"},
+				{Name: "Cause Light Wounds", Class: "Cleric", Level: 1, Duration: "Instant", Range: "The caster or a creature touched", Description: "Inflicts 1d6+1 hit points of damage.", ReverseOf: "Cure Light Wounds"},
+			},
+		},
+		{
+			name: "reverse named only in the heading",
+			text: "# Quest (Remove Quest) (C)\n\n*5th Level Cleric Spell*\n\n**Duration:** Until the quest is done\n**Range:** 30’\n\nCompels a creature.",
+			want: []OSESpell{
+				{Name: "Quest", Class: "Cleric", Level: 5, Duration: "Until the quest is done", Range: "30’", Description: "Compels a creature.", ReversedForm: "Remove Quest"},
+				{Name: "Remove Quest", Class: "Cleric", Level: 5, Duration: "Until the quest is done", Range: "30’", Description: "The reverse of *quest*.", ReverseOf: "Quest"},
+			},
+		},
+		{
+			name: "missing range",
+			text: "# Light\n\n*1st Level Cleric Spell*\n\n**Duration:** 12 turns\n\nMakes light.",
+			err:  true,
+		},
+		{
+			name: "unknown level line",
+			text: "# Light\n\nA spell\n\n**Duration:** 12 turns",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseOSESpell(tt.text)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
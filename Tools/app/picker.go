@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// catalogPicker is a scrollable list of SRD entries to choose from. While one
+// is open it takes over the keyboard and replaces the active tab's content
+type catalogPicker struct {
+	title   string
+	options []string
+	index   int
+	choose  func(m *Model, index int)
+	cancel  func(m *Model) // run when Esc closes the picker
+}
+
+const pickerPageSize = 10
+
+func (m Model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		picker := m.picker
+		m.picker = nil
+		m.message = "Cancelled"
+		if picker.cancel != nil {
+			picker.cancel(&m)
+		}
+		return m, nil
+	}
+	// Nothing to move to or choose; only Esc closes an empty picker
+	if len(m.picker.options) == 0 {
+		return m, nil
+	}
+	switch msg.String() {
+	case "up", "k":
+		m.picker.index = (m.picker.index - 1 + len(m.picker.options)) % len(m.picker.options)
+	case "down", "j":
+		m.picker.index = (m.picker.index + 1) % len(m.picker.options)
+	case "pgup":
+		m.picker.index = max(m.picker.index-pickerPageSize, 0)
+	case "pgdown":
+		m.picker.index = min(m.picker.index+pickerPageSize, len(m.picker.options)-1)
+	case "enter":
+		picker := m.picker
+		m.picker = nil
+		picker.choose(&m, picker.index)
+	}
+	return m, nil
+}
+
+// afterPicker runs then once a choice is made in the open picker, or
+// straight away when nothing opened one
+func (m *Model) afterPicker(then func(m *Model)) {
+	if m.picker == nil {
+		then(m)
+		return
+	}
+	choose := m.picker.choose
+	m.picker.choose = func(m *Model, index int) {
+		choose(m, index)
+		then(m)
+	}
+}
+
+func (m Model) renderPicker() string {
+	view := titleStyle.Render(m.picker.title) + "\n\n"
+
+	start := max(m.picker.index-pickerPageSize/2, 0)
+	end := min(start+pickerPageSize, len(m.picker.options))
+	start = max(end-pickerPageSize, 0)
+
+	for i := start; i < end; i++ {
+		option := m.picker.options[i]
+		if i == m.picker.index {
+			option = selectedItemStyle.Render(option)
+		}
+		view += option + "\n"
+	}
+	view += fmt.Sprintf("\n%d/%d  ↑/↓ to choose, Enter to add, Esc to cancel", m.picker.index+1, len(m.picker.options))
+	return sectionStyle.Render(view)
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The wiki ships each dataset twice: a folder with one file per entry
+// ("Spells/Aid.md") and a folder of alphabetical bundles ("Spells (Alt)/Spells A.md").
+// This splits the bundles back into entries and diffs the two copies
+
+// WikiEntry is one spell, monster or magic item and where it came from
+type WikiEntry struct {
+	Name       string
+	File       string
+	Paragraphs []string // the entry's body, without its heading
+}
+
+// EntryDiff is the first place the per-file and bundled copies of an entry disagree
+type EntryDiff struct {
+	Name       string
+	File       string
+	BundleFile string
+	Paragraph  int    // 1-based paragraph of the body that differs
+	FileText   string // empty when the per-file copy is shorter
+	BundleText string // empty when the bundled copy is shorter
+}
+
+// ReconcileReport lists the entries that only one of the two copies has,
+// and the ones both have but with different text
+type ReconcileReport struct {
+	Dataset            string
+	FileEntries        int
+	BundleEntries      int
+	MissingFromBundles []WikiEntry
+	MissingFromFiles   []WikiEntry
+	Different          []EntryDiff
+}
+
+var wikiHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+
+// reconcileWiki compares the per-entry folder with the matching "(Alt)" folder
+func reconcileWiki(dataset, filesDir, bundleDir string) (ReconcileReport, []ImportIssue, error) {
+	report := ReconcileReport{Dataset: dataset}
+
+	fileEntries, issues, err := loadWikiEntries(filesDir)
+	if err != nil {
+		return report, nil, err
+	}
+	bundleEntries, bundleIssues, err := loadWikiBundles(bundleDir)
+	if err != nil {
+		return report, nil, err
+	}
+	issues = append(issues, bundleIssues...)
+	report.FileEntries, report.BundleEntries = len(fileEntries), len(bundleEntries)
+
+	files, fileIssues := indexWikiEntries(fileEntries)
+	bundles, bundleIssues := indexWikiEntries(bundleEntries)
+	issues = append(issues, fileIssues...)
+	issues = append(issues, bundleIssues...)
+
+	for _, entry := range fileEntries {
+		bundled, ok := bundles[wikiEntryKey(entry.Name)]
+		if !ok {
+			report.MissingFromBundles = append(report.MissingFromBundles, entry)
+			continue
+		}
+		if diff, differs := diffWikiEntries(entry, bundled); differs {
+			report.Different = append(report.Different, diff)
+		}
+	}
+	for _, entry := range bundleEntries {
+		if _, ok := files[wikiEntryKey(entry.Name)]; !ok {
+			report.MissingFromFiles = append(report.MissingFromFiles, entry)
+		}
+	}
+	return report, issues, nil
+}
+
+// loadWikiEntries reads a per-entry folder, one entry per file
+func loadWikiEntries(dir string) ([]WikiEntry, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing %s: %v", dir, err)
+	}
+
+	var entries []WikiEntry
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		paragraphs := wikiParagraphs(string(data))
+		if len(paragraphs) == 0 || !strings.HasPrefix(paragraphs[0], "#") {
+			issues = append(issues, ImportIssue{File: file, Reason: "missing entry heading"})
+			continue
+		}
+		entries = append(entries, WikiEntry{
+			Name:       headingText(firstLine(paragraphs[0])),
+			File:       file,
+			Paragraphs: paragraphs[1:],
+		})
+	}
+	return entries, issues, nil
+}
+
+// loadWikiBundles reads every bundle in an "(Alt)" folder
+func loadWikiBundles(dir string) ([]WikiEntry, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing %s: %v", dir, err)
+	}
+
+	var entries []WikiEntry
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		// Letters with no entries hold just the title and "none."
+		split := splitWikiBundle(string(data))
+		if len(split) == 0 && len(wikiParagraphs(string(data))) > 2 {
+			issues = append(issues, ImportIssue{File: file, Reason: "no entries found"})
+			continue
+		}
+		for i := range split {
+			split[i].File = file
+		}
+		entries = append(entries, split...)
+	}
+	return entries, issues, nil
+}
+
+// splitWikiBundle cuts a bundle into entries. An entry is any heading whose
+// first line is an italic type line ("*2nd-level abjuration*",
+// "*Large aberration, lawful evil*"); it runs until the next heading of the
+// same or a higher level, so stat blocks nested in an item ("Avatar of Death"
+// in the Deck of Many Things) stay part of it. Group headings such as
+// "## Demons" or "### Black Dragon" are followed by prose or more headings
+// and are skipped
+func splitWikiBundle(text string) []WikiEntry {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	type heading struct {
+		line, level int
+		name        string
+	}
+	var headings []heading
+	for i, line := range lines {
+		if match := wikiHeadingPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			headings = append(headings, heading{i, len(match[1]), strings.TrimSpace(match[2])})
+		}
+	}
+
+	var entries []WikiEntry
+	inside := 0 // headings before this line belong to the previous entry
+	for i, h := range headings {
+		if h.line < inside {
+			continue
+		}
+		end := len(lines)
+		for _, next := range headings[i+1:] {
+			if next.level <= h.level {
+				end = next.line
+				break
+			}
+		}
+
+		paragraphs := wikiParagraphs(strings.Join(lines[h.line+1:end], "\n"))
+		if len(paragraphs) == 0 || !isTypeLine(firstLine(paragraphs[0])) {
+			continue
+		}
+		entries = append(entries, WikiEntry{Name: h.name, Paragraphs: paragraphs})
+		inside = end
+	}
+	return entries
+}
+
+// isTypeLine reports whether line is a single italic line like "*Ring, rare*".
+// A few monsters lose the asterisks ("Large dragon, chaotic evil")
+func isTypeLine(line string) bool {
+	if monsterTypePattern.MatchString(line) {
+		return true
+	}
+	return len(line) > 2 && strings.HasPrefix(line, "*") && !strings.HasPrefix(line, "**") && strings.HasSuffix(line, "*")
+}
+
+// wikiEntryKey matches entries across the two copies. The per-file copies
+// often qualify the name ("Balor (Demon)", "Acolyte (NPC)") where the bundles
+// rely on the group heading instead
+func wikiEntryKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(trailingParenPattern.ReplaceAllString(name, "")))
+}
+
+// indexWikiEntries maps entries by key, reporting names that appear twice
+func indexWikiEntries(entries []WikiEntry) (map[string]WikiEntry, []ImportIssue) {
+	index := make(map[string]WikiEntry)
+	var issues []ImportIssue
+	for _, entry := range entries {
+		key := wikiEntryKey(entry.Name)
+		if previous, ok := index[key]; ok {
+			issues = append(issues, ImportIssue{File: entry.File, Reason: fmt.Sprintf("%s also appears in %s", entry.Name, previous.File)})
+			continue
+		}
+		index[key] = entry
+	}
+	return index, issues
+}
+
+// diffWikiEntries finds the first paragraph where the two copies differ,
+// ignoring differences in whitespace
+func diffWikiEntries(file, bundle WikiEntry) (EntryDiff, bool) {
+	diff := EntryDiff{Name: file.Name, File: file.File, BundleFile: bundle.File}
+	for i := 0; i < max(len(file.Paragraphs), len(bundle.Paragraphs)); i++ {
+		fileText, bundleText := "", ""
+		if i < len(file.Paragraphs) {
+			fileText = normalizeWikiText(file.Paragraphs[i])
+		}
+		if i < len(bundle.Paragraphs) {
+			bundleText = normalizeWikiText(bundle.Paragraphs[i])
+		}
+		if fileText != bundleText {
+			diff.Paragraph = i + 1
+			diff.FileText, diff.BundleText = differingText(fileText, bundleText)
+			return diff, true
+		}
+	}
+	return diff, false
+}
+
+// differingText trims the common prefix of a and b down to a little context
+// so long paragraphs show where they part ways
+func differingText(a, b string) (string, string) {
+	const context = 20
+	ra, rb := []rune(a), []rune(b)
+	common := 0
+	for common < len(ra) && common < len(rb) && ra[common] == rb[common] {
+		common++
+	}
+	start := max(common-context, 0)
+	prefix := ""
+	if start > 0 {
+		prefix = "..."
+	}
+	return prefix + string(ra[start:]), prefix + string(rb[start:])
+}
+
+// normalizeWikiText collapses runs of whitespace, non-breaking spaces included
+func normalizeWikiText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// runReconcile prints the differences between the per-entry and bundled
+// copies of the spells, monsters and magic items.
+// Usage: app reconcile [-rules ../../Rules]
+// The Rules folder defaults to RULES_PATH or the repository's, see wikiDir
+func runReconcile(args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	rulesDir := flags.String("rules", wikiDir(rulesPathEnv, "Rules"), "path to the Rules wiki folder")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	for _, dataset := range []string{"Spells", "Monsters", "Treasure"} {
+		report, issues, err := reconcileWiki(dataset, filepath.Join(srdWikiDir(*rulesDir), dataset), filepath.Join(srdWikiDir(*rulesDir), dataset+" (Alt)"))
+		if err != nil {
+			return err
+		}
+		reportIssues(strings.ToLower(dataset), issues)
+		printReconcileReport(report, *rulesDir)
+	}
+	return nil
+}
+
+func printReconcileReport(report ReconcileReport, rulesDir string) {
+	relative := func(file string) string {
+		if rel, err := filepath.Rel(srdWikiDir(rulesDir), file); err == nil {
+			return rel
+		}
+		return file
+	}
+
+	fmt.Printf("%s: %d per-file entries, %d bundled entries\n", report.Dataset, report.FileEntries, report.BundleEntries)
+	for _, entry := range report.MissingFromBundles {
+		fmt.Printf("  missing from bundles: %s (%s)\n", entry.Name, relative(entry.File))
+	}
+	for _, entry := range report.MissingFromFiles {
+		fmt.Printf("  missing from files:   %s (%s)\n", entry.Name, relative(entry.File))
+	}
+
+	sort.Slice(report.Different, func(i, j int) bool { return report.Different[i].Name < report.Different[j].Name })
+	for _, diff := range report.Different {
+		fmt.Printf("  different: %s, paragraph %d (%s vs %s)\n", diff.Name, diff.Paragraph, relative(diff.File), relative(diff.BundleFile))
+		// %q shows the invisible differences, such as soft hyphens
+		fmt.Printf("    file:   %q\n", truncate(diff.FileText, 80))
+		fmt.Printf("    bundle: %q\n", truncate(diff.BundleText, 80))
+	}
+}
+
+// truncate shortens s to n characters for one-line display
+func truncate(s string, n int) string {
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-3]) + "..."
+}
+
+// srdWikiDir is the 5e SRD wiki under the Rules folder
+func srdWikiDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki")
+}
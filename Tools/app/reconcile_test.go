@@ -0,0 +1,112 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWikiBundle(t *testing.T) {
+	bundle := `# Spells A
+
+## Acid Arrow
+
+*2nd-level evocation*
+
+A shimmering green arrow streaks toward a target.
+
+## Demons
+
+Demons are chaotic.
+
+### Balor
+
+*Huge fiend (demon), chaotic evil*
+
+**Armor Class** 19
+
+## Deck of Many Things
+
+*Wondrous item, legendary*
+
+Usually found in a box.
+
+### Avatar of Death
+
+*Medium undead, neutral evil*
+
+The avatar's stat block.
+`
+	entries := splitWikiBundle(bundle)
+	tests := []struct {
+		name       string
+		paragraphs int
+	}{
+		{"Acid Arrow", 2},
+		{"Balor", 2},
+		{"Deck of Many Things", 5},
+	}
+	if len(entries) != len(tests) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(tests), entries)
+	}
+	for i, tt := range tests {
+		if entries[i].Name != tt.name || len(entries[i].Paragraphs) != tt.paragraphs {
+			t.Errorf("entry %d: got %s with %d paragraphs, want %s with %d", i, entries[i].Name, len(entries[i].Paragraphs), tt.name, tt.paragraphs)
+		}
+	}
+}
+
+func TestDiffWikiEntries(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   []string
+		bundle []string
+		want   EntryDiff
+		differ bool
+	}{
+		{
+			name:   "whitespace only",
+			file:   []string{"*Ring, rare*", "You have  resistance."},
+			bundle: []string{"*Ring, rare*", "You have resistance."},
+		},
+		{
+			name:   "changed word",
+			file:   []string{"*Ring, rare*", "You have resistance to cold damage."},
+			bundle: []string{"*Ring, rare*", "You have resistance to fire damage."},
+			want:   EntryDiff{Name: "Ring", Paragraph: 2, FileText: "... have resistance to cold damage.", BundleText: "... have resistance to fire damage."},
+			differ: true,
+		},
+		{
+			name:   "bundle is shorter",
+			file:   []string{"*Ring, rare*", "Extra."},
+			bundle: []string{"*Ring, rare*"},
+			want:   EntryDiff{Name: "Ring", Paragraph: 2, FileText: "Extra."},
+			differ: true,
+		},
+	}
+	for _, tt := range tests {
+		diff, differ := diffWikiEntries(WikiEntry{Name: "Ring", Paragraphs: tt.file}, WikiEntry{Name: "Ring", Paragraphs: tt.bundle})
+		if differ != tt.differ {
+			t.Errorf("%s: differ = %v, want %v", tt.name, differ, tt.differ)
+			continue
+		}
+		if differ && !reflect.DeepEqual(diff, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, diff, tt.want)
+		}
+	}
+}
+
+func TestWikiEntryKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Balor (Demon)", "balor"},
+		{"Acolyte (NPC)", "acolyte"},
+		{" Acid Arrow", "acid arrow"},
+	}
+	for _, tt := range tests {
+		if got := wikiEntryKey(tt.name); got != tt.want {
+			t.Errorf("wikiEntryKey(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
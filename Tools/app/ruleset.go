@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ruleset is a game system the editor can build characters for. Each
+// Character records the ID of the one it uses, and the derived numbers and
+// the editor tabs follow from it
+type Ruleset interface {
+	ID() string   // stored in Character.Ruleset: "5e", "ose"
+	Name() string // shown in the UI
+
+	AbilityModifier(score int) int
+	ProficiencyBonus(level int) int // 0 for systems without one
+	SavingThrows(c Character) []SavingThrow
+	AttackBonus(c Character, w Weapon) int
+	ArmorClass(c Character, worn []Item) ArmorClass
+
+	Classes() []string
+	Skills() []SkillDefinition // nil for systems without skills
+	Coins() []Coin
+
+	// TabTitle is what the editor calls a tab under this ruleset, "" to hide it
+	TabTitle(tab string) string
+}
+
+// SavingThrow is one save on the character sheet
+type SavingThrow struct {
+	Name       string
+	Value      int    // bonus to the d20 roll, or the number to reach for Target saves
+	Target     bool   // roll Value or higher on a d20, as in OSE
+	Proficient bool   // the class adds its proficiency bonus
+	Bonus      int    // from magic items, already counted in Value
+	Roll       string // "advantage" or "disadvantage" from conditions
+	AutoFail   bool   // a condition fails the save outright
+}
+
+// String renders "+3" for bonus saves and "14" for target numbers, marked
+// "(dis)" or "(adv)" when a condition affects the roll
+func (s SavingThrow) String() string {
+	if s.AutoFail {
+		return "auto-fail"
+	}
+	if !s.Target {
+		return fmt.Sprintf("%+d", s.Value) + rollModeSuffix(s.Roll)
+	}
+	if s.Value == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%d", s.Value) + rollModeSuffix(s.Roll)
+}
+
+// ArmorClass is a character's AC from the armor and shield they wear and
+// how it adds up
+type ArmorClass struct {
+	Value      int      // ascending: higher is better
+	Descending bool     // also give the descending AC, as OSE does
+	Body       bool     // wearing body armor
+	Shield     bool     // carrying a shield
+	Parts      []string // "Chain mail 16", "Shield +2"
+}
+
+// add counts a bonus, such as a Ring of Protection's
+func (a *ArmorClass) add(bonus int, part string) {
+	a.Value += bonus
+	a.Parts = append(a.Parts, part)
+}
+
+// String renders "16", or "5 [14]" for descending ACs
+func (a ArmorClass) String() string {
+	if a.Descending {
+		return fmt.Sprintf("%d [%d]", oseDescendingAC(a.Value), a.Value)
+	}
+	return fmt.Sprintf("%d", a.Value)
+}
+
+// Breakdown joins the parts: "Chain mail 16, Shield +2"
+func (a ArmorClass) Breakdown() string {
+	return strings.Join(a.Parts, ", ")
+}
+
+// SkillDefinition is a skill and the ability it keys off
+type SkillDefinition struct {
+	Name    string
+	Ability string // "Dexterity"
+}
+
+// Coin is one denomination of a ruleset's currency
+type Coin struct {
+	Abbrev string // matches the Currency field: "cp", "gp"
+	Name   string
+	Value  int // in copper pieces
+}
+
+const defaultRulesetID = "5e"
+
+// newRulesets lists the supported rulesets, the default first
+func newRulesets(data SRDData) []Ruleset {
+	return []Ruleset{
+		dnd5eRuleset{classes: data.Classes, armor: data.Armor},
+		oseRuleset{classes: data.OSEClasses},
+	}
+}
+
+// ruleset is the character's ruleset. Characters saved before rulesets
+// existed are 5e
+func (m Model) ruleset() Ruleset {
+	for _, ruleset := range m.rulesets {
+		if ruleset.ID() == m.character.Ruleset {
+			return ruleset
+		}
+	}
+	for _, ruleset := range m.rulesets {
+		if ruleset.ID() == defaultRulesetID {
+			return ruleset
+		}
+	}
+	return dnd5eRuleset{}
+}
+
+// switchRuleset moves the character to the next ruleset, leaving the active
+// tab if the new ruleset hides it
+func (m *Model) switchRuleset() {
+	current := m.ruleset().ID()
+	for i, ruleset := range m.rulesets {
+		if ruleset.ID() == current {
+			m.character.Ruleset = m.rulesets[(i+1)%len(m.rulesets)].ID()
+			break
+		}
+	}
+	if m.ruleset().TabTitle(m.tabs[m.activeTab]) == "" {
+		m.activeTab = m.nextTab(1)
+	}
+	m.message = fmt.Sprintf("Ruleset: %s", m.ruleset().Name())
+}
+
+// nextTab is the index of the next tab in direction step (1 or -1) that the
+// ruleset shows
+func (m Model) nextTab(step int) int {
+	tab := m.activeTab
+	for range m.tabs {
+		tab = (tab + step + len(m.tabs)) % len(m.tabs)
+		if m.ruleset().TabTitle(m.tabs[tab]) != "" {
+			return tab
+		}
+	}
+	return m.activeTab
+}
+
+// score returns the ability score by full name ("Strength") or
+// abbreviation ("STR")
+func (a Abilities) score(ability string) int {
+	switch strings.ToUpper(ability) {
+	case "STRENGTH", "STR":
+		return a.Strength
+	case "DEXTERITY", "DEX":
+		return a.Dexterity
+	case "CONSTITUTION", "CON":
+		return a.Constitution
+	case "INTELLIGENCE", "INT":
+		return a.Intelligence
+	case "WISDOM", "WIS":
+		return a.Wisdom
+	case "CHARISMA", "CHA":
+		return a.Charisma
+	}
+	return 0
+}
+
+var abilityNames = []string{"Strength", "Dexterity", "Constitution", "Intelligence", "Wisdom", "Charisma"}
+
+// amount returns the number of coins of a denomination
+func (c Currency) amount(abbrev string) int {
+	switch abbrev {
+	case "cp":
+		return c.CP
+	case "sp":
+		return c.SP
+	case "ep":
+		return c.EP
+	case "gp":
+		return c.GP
+	case "pp":
+		return c.PP
+	}
+	return 0
+}
+
+// add puts n coins of a denomination in the purse, or takes them out when
+// n is negative
+func (c *Currency) add(abbrev string, n int) {
+	switch abbrev {
+	case "cp":
+		c.CP += n
+	case "sp":
+		c.SP += n
+	case "ep":
+		c.EP += n
+	case "gp":
+		c.GP += n
+	case "pp":
+		c.PP += n
+	}
+}
+
+// value is the worth of the purse in copper pieces under the given coins
+func (c Currency) value(coins []Coin) int {
+	total := 0
+	for _, coin := range coins {
+		total += c.amount(coin.Abbrev) * coin.Value
+	}
+	return total
+}
+
+// isRangedWeapon reports whether a weapon is fired rather than swung,
+// going by its catalog properties
+func isRangedWeapon(w Weapon) bool {
+	return strings.Contains(strings.ToLower(w.Properties), "ammunition")
+}
+
+// isFinesseWeapon reports whether a weapon can use Dexterity in melee
+func isFinesseWeapon(w Weapon) bool {
+	return strings.Contains(strings.ToLower(w.Properties), "finesse")
+}
+
+// formatCopper renders a copper piece amount in gold: "12.34 gp"
+func formatCopper(cp int) string {
+	return fmt.Sprintf("%d.%02d gp", cp/100, cp%100)
+}
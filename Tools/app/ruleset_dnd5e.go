@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// D&D 5th edition, from Rules/DND.SRD.Wiki
+
+type dnd5eRuleset struct {
+	classes []ClassDefinition
+	armor   []SRDArmor
+}
+
+func (dnd5eRuleset) ID() string   { return "5e" }
+func (dnd5eRuleset) Name() string { return "D&D 5e" }
+
+// AbilityModifier is (score - 10) / 2, rounded down
+func (dnd5eRuleset) AbilityModifier(score int) int {
+	if score < 10 {
+		return (score - 11) / 2
+	}
+	return (score - 10) / 2
+}
+
+// ProficiencyBonus is +2 at 1st level, rising by 1 every four levels
+func (dnd5eRuleset) ProficiencyBonus(level int) int {
+	return 2 + (max(level, 1)-1)/4
+}
+
+// SavingThrows are one per ability, with the proficiency bonus added to
+// the two the character's class is proficient in
+func (r dnd5eRuleset) SavingThrows(c Character) []SavingThrow {
+	class, _ := r.class(c.Class)
+	var saves []SavingThrow
+	for _, ability := range abilityNames {
+		save := SavingThrow{Name: ability, Value: r.AbilityModifier(c.Abilities.score(ability))}
+		if containsFold(class.SavingThrows, ability) {
+			save.Proficient = true
+			save.Value += r.ProficiencyBonus(c.Level)
+		}
+		saves = append(saves, save)
+	}
+	return saves
+}
+
+// AttackBonus adds the proficiency bonus to Strength, or to Dexterity for
+// ranged weapons. Finesse weapons use the better of the two
+func (r dnd5eRuleset) AttackBonus(c Character, w Weapon) int {
+	str := r.AbilityModifier(c.Abilities.Strength)
+	dex := r.AbilityModifier(c.Abilities.Dexterity)
+
+	ability := str
+	switch {
+	case isRangedWeapon(w):
+		ability = dex
+	case isFinesseWeapon(w):
+		ability = max(str, dex)
+	}
+	return ability + r.ProficiencyBonus(c.Level)
+}
+
+// ArmorClass is the body armor's AC, adding Dexterity up to the armor's
+// cap, or 10 + Dexterity unarmored. A shield adds its bonus
+func (r dnd5eRuleset) ArmorClass(c Character, worn []Item) ArmorClass {
+	dex := r.AbilityModifier(c.Abilities.Dexterity)
+
+	var body, shield *SRDArmor
+	var magic []string
+	bonus := 0
+	for _, item := range worn {
+		armor, n, ok := findArmor(r.armor, item.Name)
+		if !ok {
+			continue
+		}
+		if armor.Category == "shield" {
+			shield = &armor
+		} else {
+			body = &armor
+		}
+		if n > 0 {
+			bonus += n
+			magic = append(magic, fmt.Sprintf("magic %+d", n))
+		}
+	}
+
+	ac := ArmorClass{Value: 10 + dex, Parts: []string{fmt.Sprintf("10 + Dex %+d", dex)}}
+	if body != nil {
+		ac.Body = true
+		ac.Value = body.BaseAC
+		if body.AddDex {
+			if body.MaxDex > 0 {
+				dex = min(dex, body.MaxDex)
+			}
+			ac.Value += dex
+		}
+		ac.Parts = []string{fmt.Sprintf("%s %d", body.Name, ac.Value)}
+	}
+	ac.Parts = append(ac.Parts, magic...)
+	ac.Value += bonus
+	if shield != nil {
+		ac.Shield = true
+		ac.add(shield.BaseAC, fmt.Sprintf("Shield %+d", shield.BaseAC))
+	}
+	return ac
+}
+
+func (r dnd5eRuleset) Classes() []string {
+	var names []string
+	for _, class := range r.classes {
+		names = append(names, class.Name)
+	}
+	return names
+}
+
+func (dnd5eRuleset) Skills() []SkillDefinition {
+	return []SkillDefinition{
+		{"Acrobatics", "Dexterity"},
+		{"Animal Handling", "Wisdom"},
+		{"Arcana", "Intelligence"},
+		{"Athletics", "Strength"},
+		{"Deception", "Charisma"},
+		{"History", "Intelligence"},
+		{"Insight", "Wisdom"},
+		{"Intimidation", "Charisma"},
+		{"Investigation", "Intelligence"},
+		{"Medicine", "Wisdom"},
+		{"Nature", "Intelligence"},
+		{"Perception", "Wisdom"},
+		{"Performance", "Charisma"},
+		{"Persuasion", "Charisma"},
+		{"Religion", "Intelligence"},
+		{"Sleight of Hand", "Dexterity"},
+		{"Stealth", "Dexterity"},
+		{"Survival", "Wisdom"},
+	}
+}
+
+// Coins follow the Standard Exchange Rates table in Equipment/Coinage.md
+func (dnd5eRuleset) Coins() []Coin {
+	return []Coin{
+		{"cp", "Copper", 1},
+		{"sp", "Silver", 10},
+		{"ep", "Electrum", 50},
+		{"gp", "Gold", 100},
+		{"pp", "Platinum", 1000},
+	}
+}
+
+func (dnd5eRuleset) TabTitle(tab string) string {
+	return tab
+}
+
+// class finds a class by name
+func (r dnd5eRuleset) class(name string) (ClassDefinition, bool) {
+	for _, class := range r.classes {
+		if strings.EqualFold(class.Name, strings.TrimSpace(name)) {
+			return class, true
+		}
+	}
+	return ClassDefinition{}, false
+}
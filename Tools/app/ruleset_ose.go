@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Old-School Essentials, from Rules/OSE.SRD.Wiki
+
+type oseRuleset struct {
+	classes []OSEClass
+}
+
+func (oseRuleset) ID() string   { return "ose" }
+func (oseRuleset) Name() string { return "Old-School Essentials" }
+
+// AbilityModifier follows the STR/DEX/CON/WIS tables in 1. Characters/3. Ability Scores.md
+func (oseRuleset) AbilityModifier(score int) int {
+	return []int{-3, -2, -1, 0, 1, 2, 3}[oseScoreBand(score)]
+}
+
+// oseScoreBand is the row of the ability score tables a score falls in:
+// 3, 4–5, 6–8, 9–12, 13–15, 16–17 or 18
+func oseScoreBand(score int) int {
+	switch {
+	case score <= 3:
+		return 0
+	case score <= 5:
+		return 1
+	case score <= 8:
+		return 2
+	case score <= 12:
+		return 3
+	case score <= 15:
+		return 4
+	case score <= 17:
+		return 5
+	}
+	return 6
+}
+
+// The rest of the ability score tables, by oseScoreBand
+var (
+	oseOpenDoors      = []int{1, 1, 1, 2, 3, 4, 5} // in 6
+	oseLanguages      = []string{"Native (broken speech)", "Native", "Native", "Native", "Native + 1 additional", "Native + 2 additional", "Native + 3 additional"}
+	oseLiteracy       = []string{"Illiterate", "Illiterate", "Basic", "Literate", "Literate", "Literate", "Literate"}
+	oseInitiative     = []int{-2, -1, -1, 0, 1, 1, 2}
+	oseReactions      = []int{-2, -1, -1, 0, 1, 1, 2}
+	oseMaxRetainers   = []int{1, 2, 3, 4, 5, 6, 7}
+	oseRetainerMorale = []int{4, 5, 6, 7, 8, 9, 10}
+)
+
+// abilityEffects describes what each score does, as the ability score
+// tables list it: "melee +1, open doors 3-in-6"
+func (r oseRuleset) abilityEffects(a Abilities) map[string]string {
+	modifier := func(score int) string {
+		if n := r.AbilityModifier(score); n != 0 {
+			return fmt.Sprintf("%+d", n)
+		}
+		return "none"
+	}
+	str, intel := oseScoreBand(a.Strength), oseScoreBand(a.Intelligence)
+	dex, cha := oseScoreBand(a.Dexterity), oseScoreBand(a.Charisma)
+	return map[string]string{
+		"Strength":     fmt.Sprintf("melee %s, open doors %d-in-6", modifier(a.Strength), oseOpenDoors[str]),
+		"Intelligence": fmt.Sprintf("%s, %s", oseLanguages[intel], strings.ToLower(oseLiteracy[intel])),
+		"Wisdom":       fmt.Sprintf("magic saves %s", modifier(a.Wisdom)),
+		"Dexterity":    fmt.Sprintf("AC %s, missile %s, initiative %+d", modifier(a.Dexterity), modifier(a.Dexterity), oseInitiative[dex]),
+		"Constitution": fmt.Sprintf("hit points %s", modifier(a.Constitution)),
+		"Charisma":     fmt.Sprintf("NPC reactions %+d, %d retainers, loyalty %d", oseReactions[cha], oseMaxRetainers[cha], oseRetainerMorale[cha]),
+	}
+}
+
+// ProficiencyBonus is 0: OSE has no proficiency bonus
+func (oseRuleset) ProficiencyBonus(level int) int {
+	return 0
+}
+
+// SavingThrows are the five save categories from the class's level
+// progression table, as target numbers. The WIS modifier counts against
+// magic: the wands and spells saves
+func (r oseRuleset) SavingThrows(c Character) []SavingThrow {
+	var saves OSESaves
+	if class, ok := r.class(c.Class); ok {
+		if level, ok := class.level(c.Level); ok {
+			saves = level.Saves
+			// A bonus is a lower number to reach
+			magic := r.AbilityModifier(c.Abilities.Wisdom)
+			saves.Wands -= magic
+			saves.Spells -= magic
+		}
+	}
+	return []SavingThrow{
+		{Name: "Death / poison", Value: saves.Death, Target: true},
+		{Name: "Wands", Value: saves.Wands, Target: true},
+		{Name: "Paralysis / petrify", Value: saves.Paralysis, Target: true},
+		{Name: "Breath attacks", Value: saves.Breath, Target: true},
+		{Name: "Spells / rods / staves", Value: saves.Spells, Target: true},
+	}
+}
+
+// AttackBonus is the class's ascending attack bonus ("19 [0]" in the THAC0
+// column) plus STR for melee or DEX for missile attacks
+func (r oseRuleset) AttackBonus(c Character, w Weapon) int {
+	bonus := 0
+	if class, ok := r.class(c.Class); ok {
+		if level, ok := class.level(c.Level); ok {
+			bonus = level.AttackBonus
+		}
+	}
+	if isRangedWeapon(w) {
+		return bonus + r.AbilityModifier(c.Abilities.Dexterity)
+	}
+	return bonus + r.AbilityModifier(c.Abilities.Strength)
+}
+
+// OSEArmor is a row of the armor table in 3. Equipment & Services/3.
+// Weapons and Armor.md
+type OSEArmor struct {
+	Name    string
+	AC      int      // ascending, or the bonus for the shield
+	Weight  int      // in coins
+	Heavy   bool     // heavy armor under basic encumbrance
+	Aliases []string // as 5e equipment names it
+}
+
+var oseArmor = []OSEArmor{
+	{Name: "Leather", AC: 12, Weight: 200, Aliases: []string{"Leather armor"}},
+	{Name: "Chainmail", AC: 14, Weight: 400, Heavy: true, Aliases: []string{"Chain mail"}},
+	{Name: "Plate mail", AC: 16, Weight: 500, Heavy: true, Aliases: []string{"Plate", "Plate armor"}},
+	{Name: "Shield", AC: 1, Weight: 100},
+}
+
+// findOSEArmor finds a row of the armor table by name, with the bonus of
+// magic armor such as "Chainmail, +1"
+func findOSEArmor(name string) (OSEArmor, int, bool) {
+	bonus := 0
+	if match := armorBonusPattern.FindStringSubmatch(name); match != nil {
+		name = match[1]
+		bonus, _ = strconv.Atoi(match[2])
+	}
+	for _, armor := range oseArmor {
+		for _, candidate := range append([]string{armor.Name}, armor.Aliases...) {
+			if strings.EqualFold(candidate, strings.TrimSpace(name)) {
+				return armor, bonus, true
+			}
+		}
+	}
+	return OSEArmor{}, 0, false
+}
+
+// oseDescendingAC converts an ascending AC to the descending one: 10 is 9,
+// 14 is 5
+func oseDescendingAC(ac int) int {
+	return 19 - ac
+}
+
+// ArmorClass is 9 [10] unarmored or the armor's AC, with the DEX modifier
+// and a shield's +1
+func (r oseRuleset) ArmorClass(c Character, worn []Item) ArmorClass {
+	ac := ArmorClass{Value: 10, Descending: true, Parts: []string{"Unarmored 9 [10]"}}
+	var shield *OSEArmor
+	var magic []string
+	bonus := 0
+	for _, item := range worn {
+		armor, n, ok := findOSEArmor(item.Name)
+		if !ok {
+			continue
+		}
+		if armor.Name == "Shield" {
+			shield = &armor
+		} else {
+			ac.Body = true
+			ac.Value = armor.AC
+			ac.Parts[0] = fmt.Sprintf("%s %d [%d]", armor.Name, oseDescendingAC(armor.AC), armor.AC)
+		}
+		if n > 0 {
+			bonus += n
+			magic = append(magic, fmt.Sprintf("magic %+d", n))
+		}
+	}
+	if dex := r.AbilityModifier(c.Abilities.Dexterity); dex != 0 {
+		ac.add(dex, fmt.Sprintf("DEX %+d", dex))
+	}
+	ac.Parts = append(ac.Parts, magic...)
+	ac.Value += bonus
+	if shield != nil {
+		ac.Shield = true
+		ac.add(shield.AC, fmt.Sprintf("Shield %+d", shield.AC))
+	}
+	return ac
+}
+
+func (r oseRuleset) Classes() []string {
+	var names []string
+	for _, class := range r.classes {
+		names = append(names, class.Name)
+	}
+	return names
+}
+
+// Skills is nil: OSE characters have no skill list
+func (oseRuleset) Skills() []SkillDefinition {
+	return nil
+}
+
+// Coins follow the conversion table in 3. Equipment & Services/1. Wealth.md,
+// where a platinum piece is worth 5 gp
+func (oseRuleset) Coins() []Coin {
+	return []Coin{
+		{"cp", "Copper", 1},
+		{"sp", "Silver", 10},
+		{"ep", "Electrum", 50},
+		{"gp", "Gold", 100},
+		{"pp", "Platinum", 500},
+	}
+}
+
+// TabTitle hides the 5e-only tabs. OSE characters have languages rather
+// than proficiencies
+func (oseRuleset) TabTitle(tab string) string {
+	switch tab {
+	case "Skills", "Background":
+		return ""
+	case "Proficiencies":
+		return "Languages"
+	}
+	return tab
+}
+
+// class finds a class by name
+func (r oseRuleset) class(name string) (OSEClass, bool) {
+	for _, class := range r.classes {
+		if strings.EqualFold(class.Name, strings.TrimSpace(name)) {
+			return class, true
+		}
+	}
+	return OSEClass{}, false
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestOSEScoreBand(t *testing.T) {
+	tests := []struct {
+		score int
+		want  int
+	}{
+		{3, 0}, {4, 1}, {5, 1}, {6, 2}, {8, 2}, {9, 3}, {12, 3}, {13, 4}, {15, 4}, {16, 5}, {17, 5}, {18, 6},
+	}
+	for _, tt := range tests {
+		if got := oseScoreBand(tt.score); got != tt.want {
+			t.Errorf("oseScoreBand(%d) = %d, want %d", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestOSEAbilityEffects(t *testing.T) {
+	effects := oseRuleset{}.abilityEffects(Abilities{Strength: 18, Dexterity: 5, Constitution: 10, Intelligence: 7, Wisdom: 13, Charisma: 16})
+	want := map[string]string{
+		"Strength":     "melee +3, open doors 5-in-6",
+		"Intelligence": "Native, basic",
+		"Wisdom":       "magic saves +1",
+		"Dexterity":    "AC -2, missile -2, initiative -1",
+		"Constitution": "hit points none",
+		"Charisma":     "NPC reactions +1, 6 retainers, loyalty 9",
+	}
+	for ability, text := range want {
+		if effects[ability] != text {
+			t.Errorf("%s: %q, want %q", ability, effects[ability], text)
+		}
+	}
+}
+
+func TestOSEArmorClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		dexterity int
+		worn      []string
+		want      string
+		breakdown string
+	}{
+		{"unarmored", 10, nil, "9 [10]", "Unarmored 9 [10]"},
+		{"chainmail and shield", 10, []string{"Chainmail", "Shield"}, "4 [15]", "Chainmail 5 [14], Shield +1"},
+		{"5e names", 10, []string{"Chain mail"}, "5 [14]", "Chainmail 5 [14]"},
+		{"plate and DEX", 16, []string{"Plate mail"}, "1 [18]", "Plate mail 3 [16], DEX +2"},
+		{"magic leather", 5, []string{"Leather, +1"}, "8 [11]", "Leather 7 [12], DEX -2, magic +1"},
+		{"not armor", 10, []string{"Lantern"}, "9 [10]", "Unarmored 9 [10]"},
+	}
+	for _, tt := range tests {
+		var worn []Item
+		for _, name := range tt.worn {
+			worn = append(worn, Item{Name: name, Quantity: 1, Equipped: true})
+		}
+		c := Character{Abilities: Abilities{Dexterity: tt.dexterity}}
+		ac := oseRuleset{}.ArmorClass(c, worn)
+		if ac.String() != tt.want || ac.Breakdown() != tt.breakdown {
+			t.Errorf("%s: AC %s (%s), want %s (%s)", tt.name, ac, ac.Breakdown(), tt.want, tt.breakdown)
+		}
+	}
+}
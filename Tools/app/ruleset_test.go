@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestAbilityModifier(t *testing.T) {
+	tests := []struct {
+		score int
+		dnd5e int
+		ose   int
+	}{
+		{3, -4, -3},
+		{5, -3, -2},
+		{8, -1, -1},
+		{9, -1, 0},
+		{10, 0, 0},
+		{12, 1, 0},
+		{13, 1, 1},
+		{16, 3, 2},
+		{18, 4, 3},
+		{20, 5, 3},
+	}
+	for _, tt := range tests {
+		if got := (dnd5eRuleset{}).AbilityModifier(tt.score); got != tt.dnd5e {
+			t.Errorf("5e AbilityModifier(%d) = %d, want %d", tt.score, got, tt.dnd5e)
+		}
+		if got := (oseRuleset{}).AbilityModifier(tt.score); got != tt.ose {
+			t.Errorf("OSE AbilityModifier(%d) = %d, want %d", tt.score, got, tt.ose)
+		}
+	}
+}
+
+func TestProficiencyBonus(t *testing.T) {
+	tests := []struct {
+		level int
+		want  int
+	}{
+		{1, 2}, {4, 2}, {5, 3}, {9, 4}, {13, 5}, {17, 6}, {20, 6},
+	}
+	for _, tt := range tests {
+		if got := (dnd5eRuleset{}).ProficiencyBonus(tt.level); got != tt.want {
+			t.Errorf("ProficiencyBonus(%d) = %d, want %d", tt.level, got, tt.want)
+		}
+		if got := (oseRuleset{}).ProficiencyBonus(tt.level); got != 0 {
+			t.Errorf("OSE ProficiencyBonus(%d) = %d, want 0", tt.level, got)
+		}
+	}
+}
+
+func TestOSESavingThrows(t *testing.T) {
+	data, err := loadSRDData(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleset := oseRuleset{classes: data.OSEClasses}
+	tests := []struct {
+		class  string
+		level  int
+		wisdom int
+		want   []int // death, wands, paralysis, breath, spells
+	}{
+		{"Fighter", 1, 10, []int{12, 13, 14, 15, 16}},
+		{"Elf", 4, 10, []int{10, 11, 11, 13, 12}},
+		{"Fighter", 1, 16, []int{12, 11, 14, 15, 14}}, // WIS +2 against magic
+		{"Fighter", 1, 5, []int{12, 15, 14, 15, 18}},  // WIS -2
+		{"Nobody", 1, 16, []int{0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		saves := ruleset.SavingThrows(Character{Class: tt.class, Level: tt.level, Abilities: Abilities{Wisdom: tt.wisdom}})
+		for i, save := range saves {
+			if save.Value != tt.want[i] || !save.Target {
+				t.Errorf("%s %d, WIS %d: %s = %v, want target %d", tt.class, tt.level, tt.wisdom, save.Name, save, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// "You gain a +1 bonus to AC and saving throws while wearing this ring"
+var itemSaveBonusPattern = regexp.MustCompile(`\+(\d) bonus to (?:AC and |ability checks and )?saving throws`)
+
+// savingThrows are the ruleset's saves with magic item bonuses and the
+// character's conditions applied. Ability-specific effects only reach
+// saves named after an ability
+func (m Model) savingThrows() []SavingThrow {
+	effects := m.conditionEffects()
+	bonus := m.itemSaveBonus()
+	saves := m.ruleset().SavingThrows(m.character)
+	for i := range saves {
+		saves[i].Bonus = bonus
+		if saves[i].Target {
+			// A bonus to the roll is a lower number to reach
+			if saves[i].Value > 0 {
+				saves[i].Value -= bonus
+			}
+		} else {
+			saves[i].Value += bonus
+		}
+		saves[i].Roll = rollMode(effects, "save", saves[i].Name)
+		saves[i].AutoFail = hasEffect(effects, effectSaveAutoFail, saves[i].Name)
+	}
+	return saves
+}
+
+// itemSaveBonus adds up the saving throw bonuses of the equipped magic
+// items, such as a Ring of Protection
+func (m Model) itemSaveBonus() int {
+	bonus := 0
+	for _, item := range m.equipment {
+		if !item.Equipped {
+			continue
+		}
+		if magic, ok := m.srdData.magicItem(item.Name); ok {
+			if match := itemSaveBonusPattern.FindStringSubmatch(magic.Description); match != nil {
+				n, _ := strconv.Atoi(match[1])
+				bonus += n
+			}
+		}
+	}
+	return bonus
+}
+
+// rollSave rolls the selected saving throw: a d20 plus the bonus, or
+// against the target number for OSE saves
+func (m *Model) rollSave() {
+	saves := m.savingThrows()
+	if m.selectedSave < 0 || m.selectedSave >= len(saves) {
+		return
+	}
+	save := saves[m.selectedSave]
+	if save.AutoFail {
+		m.message = fmt.Sprintf("%s save: automatic failure", save.Name)
+		return
+	}
+
+	roll, rolled := rollD20(save.Roll)
+	if !save.Target {
+		m.message = fmt.Sprintf("%s save: %s %+d = %d", save.Name, rolled, save.Value, roll+save.Value)
+		return
+	}
+	if save.Value == 0 {
+		m.message = fmt.Sprintf("%s save: no target number for class %q", save.Name, m.character.Class)
+		return
+	}
+	result := "failed"
+	if roll >= save.Value {
+		result = "saved"
+	}
+	m.message = fmt.Sprintf("%s save: %s against %d, %s", save.Name, rolled, save.Value, result)
+}
+
+// moveSaveSelection moves the saving throw cursor by step, wrapping around
+func (m *Model) moveSaveSelection(step int) {
+	if saves := len(m.savingThrows()); saves > 0 {
+		m.selectedSave = (m.selectedSave + step + saves) % saves
+	}
+}
+
+// renderSavingThrows lists the saves, proficient ones ticked, with the
+// cursor on the one ctrl+d rolls
+func (m Model) renderSavingThrows() string {
+	view := titleStyle.Render("Saving Throws") + "\n"
+	for i, save := range m.savingThrows() {
+		line := fmt.Sprintf("%s: %s", save.Name, save)
+		var notes []string
+		if save.Proficient {
+			notes = append(notes, "proficient")
+		}
+		if save.Bonus != 0 {
+			notes = append(notes, fmt.Sprintf("items %+d", save.Bonus))
+		}
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, ", ") + ")"
+		}
+		if i == m.selectedSave {
+			line = selectedItemStyle.Render(line)
+		}
+		view += line + "\n"
+	}
+	return view + "↑/↓ to select a save, ctrl+d to roll it\n"
+}
@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestSavingThrows(t *testing.T) {
+	data, err := loadSRDData(nil)
+	if err != nil {
+		t.Fatalf("loadSRDData: %v", err)
+	}
+	restrained, ok := data.condition("Restrained")
+	if !ok {
+		t.Fatal("no Restrained condition in the SRD data")
+	}
+
+	tests := []struct {
+		name       string
+		ruleset    Ruleset
+		ring       bool
+		restrained bool
+		want       []SavingThrow
+	}{
+		{"5e wizard", dnd5eRuleset{classes: data.Classes}, false, false, []SavingThrow{
+			{Name: "Strength", Value: 0},
+			{Name: "Dexterity", Value: -1},
+			{Name: "Constitution", Value: 2},
+			{Name: "Intelligence", Value: 6, Proficient: true},
+			{Name: "Wisdom", Value: 4, Proficient: true},
+			{Name: "Charisma", Value: 0},
+		}},
+		{"5e wizard with a ring, restrained", dnd5eRuleset{classes: data.Classes}, true, true, []SavingThrow{
+			{Name: "Strength", Value: 1, Bonus: 1},
+			{Name: "Dexterity", Value: 0, Bonus: 1, Roll: "disadvantage"},
+			{Name: "Constitution", Value: 3, Bonus: 1},
+			{Name: "Intelligence", Value: 7, Proficient: true, Bonus: 1},
+			{Name: "Wisdom", Value: 5, Proficient: true, Bonus: 1},
+			{Name: "Charisma", Value: 1, Bonus: 1},
+		}},
+		{"OSE magic-user with a ring", oseRuleset{classes: data.OSEClasses}, true, false, []SavingThrow{
+			{Name: "Death / poison", Value: 12, Target: true, Bonus: 1},
+			{Name: "Wands", Value: 13, Target: true, Bonus: 1},
+			{Name: "Paralysis / petrify", Value: 12, Target: true, Bonus: 1},
+			{Name: "Breath attacks", Value: 15, Target: true, Bonus: 1},
+			{Name: "Spells / rods / staves", Value: 14, Target: true, Bonus: 1},
+		}},
+	}
+	for _, tt := range tests {
+		m := Model{rulesets: []Ruleset{tt.ruleset}, srdData: data}
+		m.character.Ruleset = tt.ruleset.ID()
+		m.character.Class = "Wizard"
+		if _, ose := tt.ruleset.(oseRuleset); ose {
+			m.character.Class = "Magic-User"
+		}
+		m.character.Level = 5
+		m.character.Abilities = Abilities{Strength: 10, Dexterity: 8, Constitution: 14, Intelligence: 16, Wisdom: 12, Charisma: 10}
+		if tt.ring {
+			m.equipment = append(m.equipment, Item{Name: "Ring of Protection", Quantity: 1, Equipped: true})
+		}
+		if tt.restrained {
+			m.addCondition(restrained, 0)
+		}
+
+		saves := m.savingThrows()
+		if len(saves) != len(tt.want) {
+			t.Fatalf("%s: got %d saves, want %d", tt.name, len(saves), len(tt.want))
+		}
+		for i, want := range tt.want {
+			if saves[i] != want {
+				t.Errorf("%s: save %d = %+v, want %+v", tt.name, i, saves[i], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Full-text search over the markdown in Rules/ and Settings/. Every file is
+// split into sections at its headings, so a hit can say where in the page it
+// is ("Combat > Grappling"), and sections are ranked with BM25
+
+// Environment variables naming the wiki folders, for running the app from
+// somewhere other than Tools/app
+const (
+	rulesPathEnv    = "RULES_PATH"
+	settingsPathEnv = "SETTINGS_PATH"
+)
+
+// wikiDir is where a wiki folder is: the environment variable when set,
+// otherwise the folder of that name in the nearest directory above the
+// working directory or the executable that has one. It falls back to the
+// path from Tools/app
+func wikiDir(env, name string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	var starts []string
+	if wd, err := os.Getwd(); err == nil {
+		starts = append(starts, wd)
+	}
+	if exe, err := os.Executable(); err == nil {
+		starts = append(starts, filepath.Dir(exe))
+	}
+	for _, dir := range starts {
+		for {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				return candidate
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+	return filepath.Join("..", "..", name)
+}
+
+// SearchResult is one matching section
+type SearchResult struct {
+	Path     string   // relative to the searched roots' parent: "Rules/DND.SRD.Wiki/Gameplay/Combat.md"
+	Ruleset  string   // "5e", "ose", or "" for setting material
+	Headings []string // the section's heading path, outermost first
+	Snippet  string
+	Score    float64
+	terms    map[string]bool
+}
+
+// SearchIndex is an inverted index over the sections of every markdown file
+// under its roots. Refresh re-reads only the files that changed
+type SearchIndex struct {
+	roots    []string
+	docs     map[string]*searchDoc
+	postings map[string]map[*searchSection]int // term -> section -> occurrences
+	sections int
+	length   int // total terms in all sections, for the average
+}
+
+type searchDoc struct {
+	path     string
+	display  string
+	ruleset  string
+	modTime  time.Time
+	size     int64
+	sections []*searchSection
+}
+
+type searchSection struct {
+	doc      *searchDoc
+	headings []string
+	text     string // cleaned of markdown, for snippets
+	terms    map[string]int
+	heading  map[string]bool // terms that appear in the section's own heading
+	length   int
+}
+
+var (
+	searchWordPattern     = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	markdownLinkPattern   = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownSymbolPattern = regexp.MustCompile(`[*_|>~` + "`" + `]+|:?-{3,}:?`)
+)
+
+// BM25 parameters
+const (
+	searchK1           = 1.2
+	searchB            = 0.75
+	searchHeadingBoost = 2.0
+	searchSnippetWidth = 160
+)
+
+// newSearchIndex indexes every markdown file under roots
+func newSearchIndex(roots ...string) (*SearchIndex, error) {
+	index := &SearchIndex{
+		roots:    roots,
+		docs:     make(map[string]*searchDoc),
+		postings: make(map[string]map[*searchSection]int),
+	}
+	_, err := index.Refresh()
+	return index, err
+}
+
+// Refresh re-indexes files whose size or modification time changed, adds new
+// files and drops deleted ones. It returns how many files it (re)indexed or removed
+func (idx *SearchIndex) Refresh() (int, error) {
+	seen := make(map[string]bool)
+	changed := 0
+
+	for _, root := range idx.roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || filepath.Ext(path) != ".md" {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			seen[path] = true
+
+			if doc, ok := idx.docs[path]; ok && doc.modTime.Equal(info.ModTime()) && doc.size == info.Size() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			idx.remove(path)
+			idx.add(path, filepath.Join(filepath.Base(root), strings.TrimPrefix(path, root)), info, string(data))
+			changed++
+			return nil
+		})
+		if err != nil {
+			return changed, fmt.Errorf("error indexing %s: %v", root, err)
+		}
+	}
+
+	for path := range idx.docs {
+		if !seen[path] {
+			idx.remove(path)
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// add splits a file into sections and indexes them
+func (idx *SearchIndex) add(path, display string, info fs.FileInfo, text string) {
+	doc := &searchDoc{
+		path:    path,
+		display: filepath.ToSlash(display),
+		ruleset: searchRuleset(path),
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+
+	var headings []string
+	var levels []int
+	var body []string
+	flush := func() {
+		content := strings.Join(body, "\n")
+		if strings.TrimSpace(content) == "" && len(headings) == 0 {
+			return
+		}
+		section := &searchSection{
+			doc:      doc,
+			headings: append([]string(nil), headings...),
+			text:     cleanMarkdown(content),
+			terms:    make(map[string]int),
+			heading:  make(map[string]bool),
+		}
+		if len(headings) > 0 {
+			for _, term := range searchTerms(headings[len(headings)-1]) {
+				section.heading[term] = true
+				section.terms[term]++
+				section.length++
+			}
+		}
+		for _, term := range searchTerms(section.text) {
+			section.terms[term]++
+			section.length++
+		}
+		doc.sections = append(doc.sections, section)
+		body = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		match := wikiHeadingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			body = append(body, line)
+			continue
+		}
+		flush()
+		level := len(match[1])
+		for len(levels) > 0 && levels[len(levels)-1] >= level {
+			levels, headings = levels[:len(levels)-1], headings[:len(headings)-1]
+		}
+		levels = append(levels, level)
+		headings = append(headings, cleanMarkdown(match[2]))
+	}
+	flush()
+
+	for _, section := range doc.sections {
+		for term, count := range section.terms {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[*searchSection]int)
+			}
+			idx.postings[term][section] = count
+		}
+		idx.sections++
+		idx.length += section.length
+	}
+	idx.docs[path] = doc
+}
+
+// remove drops a file's sections from the index
+func (idx *SearchIndex) remove(path string) {
+	doc, ok := idx.docs[path]
+	if !ok {
+		return
+	}
+	for _, section := range doc.sections {
+		for term := range section.terms {
+			delete(idx.postings[term], section)
+			if len(idx.postings[term]) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+		idx.sections--
+		idx.length -= section.length
+	}
+	delete(idx.docs, path)
+}
+
+// Search returns the best sections containing every term of query. A
+// ruleset of "5e" or "ose" keeps that system's pages and the ruleset-neutral
+// ones (Settings); "" searches everything
+func (idx *SearchIndex) Search(query, ruleset string, limit int) []SearchResult {
+	terms := searchTerms(query)
+	if len(terms) == 0 || idx.sections == 0 {
+		return nil
+	}
+
+	// Rarest term first, so the candidate set starts small
+	sort.Slice(terms, func(i, j int) bool { return len(idx.postings[terms[i]]) < len(idx.postings[terms[j]]) })
+	candidates := idx.postings[terms[0]]
+	average := float64(idx.length) / float64(idx.sections)
+	phrase := strings.ToLower(strings.Join(strings.Fields(query), " "))
+
+	var results []SearchResult
+	for section := range candidates {
+		if ruleset != "" && section.doc.ruleset != "" && section.doc.ruleset != ruleset {
+			continue
+		}
+
+		score := 0.0
+		for _, term := range terms {
+			tf, ok := idx.postings[term][section]
+			if !ok {
+				score = -1
+				break
+			}
+			df := float64(len(idx.postings[term]))
+			idf := math.Log(1 + (float64(idx.sections)-df+0.5)/(df+0.5))
+			weight := float64(tf) * (searchK1 + 1) / (float64(tf) + searchK1*(1-searchB+searchB*float64(section.length)/average))
+			if section.heading[term] {
+				weight += searchHeadingBoost
+			}
+			score += idf * weight
+		}
+		if score < 0 {
+			continue
+		}
+		if len(terms) > 1 && strings.Contains(strings.ToLower(section.text), phrase) {
+			score *= 1.5
+		}
+
+		termSet := make(map[string]bool)
+		for _, term := range terms {
+			termSet[term] = true
+		}
+		results = append(results, SearchResult{
+			Path:     section.doc.display,
+			Ruleset:  section.doc.ruleset,
+			Headings: section.headings,
+			Score:    score,
+			terms:    termSet,
+			Snippet:  section.text, // cut down below, once the results are ranked
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Path < results[j].Path
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		results[i].Snippet = searchSnippet(results[i].Snippet, results[i].terms)
+	}
+	return results
+}
+
+// Highlight wraps the query terms in the snippet with mark
+func (r SearchResult) Highlight(mark func(string) string) string {
+	return searchWordPattern.ReplaceAllStringFunc(r.Snippet, func(word string) string {
+		if r.terms[normalizeSearchTerm(word)] {
+			return mark(word)
+		}
+		return word
+	})
+}
+
+// Location is the heading path, or the file name for text above the first heading
+func (r SearchResult) Location() string {
+	if len(r.Headings) == 0 {
+		return strings.TrimSuffix(filepath.Base(r.Path), ".md")
+	}
+	return strings.Join(r.Headings, " > ")
+}
+
+// searchSnippet cuts a window of text around the first query term
+func searchSnippet(text string, terms map[string]bool) string {
+	start := 0
+	for _, loc := range searchWordPattern.FindAllStringIndex(text, -1) {
+		if terms[normalizeSearchTerm(text[loc[0]:loc[1]])] {
+			start = loc[0]
+			break
+		}
+	}
+
+	runes := []rune(text)
+	from := len([]rune(text[:start])) - searchSnippetWidth/4
+	prefix, suffix := "…", "…"
+	if from <= 0 {
+		from, prefix = 0, ""
+	}
+	to := from + searchSnippetWidth
+	if to >= len(runes) {
+		to, suffix = len(runes), ""
+	}
+
+	// Don't cut words in half
+	for from > 0 && from < to && !unicode.IsSpace(runes[from-1]) {
+		from++
+	}
+	for to < len(runes) && to > from && !unicode.IsSpace(runes[to]) {
+		to--
+	}
+	return prefix + strings.TrimSpace(string(runes[from:to])) + suffix
+}
+
+// searchTerms splits text into normalised index terms
+func searchTerms(text string) []string {
+	var terms []string
+	for _, word := range searchWordPattern.FindAllString(text, -1) {
+		if term := normalizeSearchTerm(word); len(term) > 1 {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// normalizeSearchTerm lower-cases a word and folds simple plurals, so
+// "Creatures" finds "creature"
+func normalizeSearchTerm(word string) string {
+	term := strings.ToLower(word)
+	if len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") {
+		term = strings.TrimSuffix(term, "s")
+	}
+	return term
+}
+
+// cleanMarkdown strips emphasis, table pipes and link targets and collapses
+// whitespace, leaving readable text
+func cleanMarkdown(text string) string {
+	text = markdownLinkPattern.ReplaceAllString(text, "$1")
+	text = markdownSymbolPattern.ReplaceAllString(text, " ")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// searchRuleset tells which system a file belongs to from its wiki folder
+func searchRuleset(path string) string {
+	slashed := filepath.ToSlash(path)
+	switch {
+	case strings.Contains(slashed, "DND.SRD.Wiki/"):
+		return "5e"
+	case strings.Contains(slashed, "OSE.SRD.Wiki/"):
+		return "ose"
+	}
+	return ""
+}
+
+// runSearch prints the best matches for a query.
+// Usage: app search [-rules ../../Rules] [-settings ../../Settings] [-ruleset 5e|ose] [-n 10] words...
+// The folders default to RULES_PATH and SETTINGS_PATH, or the repository's
+// own, see wikiDir
+func runSearch(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	rulesDir := flags.String("rules", wikiDir(rulesPathEnv, "Rules"), "path to the Rules wiki folder")
+	settingsDir := flags.String("settings", wikiDir(settingsPathEnv, "Settings"), "path to the Settings folder")
+	ruleset := flags.String("ruleset", "", "only search this ruleset's wiki (5e or ose)")
+	limit := flags.Int("n", 10, "number of results to show")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	query := strings.Join(flags.Args(), " ")
+	if query == "" {
+		return fmt.Errorf("no search terms given")
+	}
+
+	index, err := newSearchIndex(*rulesDir, *settingsDir)
+	if err != nil {
+		return err
+	}
+	results := index.Search(query, *ruleset, *limit)
+	if len(results) == 0 {
+		fmt.Printf("No matches for %q\n", query)
+		return nil
+	}
+	for _, result := range results {
+		fmt.Printf("%s (%s)\n", result.Location(), result.Path)
+		fmt.Printf("    %s\n\n", result.Highlight(func(word string) string { return "**" + word + "**" }))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchScreen is the wiki search overlay. Like the catalog picker it takes
+// over the keyboard and replaces the active tab's content while open
+type searchScreen struct {
+	input   textinput.Model
+	ruleset string // "" for all wikis
+	results []SearchResult
+	index   int
+}
+
+const (
+	searchPageSize = 5
+	searchLimit    = 50
+)
+
+// openSearch shows the search screen, filtered to the character's ruleset
+func (m *Model) openSearch() {
+	input := textinput.New()
+	input.Placeholder = "Search the rules..."
+	input.Focus()
+	m.search = &searchScreen{input: input, ruleset: m.ruleset().ID()}
+}
+
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.search = nil
+		return m, nil
+	case "up":
+		if len(m.search.results) > 0 {
+			m.search.index = (m.search.index - 1 + len(m.search.results)) % len(m.search.results)
+		}
+		return m, nil
+	case "down":
+		if len(m.search.results) > 0 {
+			m.search.index = (m.search.index + 1) % len(m.search.results)
+		}
+		return m, nil
+	case "tab":
+		m.search.ruleset = m.nextSearchRuleset()
+		m.runSearch()
+		return m, nil
+	case "enter":
+		m.runSearch()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.search.input, cmd = m.search.input.Update(msg)
+	return m, cmd
+}
+
+// runSearch queries the index, building it on first use and picking up
+// edited wiki files on later searches
+func (m *Model) runSearch() {
+	query := m.search.input.Value()
+	if query == "" {
+		return
+	}
+
+	if m.searchIndex == nil {
+		index, err := newSearchIndex(wikiDir(rulesPathEnv, "Rules"), wikiDir(settingsPathEnv, "Settings"))
+		if err != nil {
+			m.message = fmt.Sprintf("Error building search index: %v", err)
+			return
+		}
+		m.searchIndex = index
+	} else if _, err := m.searchIndex.Refresh(); err != nil {
+		m.message = fmt.Sprintf("Error updating search index: %v", err)
+	}
+
+	m.search.results = m.searchIndex.Search(query, m.search.ruleset, searchLimit)
+	m.search.index = 0
+	m.message = fmt.Sprintf("%d results for %q", len(m.search.results), query)
+}
+
+// nextSearchRuleset cycles the filter through all wikis and each ruleset's
+func (m Model) nextSearchRuleset() string {
+	options := []string{""}
+	for _, ruleset := range m.rulesets {
+		options = append(options, ruleset.ID())
+	}
+	for i, option := range options {
+		if option == m.search.ruleset {
+			return options[(i+1)%len(options)]
+		}
+	}
+	return ""
+}
+
+func (m Model) renderSearch() string {
+	view := titleStyle.Render("Search Rules") + "\n\n"
+	view += m.search.input.View() + "\n"
+
+	filter := "all rulesets"
+	for _, ruleset := range m.rulesets {
+		if ruleset.ID() == m.search.ruleset {
+			filter = ruleset.Name()
+		}
+	}
+	view += fmt.Sprintf("Searching: %s (Tab to change)\n\n", filter)
+
+	results := m.search.results
+	start := max(m.search.index-searchPageSize/2, 0)
+	end := min(start+searchPageSize, len(results))
+	start = max(end-searchPageSize, 0)
+
+	for i := start; i < end; i++ {
+		location := results[i].Location()
+		if i == m.search.index {
+			location = selectedItemStyle.Render(location)
+		}
+		view += location + "\n"
+		view += statusStyle.Render(results[i].Path) + "\n"
+		view += results[i].Highlight(func(word string) string { return equippedStyle.Render(word) }) + "\n\n"
+	}
+	if len(results) > 0 {
+		view += fmt.Sprintf("%d/%d  ", m.search.index+1, len(results))
+	}
+	view += "Enter to search, ↑/↓ to browse, Esc to close"
+	return sectionStyle.Render(view)
+}
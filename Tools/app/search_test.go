@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeWiki creates files under dir from relative path -> content
+func writeWiki(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	writeWiki(t, dir, map[string]string{
+		"Rules/DND.SRD.Wiki/Combat.md": "# Combat\n\nRoll initiative.\n\n## Grappling\n\nWhen you want to grab a creature, you make a grapple check.",
+		"Rules/OSE.SRD.Wiki/Combat.md": "# Combat\n\nRoll 1d6 for initiative each round.",
+		"Settings/Town.md":             "# Millbrook\n\nThe inn holds an initiative contest at midsummer.",
+	})
+	index, err := newSearchIndex(filepath.Join(dir, "Rules"), filepath.Join(dir, "Settings"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query   string
+		ruleset string
+		want    []string // "path: location" of each result, best first
+	}{
+		{"grapple", "", []string{"Rules/DND.SRD.Wiki/Combat.md: Combat > Grappling"}},
+		{"Grappling creatures", "", []string{"Rules/DND.SRD.Wiki/Combat.md: Combat > Grappling"}},
+		{"grapple", "ose", nil},
+		{"initiative round", "", []string{"Rules/OSE.SRD.Wiki/Combat.md: Combat"}},
+		{"midsummer", "ose", []string{"Settings/Town.md: Millbrook"}},
+		{"a", "", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, result := range index.Search(tt.query, tt.ruleset, 10) {
+			got = append(got, filepath.ToSlash(result.Path)+": "+result.Location())
+		}
+		if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+			t.Errorf("Search(%q, %q) = %q, want %q", tt.query, tt.ruleset, got, tt.want)
+		}
+	}
+
+	// Edited and deleted files are picked up by Refresh
+	writeWiki(t, dir, map[string]string{"Settings/Town.md": "# Millbrook\n\nThe mill burned down."})
+	if err := os.Remove(filepath.Join(dir, "Rules/OSE.SRD.Wiki/Combat.md")); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := index.Refresh(); err != nil || changed != 2 {
+		t.Errorf("Refresh() = %d, %v, want 2 files changed", changed, err)
+	}
+	if results := index.Search("initiative", "", 10); len(results) != 1 {
+		t.Errorf("after refresh, initiative matched %d sections, want 1", len(results))
+	}
+}
+
+func TestNormalizeSearchTerm(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"Creatures", "creature"},
+		{"class", "class"},
+		{"its", "its"},
+		{"Spells", "spell"},
+	}
+	for _, tt := range tests {
+		if got := normalizeSearchTerm(tt.word); got != tt.want {
+			t.Errorf("normalizeSearchTerm(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestWikiDir(t *testing.T) {
+	root := t.TempDir()
+	writeWiki(t, root, map[string]string{"Rules/README.md": "", "Tools/app/main.go": ""})
+	t.Chdir(filepath.Join(root, "Tools", "app"))
+
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", filepath.Join(root, "Rules")},
+		{"/srv/wiki/Rules", "/srv/wiki/Rules"},
+	}
+	for _, tt := range tests {
+		t.Setenv(rulesPathEnv, tt.env)
+		if got := wikiDir(rulesPathEnv, "Rules"); got != tt.want {
+			t.Errorf("%s=%q: wikiDir = %q, want %q", rulesPathEnv, tt.env, got, tt.want)
+		}
+	}
+}
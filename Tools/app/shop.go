@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The shop sells from the SRD catalog and buys the character's gear back at
+// a share of its cost, half unless Character.SellRate says otherwise, as in
+// Equipment/Selling Treasure.md. Prices are paid in the ruleset's coins,
+// with change given back
+
+// costPattern matches a catalog price: "2 gp", "1,500 gp", "5 cp"
+var costPattern = regexp.MustCompile(`^([\d,]+)\s*(cp|sp|ep|gp|pp)$`)
+
+// defaultSellRate is what merchants pay for undamaged weapons, armor and
+// other equipment: half their cost
+const defaultSellRate = 50
+
+// sellRates are the shop's choices of rate, in percent of the cost. Gems,
+// art objects and trade goods keep their full value
+var sellRates = []int{25, 50, 75, 100}
+
+// shopItem is something on sale and how it reaches the character
+type shopItem struct {
+	name string
+	cost string
+	add  func(m *Model)
+}
+
+// parseCost reads a price as copper pieces under the given coins
+func parseCost(cost string, coins []Coin) (int, bool) {
+	match := costPattern.FindStringSubmatch(strings.TrimSpace(cost))
+	if match == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(match[1], ",", ""))
+	if err != nil {
+		return 0, false
+	}
+	for _, coin := range coins {
+		if coin.Abbrev == match[2] {
+			return n * coin.Value, true
+		}
+	}
+	return 0, false
+}
+
+// pay takes a price in copper pieces from the purse. Coins go from the
+// smallest up without paying more than is owed; what's left is covered by
+// one larger coin and the change comes back in the fewest coins. It
+// reports false, taking nothing, when the purse is worth too little
+func (m *Model) pay(price int) bool {
+	coins := m.ruleset().Coins()
+	purse := &m.character.Currency
+	if purse.value(coins) < price {
+		return false
+	}
+	owed := price
+	for _, coin := range coins {
+		n := min(purse.amount(coin.Abbrev), owed/coin.Value)
+		purse.add(coin.Abbrev, -n)
+		owed -= n * coin.Value
+	}
+	if owed == 0 {
+		return true
+	}
+	// The largest coin left is worth more than what's owed, or it would
+	// have been spent above
+	for _, coin := range coins {
+		if purse.amount(coin.Abbrev) > 0 && coin.Value >= owed {
+			purse.add(coin.Abbrev, -1)
+			m.receive(coin.Value - owed)
+			return true
+		}
+	}
+	return true
+}
+
+// receive puts copper pieces' worth of coins in the purse, largest first
+func (m *Model) receive(cp int) {
+	coins := m.ruleset().Coins()
+	for i := len(coins) - 1; i >= 0; i-- {
+		m.character.Currency.add(coins[i].Abbrev, cp/coins[i].Value)
+		cp %= coins[i].Value
+	}
+}
+
+// sellRate is the percentage of an item's cost merchants pay for it
+func (m Model) sellRate() int {
+	if m.character.SellRate > 0 {
+		return m.character.SellRate
+	}
+	return defaultSellRate
+}
+
+// openShop is the shop's counter: a catalog to buy from, the character's
+// gear to sell, and the selling rate
+func (m *Model) openShop() {
+	coins := m.ruleset().Coins()
+	options := []string{
+		"Buy adventuring gear and tools",
+		"Buy weapons",
+		"Buy armor",
+		"Sell",
+		fmt.Sprintf("Selling rate: %d%% of cost", m.sellRate()),
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("Shop (purse: %s)", formatCopper(m.character.Currency.value(coins))),
+		options: options,
+		choose: func(m *Model, index int) {
+			switch index {
+			case 0, 1, 2:
+				m.openShopCatalog(options[index], m.shopCatalog(index), 0)
+			case 3:
+				m.openSellPicker(0)
+			case 4:
+				m.openSellRatePicker()
+			}
+		},
+	}
+}
+
+// shopCatalog is the gear (0), weapons (1) or armor (2) the SRD prices
+func (m Model) shopCatalog(kind int) []shopItem {
+	var items []shopItem
+	switch kind {
+	case 0:
+		for _, e := range m.srdData.Equipment {
+			e := e
+			items = append(items, shopItem{e.Name, e.Cost, func(m *Model) { m.addShopItem(itemFromEquipment(e)) }})
+		}
+	case 1:
+		for _, w := range m.srdData.Weapons {
+			w := w
+			items = append(items, shopItem{w.Name, w.Cost, func(m *Model) { m.weapons = append(m.weapons, weaponFromSRD(w)) }})
+		}
+	case 2:
+		for _, a := range m.srdData.Armor {
+			a := a
+			items = append(items, shopItem{a.Name, a.Cost, func(m *Model) { m.equipment = append(m.equipment, itemFromArmor(a)) }})
+		}
+	}
+	return items
+}
+
+// addShopItem adds bought gear to the inventory, stacking it on an
+// unequipped item of the same name
+func (m *Model) addShopItem(item Item) {
+	for i := range m.equipment {
+		if strings.EqualFold(m.equipment[i].Name, item.Name) && !m.equipment[i].Equipped {
+			m.equipment[i].Quantity = max(m.equipment[i].Quantity, 1) + 1
+			return
+		}
+	}
+	m.equipment = append(m.equipment, item)
+}
+
+// openShopCatalog lists what's on sale with prices, staying open at the
+// same place after each purchase. Items the purse can't cover are refused
+func (m *Model) openShopCatalog(title string, items []shopItem, index int) {
+	if len(items) == 0 {
+		m.message = "Nothing on sale"
+		return
+	}
+	coins := m.ruleset().Coins()
+	var options []string
+	for _, item := range items {
+		options = append(options, fmt.Sprintf("%s (%s)", item.name, item.cost))
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("%s (purse: %s)", title, formatCopper(m.character.Currency.value(coins))),
+		options: options,
+		index:   index,
+		choose: func(m *Model, index int) {
+			item := items[index]
+			price, ok := parseCost(item.cost, coins)
+			switch {
+			case !ok:
+				m.message = fmt.Sprintf("%s has no price", item.name)
+			case !m.pay(price):
+				m.message = fmt.Sprintf("Can't afford %s: it costs %s, the purse holds %s", item.name, item.cost, formatCopper(m.character.Currency.value(coins)))
+			default:
+				item.add(m)
+				m.character.Equipment, m.character.Weapons = m.equipment, m.weapons
+				m.updateInputsFromCharacter()
+				m.message = fmt.Sprintf("Bought %s for %s", item.name, item.cost)
+			}
+			m.openShopCatalog(title, items, index)
+		},
+	}
+}
+
+// openSellPicker lists the character's unequipped gear and weapons with
+// what the shop pays for each. Selling one of a stack sells a single item
+func (m *Model) openSellPicker(index int) {
+	coins := m.ruleset().Coins()
+	type sale struct {
+		name   string
+		cost   string
+		remove func(m *Model)
+	}
+	var sales []sale
+	for i, item := range m.equipment {
+		if item.Equipped {
+			continue
+		}
+		i := i
+		sales = append(sales, sale{item.Name, item.Cost, func(m *Model) {
+			if m.equipment[i].Quantity > 1 {
+				m.equipment[i].Quantity--
+				return
+			}
+			m.equipment = append(m.equipment[:i], m.equipment[i+1:]...)
+		}})
+	}
+	for i, weapon := range m.weapons {
+		if weapon.Equipped {
+			continue
+		}
+		i := i
+		sales = append(sales, sale{weapon.Name, weapon.Cost, func(m *Model) {
+			m.weapons = append(m.weapons[:i], m.weapons[i+1:]...)
+		}})
+	}
+	if len(sales) == 0 {
+		m.message = "Nothing to sell; equipped gear has to be unequipped first"
+		return
+	}
+
+	rate := m.sellRate()
+	var options []string
+	for _, s := range sales {
+		offer := "no offer"
+		if cost, ok := parseCost(s.cost, coins); ok {
+			offer = formatCopper(cost * rate / 100)
+		}
+		options = append(options, fmt.Sprintf("%s (%s)", s.name, offer))
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("Sell at %d%% of cost (purse: %s)", rate, formatCopper(m.character.Currency.value(coins))),
+		options: options,
+		index:   min(index, len(options)-1),
+		choose: func(m *Model, index int) {
+			s := sales[index]
+			cost, ok := parseCost(s.cost, coins)
+			if !ok {
+				m.message = fmt.Sprintf("No one offers anything for %s", s.name)
+				m.openSellPicker(index)
+				return
+			}
+			s.remove(m)
+			m.receive(cost * rate / 100)
+			m.character.Equipment, m.character.Weapons = m.equipment, m.weapons
+			m.updateInputsFromCharacter()
+			m.openSellPicker(index)
+			m.message = fmt.Sprintf("Sold %s for %s", s.name, formatCopper(cost*rate/100))
+		},
+	}
+}
+
+// openSellRatePicker sets what merchants pay, as a share of the cost
+func (m *Model) openSellRatePicker() {
+	var options []string
+	for _, rate := range sellRates {
+		option := fmt.Sprintf("%d%% of cost", rate)
+		switch rate {
+		case 50:
+			option += " (arms, armor and other equipment)"
+		case 100:
+			option += " (gems, art objects and trade goods)"
+		}
+		options = append(options, option)
+	}
+	m.picker = &catalogPicker{
+		title:   "Selling Rate",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.character.SellRate = sellRates[index]
+			m.message = "Selling at " + options[index]
+			m.openShop()
+		},
+	}
+}
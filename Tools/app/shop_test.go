@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestParseCost(t *testing.T) {
+	tests := []struct {
+		ruleset Ruleset
+		cost    string
+		want    int
+		ok      bool
+	}{
+		{dnd5eRuleset{}, "1 pp", 1000, true},
+		{oseRuleset{}, "1 pp", 500, true},
+		{dnd5eRuleset{}, "2 gp", 200, true},
+		{dnd5eRuleset{}, "1,500 gp", 150000, true},
+		{oseRuleset{}, "5 cp", 5, true},
+		{dnd5eRuleset{}, "—", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseCost(tt.cost, tt.ruleset.Coins())
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: parseCost(%q) = %d, %v, want %d, %v", tt.ruleset.ID(), tt.cost, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPay(t *testing.T) {
+	tests := []struct {
+		name  string
+		purse Currency
+		price int
+		want  Currency
+		ok    bool
+	}{
+		{"exact coins", Currency{GP: 2}, 100, Currency{GP: 1}, true},
+		{"change from a larger coin", Currency{SP: 3, GP: 1}, 35, Currency{CP: 5, SP: 4, EP: 1}, true},
+		{"smallest coins first", Currency{CP: 20, SP: 5}, 25, Currency{CP: 5, SP: 4}, true},
+		{"unaffordable", Currency{CP: 4, SP: 3}, 50, Currency{CP: 4, SP: 3}, false},
+	}
+	for _, ruleset := range []Ruleset{dnd5eRuleset{}, oseRuleset{}} {
+		for _, tt := range tests {
+			m := Model{rulesets: []Ruleset{ruleset}}
+			m.character.Ruleset = ruleset.ID()
+			m.character.Currency = tt.purse
+			if ok := m.pay(tt.price); ok != tt.ok {
+				t.Errorf("%s, %s: pay(%d) = %v, want %v", ruleset.ID(), tt.name, tt.price, ok, tt.ok)
+			}
+			if m.character.Currency != tt.want {
+				t.Errorf("%s, %s: purse is %+v, want %+v", ruleset.ID(), tt.name, m.character.Currency, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// skillRows is what the Skills tab lists: every skill of the ruleset with
+// the character's entry for it, then custom skills the ruleset doesn't know
+func (m Model) skillRows() []skillRow {
+	entries := make(map[string]Skill)
+	for _, skill := range m.skillList {
+		entries[strings.ToLower(skill.Name)] = skill
+	}
+
+	var rows []skillRow
+	for _, definition := range m.ruleset().Skills() {
+		skill, ok := entries[strings.ToLower(definition.Name)]
+		if !ok {
+			skill = Skill{Name: definition.Name}
+		}
+		delete(entries, strings.ToLower(definition.Name))
+		rows = append(rows, skillRow{Skill: skill, Ability: definition.Ability})
+	}
+	for _, skill := range m.skillList {
+		if _, custom := entries[strings.ToLower(skill.Name)]; custom {
+			rows = append(rows, skillRow{Skill: skill})
+		}
+	}
+	return rows
+}
+
+// skillRow is a skill on the Skills tab and the ability it keys off, ""
+// for custom skills
+type skillRow struct {
+	Skill
+	Ability string
+}
+
+// skillModifier is the skill's check bonus: the ability modifier plus the
+// proficiency bonus, twice over with expertise. A manual override replaces
+// it, and custom skills only have their stored modifier
+func (m Model) skillModifier(row skillRow) int {
+	if row.Override || row.Ability == "" {
+		return row.Modifier
+	}
+	ruleset := m.ruleset()
+	modifier := ruleset.AbilityModifier(m.character.Abilities.score(row.Ability))
+	switch {
+	case row.Expertise:
+		modifier += 2 * ruleset.ProficiencyBonus(m.character.Level)
+	case row.Proficient:
+		modifier += ruleset.ProficiencyBonus(m.character.Level)
+	}
+	return modifier
+}
+
+// skillEntry returns the character's entry for a skill, adding one if the
+// skill has none yet
+func (m *Model) skillEntry(name string) *Skill {
+	for i := range m.skillList {
+		if strings.EqualFold(m.skillList[i].Name, name) {
+			return &m.skillList[i]
+		}
+	}
+	m.skillList = append(m.skillList, Skill{Name: name})
+	return &m.skillList[len(m.skillList)-1]
+}
+
+// cycleSkillProficiency steps the selected skill through not proficient,
+// proficient and expertise
+func (m *Model) cycleSkillProficiency() {
+	rows := m.skillRows()
+	if m.selectedSkill < 0 || m.selectedSkill >= len(rows) {
+		return
+	}
+	skill := m.skillEntry(rows[m.selectedSkill].Name)
+	switch {
+	case skill.Expertise:
+		skill.Proficient, skill.Expertise = false, false
+		m.message = fmt.Sprintf("%s: not proficient", skill.Name)
+	case skill.Proficient:
+		skill.Expertise = true
+		m.message = fmt.Sprintf("%s: expertise", skill.Name)
+	default:
+		skill.Proficient = true
+		m.message = fmt.Sprintf("%s: proficient", skill.Name)
+	}
+}
+
+// applySkillOverride sets the selected skill's modifier to the number typed
+// in the override input, or goes back to the computed one when it's empty.
+// It reports whether it changed anything
+func (m *Model) applySkillOverride() bool {
+	rows := m.skillRows()
+	if m.selectedSkill < 0 || m.selectedSkill >= len(rows) {
+		return false
+	}
+	value := strings.TrimSpace(m.inputs["skill_override"].Value())
+	if value == "" {
+		if !rows[m.selectedSkill].Override {
+			return false
+		}
+		skill := m.skillEntry(rows[m.selectedSkill].Name)
+		skill.Override, skill.Modifier = false, 0
+		m.message = fmt.Sprintf("%s: computed modifier", skill.Name)
+		return true
+	}
+	modifier, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+	if err != nil {
+		m.message = fmt.Sprintf("Can't read modifier %q", value)
+		return true
+	}
+	skill := m.skillEntry(rows[m.selectedSkill].Name)
+	skill.Override, skill.Modifier = true, modifier
+	m.setInput("skill_override", "")
+	m.message = fmt.Sprintf("%s: %+d (manual)", skill.Name, modifier)
+	return true
+}
+
+// moveSkillSelection moves the Skills tab cursor by step, wrapping around
+func (m *Model) moveSkillSelection(step int) {
+	if rows := len(m.skillRows()); rows > 0 {
+		m.selectedSkill = (m.selectedSkill + step + rows) % rows
+	}
+}
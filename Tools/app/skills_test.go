@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSkillModifier(t *testing.T) {
+	tests := []struct {
+		name string
+		row  skillRow
+		want int
+	}{
+		{"untrained", skillRow{Skill: Skill{Name: "Stealth"}, Ability: "Dexterity"}, 3},
+		{"proficient", skillRow{Skill: Skill{Name: "Stealth", Proficient: true}, Ability: "Dexterity"}, 6},
+		{"expertise", skillRow{Skill: Skill{Name: "Stealth", Proficient: true, Expertise: true}, Ability: "Dexterity"}, 9},
+		{"weak ability", skillRow{Skill: Skill{Name: "Arcana", Proficient: true}, Ability: "Intelligence"}, 2},
+		{"override", skillRow{Skill: Skill{Name: "Stealth", Proficient: true, Override: true, Modifier: -2}, Ability: "Dexterity"}, -2},
+		{"custom", skillRow{Skill: Skill{Name: "Cartography", Modifier: 4}}, 4},
+	}
+	m := Model{rulesets: []Ruleset{dnd5eRuleset{}}}
+	m.character.Ruleset = dnd5eRuleset{}.ID()
+	m.character.Level = 5
+	m.character.Abilities = Abilities{Dexterity: 16, Intelligence: 8}
+	for _, tt := range tests {
+		if got := m.skillModifier(tt.row); got != tt.want {
+			t.Errorf("%s: skillModifier = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSkillRows(t *testing.T) {
+	m := Model{rulesets: []Ruleset{dnd5eRuleset{}}}
+	m.character.Ruleset = dnd5eRuleset{}.ID()
+	m.skillList = []Skill{{Name: "Cartography"}, {Name: "stealth", Proficient: true}}
+
+	rows := m.skillRows()
+	if want := len(dnd5eRuleset{}.Skills()) + 1; len(rows) != want {
+		t.Fatalf("got %d rows, want %d", len(rows), want)
+	}
+	for _, row := range rows {
+		if row.Name == "stealth" && (!row.Proficient || row.Ability != "Dexterity") {
+			t.Errorf("Stealth row = %+v, want the character's proficient entry on Dexterity", row)
+		}
+	}
+	if last := rows[len(rows)-1]; last.Name != "Cartography" || last.Ability != "" {
+		t.Errorf("last row = %+v, want the custom Cartography skill", last)
+	}
+}
+
+func TestCycleSkillProficiency(t *testing.T) {
+	m := Model{rulesets: []Ruleset{dnd5eRuleset{}}}
+	m.character.Ruleset = dnd5eRuleset{}.ID()
+	want := []Skill{
+		{Proficient: true},
+		{Proficient: true, Expertise: true},
+		{},
+	}
+	for _, step := range want {
+		m.cycleSkillProficiency()
+		step.Name = m.skillRows()[0].Name
+		if !reflect.DeepEqual(m.skillList, []Skill{step}) {
+			t.Errorf("skills = %+v, want %+v", m.skillList, []Skill{step})
+		}
+	}
+}
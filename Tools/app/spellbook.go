@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpellbookEntry is a spell written in a wizard's or magic-user's book and
+// how it got there
+type SpellbookEntry struct {
+	Name    string `json:"name"`
+	Level   int    `json:"level"`
+	Learned string `json:"learned"`           // one of the learned* constants
+	CostGP  int    `json:"cost_gp,omitempty"` // spent learning it
+	Time    string `json:"time,omitempty"`    // "4 hours", "2 weeks"
+}
+
+const (
+	learnedLevelUp  = "level-up" // a wizard's six starting spells and two more a level
+	learnedScroll   = "scroll"   // copied from a scroll or another book
+	learnedStarting = "starting" // an OSE caster's beginning spells
+	learnedMentor   = "mentor"   // taught by an OSE guild or mentor
+	learnedResearch = "research" // OSE magical research
+)
+
+// learnedText describes how an entry was learned: "copied from a scroll
+// (100 gp, 4 hours)"
+func (e SpellbookEntry) learnedText() string {
+	text := map[string]string{
+		learnedLevelUp:  "gained with a level",
+		learnedScroll:   "copied from a scroll",
+		learnedStarting: "beginning spell",
+		learnedMentor:   "taught by a mentor",
+		learnedResearch: "researched",
+	}[e.Learned]
+	if text == "" {
+		text = e.Learned
+	}
+	var costs []string
+	if e.CostGP > 0 {
+		costs = append(costs, fmt.Sprintf("%d gp", e.CostGP))
+	}
+	if e.Time != "" {
+		costs = append(costs, e.Time)
+	}
+	if len(costs) > 0 {
+		text += " (" + strings.Join(costs, ", ") + ")"
+	}
+	return text
+}
+
+// hasSpellbook reports whether the character prepares spells from a book:
+// 5e wizards and OSE magic-users and elves
+func (m Model) hasSpellbook() bool {
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		class, _ := ruleset.class(m.character.Class)
+		return class.SpellBook
+	}
+	_, ok := m.bookCaster()
+	return ok
+}
+
+// bookCaster is the character's 5e class with the Spellbook feature
+func (m Model) bookCaster() (spellcaster, bool) {
+	for _, caster := range m.spellcasters() {
+		if caster.Class.Spellbook {
+			return caster, true
+		}
+	}
+	return spellcaster{}, false
+}
+
+// bookSlots are the slots of the book class alone, which limit the levels
+// of the spells it can learn and prepare
+func (m Model) bookSlots() []int {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		return m.spellSlots()
+	}
+	caster, _ := m.bookCaster()
+	return caster.Row.SpellSlots
+}
+
+// bookMaxLevel is the highest spell level the book class has slots for
+func (m Model) bookMaxLevel() int {
+	top := 0
+	for level, slots := range m.bookSlots() {
+		if slots > 0 {
+			top = level + 1
+		}
+	}
+	return top
+}
+
+// freeSpellbookSpells is how many spells a wizard adds for free: six at 1st
+// level and two more with each level after
+func (m Model) freeSpellbookSpells() int {
+	caster, ok := m.bookCaster()
+	if !ok {
+		return 0
+	}
+	return 6 + 2*(caster.Level-1)
+}
+
+// spellbookCount counts the book's spells learned a way, or of a level
+func (m Model) spellbookCount(match func(SpellbookEntry) bool) int {
+	count := 0
+	for _, entry := range m.character.Spellbook {
+		if match(entry) {
+			count++
+		}
+	}
+	return count
+}
+
+// inSpellbook reports whether a spell is written in the book
+func (m Model) inSpellbook(name string) bool {
+	return m.spellbookCount(func(e SpellbookEntry) bool { return strings.EqualFold(e.Name, name) }) > 0
+}
+
+// catalogSpell finds a spell on the character's spell list in the SRD data
+func (m Model) catalogSpell(name string) (Spell, bool) {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		list := oseSpellList(m.character.Class)
+		for _, s := range m.srdData.OSESpells {
+			if strings.EqualFold(s.Class, list) && strings.EqualFold(s.Name, name) {
+				return Spell{Name: s.Name, Description: s.Description, Level: s.Level}, true
+			}
+		}
+		return Spell{}, false
+	}
+	for _, s := range m.srdData.Spells {
+		if strings.EqualFold(s.Name, name) {
+			return spellFromSRD(s), true
+		}
+	}
+	return Spell{}, false
+}
+
+// learnableSpells lists the spells the book could take: on the class's
+// list, of a level it has slots for and not already written in it
+func (m Model) learnableSpells() []Spell {
+	top := m.bookMaxLevel()
+	var spells []Spell
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		list := oseSpellList(m.character.Class)
+		for _, s := range m.srdData.OSESpells {
+			if strings.EqualFold(s.Class, list) && s.Level <= top && !m.inSpellbook(s.Name) {
+				spells = append(spells, Spell{Name: s.Name, Description: s.Description, Level: s.Level})
+			}
+		}
+		return spells
+	}
+	caster, _ := m.bookCaster()
+	for _, s := range m.srdData.Spells {
+		if s.Level >= 1 && s.Level <= top && onSpellList(s, []string{caster.Class.Name}) && !m.inSpellbook(s.Name) {
+			spells = append(spells, spellFromSRD(s))
+		}
+	}
+	return spells
+}
+
+// learnOption is a way to write a spell into the book and what it costs
+type learnOption struct {
+	label string
+	entry SpellbookEntry
+}
+
+// learnOptions are the ways the rules give to add a spell of a level. A
+// wizard copies a scroll for 50 gp and 2 hours a spell level; an OSE
+// magic-user researches for 1,000 gp and two weeks a spell level
+func (m Model) learnOptions(spell Spell) []learnOption {
+	entry := SpellbookEntry{Name: spell.Name, Level: spell.Level}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		starting, mentor, research := entry, entry, entry
+		starting.Learned = learnedStarting
+		mentor.Learned, mentor.Time = learnedMentor, "about a week"
+		research.Learned, research.CostGP, research.Time = learnedResearch, 1000*spell.Level, fmt.Sprintf("%d weeks", 2*spell.Level)
+		return []learnOption{
+			{"Beginning spell", starting},
+			{"Taught by a guild or mentor (about a week)", mentor},
+			{fmt.Sprintf("Magical research (%d gp, %s)", research.CostGP, research.Time), research},
+		}
+	}
+	levelUp, scroll := entry, entry
+	levelUp.Learned = learnedLevelUp
+	scroll.Learned, scroll.CostGP, scroll.Time = learnedScroll, 50*spell.Level, fmt.Sprintf("%d hours", 2*spell.Level)
+	free := m.freeSpellbookSpells() - m.spellbookCount(func(e SpellbookEntry) bool { return e.Learned == learnedLevelUp })
+	return []learnOption{
+		{fmt.Sprintf("Gained with a level (free, %d left)", max(free, 0)), levelUp},
+		{fmt.Sprintf("Copied from a scroll (%d gp, %s)", scroll.CostGP, scroll.Time), scroll},
+	}
+}
+
+// openLearnSpellPicker picks a spell to write into the book, then how it
+// was learned
+func (m *Model) openLearnSpellPicker() {
+	spells := m.learnableSpells()
+	if len(spells) == 0 {
+		m.message = "No spells left to learn at this level"
+		return
+	}
+	var options []string
+	for _, spell := range spells {
+		options = append(options, fmt.Sprintf("%s (%s)", spell.Name, spellLevelText(spell.Level)))
+	}
+	m.picker = &catalogPicker{
+		title:   "Learn Spell",
+		options: options,
+		choose: func(m *Model, index int) {
+			spell := spells[index]
+			learn := m.learnOptions(spell)
+			var labels []string
+			for _, option := range learn {
+				labels = append(labels, option.label)
+			}
+			m.picker = &catalogPicker{
+				title:   "Learn " + spell.Name,
+				options: labels,
+				choose: func(m *Model, index int) {
+					m.learnSpell(learn[index].entry)
+				},
+			}
+		},
+	}
+}
+
+// learnSpell writes a spell into the book, paying its gold. It refuses
+// once the free level-up spells are used up, when the gold isn't there,
+// and for OSE books that already hold as many spells of the level as the
+// caster can memorize
+func (m *Model) learnSpell(entry SpellbookEntry) {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		slots := m.bookSlots()
+		count := m.spellbookCount(func(e SpellbookEntry) bool { return e.Level == entry.Level })
+		if entry.Level > len(slots) || count >= slots[entry.Level-1] {
+			m.message = fmt.Sprintf("The spell book already holds as many %s level spells as can be memorized", ordinal(entry.Level))
+			return
+		}
+	}
+	if entry.Learned == learnedLevelUp &&
+		m.spellbookCount(func(e SpellbookEntry) bool { return e.Learned == learnedLevelUp }) >= m.freeSpellbookSpells() {
+		m.message = "No free level-up spells left; copy it from a scroll instead"
+		return
+	}
+	if !m.pay(entry.CostGP * 100) {
+		m.message = fmt.Sprintf("Learning %s costs %d gp, the purse holds %s", entry.Name, entry.CostGP, formatCopper(m.character.Currency.value(m.ruleset().Coins())))
+		return
+	}
+
+	m.character.Spellbook = append(m.character.Spellbook, entry)
+	m.updateInputsFromCharacter()
+	m.message = fmt.Sprintf("%s written into the spellbook, %s", entry.Name, entry.learnedText())
+}
+
+// prepareFromSpellbook prepares the selected book spell for today, adding
+// it to the spell list, or unprepares it. The caster needs slots of its level
+func (m *Model) prepareFromSpellbook() {
+	book := m.character.Spellbook
+	if m.selectedSpell < 0 || m.selectedSpell >= len(book) {
+		return
+	}
+	entry := book[m.selectedSpell]
+	for i, spell := range m.spells {
+		if strings.EqualFold(spell.Name, entry.Name) {
+			m.toggleSpellPrepared(i)
+			return
+		}
+	}
+	if entry.Level > m.bookMaxLevel() {
+		m.message = fmt.Sprintf("Can't prepare %s: no %s level slots", entry.Name, ordinal(entry.Level))
+		return
+	}
+
+	spell, ok := m.catalogSpell(entry.Name)
+	if !ok {
+		spell = Spell{Name: entry.Name, Level: entry.Level}
+	}
+	m.spells = append(m.spells, spell)
+	m.toggleSpellPrepared(len(m.spells) - 1)
+	if !m.spells[len(m.spells)-1].Prepared {
+		m.spells = m.spells[:len(m.spells)-1]
+	}
+}
+
+// toggleSpellbookView switches the Spells tab between the spell list and
+// the spellbook
+func (m *Model) toggleSpellbookView() {
+	if !m.hasSpellbook() {
+		m.message = fmt.Sprintf("A %s has no spellbook", m.character.Class)
+		return
+	}
+	m.spellbookView = !m.spellbookView
+	m.selectedSpell = 0
+}
+
+// preparedToday reports whether a book spell is prepared
+func (m Model) preparedToday(name string) bool {
+	for _, spell := range m.spells {
+		if strings.EqualFold(spell.Name, name) {
+			return spell.Prepared
+		}
+	}
+	return false
+}
+
+// renderSpellbook is the Spells tab showing the book: each spell, how it
+// was learned and whether it is prepared today
+func (m Model) renderSpellbook() string {
+	view := titleStyle.Render("Spellbook") + "\n\n"
+
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		var levels []string
+		for level, slots := range m.bookSlots() {
+			if slots > 0 {
+				count := m.spellbookCount(func(e SpellbookEntry) bool { return e.Level == level+1 })
+				levels = append(levels, fmt.Sprintf("%s %d/%d", ordinal(level+1), count, slots))
+			}
+		}
+		if len(levels) > 0 {
+			view += "Spells per level: " + strings.Join(levels, ", ") + "\n"
+		}
+	} else {
+		used := m.spellbookCount(func(e SpellbookEntry) bool { return e.Learned == learnedLevelUp })
+		view += fmt.Sprintf("Free level-up spells: %d/%d\n", used, m.freeSpellbookSpells())
+	}
+	if prepared := m.preparedText(); prepared != "" {
+		view += "Prepared today: " + prepared + "\n"
+	}
+	view += "\n"
+
+	for i, entry := range m.character.Spellbook {
+		prepared := " "
+		if m.preparedToday(entry.Name) {
+			prepared = "✓"
+		}
+		line := fmt.Sprintf("[%s] %s: %s, %s", prepared, capitalize(spellLevelText(entry.Level)), entry.Name, entry.learnedText())
+		if i == m.selectedSpell {
+			line = selectedItemStyle.Render(line)
+		}
+		view += line + "\n"
+	}
+
+	view += "\n↑/↓ to select, b for the spell list"
+	if m.mode == "edit" {
+		view += ", a to learn a spell, space to prepare for today"
+	}
+	return sectionStyle.Render(view)
+}
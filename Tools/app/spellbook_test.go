@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+func TestLearnedText(t *testing.T) {
+	tests := []struct {
+		entry SpellbookEntry
+		want  string
+	}{
+		{SpellbookEntry{Learned: learnedLevelUp}, "gained with a level"},
+		{SpellbookEntry{Learned: learnedScroll, CostGP: 100, Time: "4 hours"}, "copied from a scroll (100 gp, 4 hours)"},
+		{SpellbookEntry{Learned: learnedMentor, Time: "about a week"}, "taught by a mentor (about a week)"},
+		{SpellbookEntry{Learned: "found in a tomb"}, "found in a tomb"},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.learnedText(); got != tt.want {
+			t.Errorf("learnedText(%+v) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestLearnSpell(t *testing.T) {
+	tests := []struct {
+		name    string
+		ose     bool
+		class   string
+		book    []SpellbookEntry
+		gold    int
+		entry   SpellbookEntry
+		learned bool
+		goldNow int
+	}{
+		{"free level-up spell", false, "Wizard", nil, 0,
+			SpellbookEntry{Name: "Shield", Level: 1, Learned: learnedLevelUp}, true, 0},
+		{"free spells used up", false, "Wizard", make([]SpellbookEntry, 6), 0,
+			SpellbookEntry{Name: "Shield", Level: 1, Learned: learnedLevelUp}, false, 0},
+		{"scroll paid for", false, "Wizard", nil, 60,
+			SpellbookEntry{Name: "Shield", Level: 1, Learned: learnedScroll, CostGP: 50}, true, 10},
+		{"scroll too dear", false, "Wizard", nil, 40,
+			SpellbookEntry{Name: "Shield", Level: 1, Learned: learnedScroll, CostGP: 50}, false, 40},
+		{"OSE beginning spell", true, "Magic-User", nil, 0,
+			SpellbookEntry{Name: "Sleep", Level: 1, Learned: learnedStarting}, true, 0},
+		{"OSE book full for the level", true, "Magic-User", []SpellbookEntry{{Name: "Sleep", Level: 1}}, 0,
+			SpellbookEntry{Name: "Light", Level: 1, Learned: learnedStarting}, false, 0},
+		{"OSE level without slots", true, "Magic-User", nil, 5000,
+			SpellbookEntry{Name: "Web", Level: 2, Learned: learnedResearch, CostGP: 2000}, false, 5000},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, tt.ose, tt.class, 1)
+		m.inputs = map[string]textinput.Model{}
+		for i := range tt.book {
+			tt.book[i].Learned = learnedLevelUp
+		}
+		m.character.Spellbook = tt.book
+		m.character.Currency.GP = tt.gold
+
+		m.learnSpell(tt.entry)
+		if learned := m.inSpellbook(tt.entry.Name); learned != tt.learned {
+			t.Errorf("%s: learned %v, want %v (%s)", tt.name, learned, tt.learned, m.message)
+		}
+		if m.character.Currency.GP != tt.goldNow {
+			t.Errorf("%s: %d gp left, want %d", tt.name, m.character.Currency.GP, tt.goldNow)
+		}
+	}
+}
+
+func TestLearnableSpells(t *testing.T) {
+	m := casterModel(t, false, "Wizard", 3)
+	m.character.Spellbook = []SpellbookEntry{{Name: "Magic Missile", Level: 1}}
+	spells := m.learnableSpells()
+	if len(spells) == 0 {
+		t.Fatal("no learnable spells for a 3rd level wizard")
+	}
+	for _, spell := range spells {
+		if spell.Level < 1 || spell.Level > 2 || spell.Name == "Magic Missile" || spell.Name == "Cure Wounds" {
+			t.Errorf("learnable spell %s (level %d)", spell.Name, spell.Level)
+		}
+	}
+}
+
+func TestLearnOptions(t *testing.T) {
+	m := casterModel(t, true, "Magic-User", 5)
+	options := m.learnOptions(Spell{Name: "Fly", Level: 3})
+	if len(options) != 3 {
+		t.Fatalf("got %d options, want 3", len(options))
+	}
+	if research := options[2].entry; research.CostGP != 3000 || research.Time != "6 weeks" {
+		t.Errorf("research = %+v, want 3000 gp and 6 weeks", research)
+	}
+
+	m = casterModel(t, false, "Wizard", 5)
+	options = m.learnOptions(Spell{Name: "Fly", Level: 3})
+	if scroll := options[1].entry; scroll.CostGP != 150 || scroll.Time != "6 hours" {
+		t.Errorf("scroll = %+v, want 150 gp and 6 hours", scroll)
+	}
+}
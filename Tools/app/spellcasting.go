@@ -0,0 +1,676 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SlotsUsed counts the spell slots spent since the last rest
+type SlotsUsed struct {
+	Slots []int `json:"slots,omitempty"` // per spell level
+	Pact  int   `json:"pact,omitempty"`  // warlock pact slots
+}
+
+// spellcaster is one of the character's classes with the Spellcasting or
+// Pact Magic feature
+type spellcaster struct {
+	Class ClassDefinition
+	Level int
+	Row   ClassLevel // the class table row at Level
+}
+
+// slotColumns is how many spell levels a class's table has slots for: 9
+// for full casters, 5 for the half casters, 0 for the warlock's pact magic
+func (c ClassDefinition) slotColumns() int {
+	columns := 0
+	for _, level := range c.Levels {
+		columns = max(columns, len(level.SpellSlots))
+	}
+	return columns
+}
+
+// casterLevel is what a class adds to a multiclass caster level: all of its
+// levels for full casters and half, rounded down, for paladins and rangers
+func (c ClassDefinition) casterLevel(level int) int {
+	switch columns := c.slotColumns(); {
+	case columns >= 9:
+		return level
+	case columns > 0:
+		return level / 2
+	}
+	return 0
+}
+
+// spellcasters lists the character's 5e classes that cast spells
+func (m Model) spellcasters() []spellcaster {
+	var casters []spellcaster
+	for _, cl := range m.classLevels() {
+		class, ok := m.srdData.class(cl.Name)
+		if !ok || class.SpellcastingAbility == "" {
+			continue
+		}
+		row, _ := class.level(cl.Level)
+		casters = append(casters, spellcaster{Class: class, Level: cl.Level, Row: row})
+	}
+	return casters
+}
+
+// spellSlots is the number of slots per spell level. A single caster
+// class uses its own table; several add up their caster levels on the
+// multiclass table. OSE casters follow their class's level row
+func (m Model) spellSlots() []int {
+	if ruleset, ok := m.ruleset().(oseRuleset); ok {
+		class, _ := ruleset.class(m.character.Class)
+		row, _ := class.level(max(m.character.Level, 1))
+		return row.SpellSlots
+	}
+
+	var slotted []spellcaster
+	for _, caster := range m.spellcasters() {
+		if caster.Class.slotColumns() > 0 {
+			slotted = append(slotted, caster)
+		}
+	}
+	switch len(slotted) {
+	case 0:
+		return nil
+	case 1:
+		return slotted[0].Row.SpellSlots
+	}
+	casterLevel := 0
+	for _, caster := range slotted {
+		casterLevel += caster.Class.casterLevel(caster.Level)
+	}
+	return m.srdData.multiclassSlots(casterLevel)
+}
+
+// pactSlots is the warlock's pact magic: how many slots and their level
+func (m Model) pactSlots() (int, int) {
+	for _, caster := range m.spellcasters() {
+		if caster.Row.PactSlots > 0 {
+			return caster.Row.PactSlots, caster.Row.PactSlotLevel
+		}
+	}
+	return 0, 0
+}
+
+// slotsLeft is the unspent slots of a spell level
+func (m Model) slotsLeft(level int) int {
+	slots := m.spellSlots()
+	if level < 1 || level > len(slots) {
+		return 0
+	}
+	used := m.character.SlotsUsed.Slots
+	if level <= len(used) {
+		return max(slots[level-1]-used[level-1], 0)
+	}
+	return slots[level-1]
+}
+
+// pactSlotsLeft is the unspent pact magic slots
+func (m Model) pactSlotsLeft() int {
+	slots, _ := m.pactSlots()
+	return max(slots-m.character.SlotsUsed.Pact, 0)
+}
+
+// spendSlot marks a slot of a spell level used
+func (m *Model) spendSlot(level int) {
+	used := &m.character.SlotsUsed.Slots
+	for len(*used) < level {
+		*used = append(*used, 0)
+	}
+	(*used)[level-1]++
+}
+
+// spellSaveDC is 8 + proficiency bonus + the spellcasting ability modifier
+func (m Model) spellSaveDC(caster spellcaster) int {
+	return 8 + m.spellAttackBonus(caster)
+}
+
+// spellAttackBonus is the proficiency bonus + the spellcasting ability modifier
+func (m Model) spellAttackBonus(caster spellcaster) int {
+	ruleset := m.ruleset()
+	return ruleset.ProficiencyBonus(m.character.Level) +
+		ruleset.AbilityModifier(m.character.Abilities.score(caster.Class.SpellcastingAbility))
+}
+
+// preparedLimit is how many leveled spells the character can have prepared:
+// the spellcasting ability modifier + class level (half of it for paladins),
+// at least 1, for each class that prepares spells. OSE casters memorize one
+// spell per slot. 0 means the character's classes know rather than prepare
+func (m Model) preparedLimit() int {
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		total := 0
+		for _, slots := range m.spellSlots() {
+			total += slots
+		}
+		return total
+	}
+
+	limit := 0
+	for _, caster := range m.spellcasters() {
+		limit += m.classPreparedLimit(caster.Class.Name)
+	}
+	return limit
+}
+
+// classPreparedLimit is preparedLimit for one of the character's classes,
+// 0 if it doesn't prepare spells
+func (m Model) classPreparedLimit(class string) int {
+	for _, caster := range m.spellcasters() {
+		if !strings.EqualFold(caster.Class.Name, class) || !caster.Class.PreparesSpells {
+			continue
+		}
+		level := caster.Level
+		if caster.Class.PrepareHalfLevel {
+			level /= 2
+		}
+		modifier := m.ruleset().AbilityModifier(m.character.Abilities.score(caster.Class.SpellcastingAbility))
+		return max(modifier+level, 1)
+	}
+	return 0
+}
+
+// preparedCount is the number of leveled spells marked prepared
+func (m Model) preparedCount() int {
+	count := 0
+	for _, spell := range m.spells {
+		if spell.Prepared && spell.Level > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// classPreparedCount is the number of leveled spells prepared for a class
+func (m Model) classPreparedCount(class string) int {
+	count := 0
+	for _, spell := range m.spells {
+		if spell.Prepared && spell.Level > 0 && strings.EqualFold(m.spellClass(spell), class) {
+			count++
+		}
+	}
+	return count
+}
+
+// memorizedCount is the number of spells of a level an OSE caster has
+// memorized
+func (m Model) memorizedCount(level int) int {
+	count := 0
+	for _, spell := range m.spells {
+		if spell.Prepared && spell.Level == level {
+			count++
+		}
+	}
+	return count
+}
+
+// preparingClasses are the character's classes that prepare spells and
+// have the spell on their list. Spells missing from the catalog could be
+// on any of them
+func (m Model) preparingClasses(spell Spell) []string {
+	var srd *SRDSpell
+	for i := range m.srdData.Spells {
+		if strings.EqualFold(m.srdData.Spells[i].Name, spell.Name) {
+			srd = &m.srdData.Spells[i]
+			break
+		}
+	}
+	var classes []string
+	for _, caster := range m.spellcasters() {
+		if caster.Class.PreparesSpells && (srd == nil || onSpellList(*srd, []string{caster.Class.Name})) {
+			classes = append(classes, caster.Class.Name)
+		}
+	}
+	return classes
+}
+
+// spellClass is the class a spell is prepared for: the one recorded on it,
+// or the first class that prepares it. "" when none of the character's
+// classes prepare it
+func (m Model) spellClass(spell Spell) string {
+	if spell.Class != "" {
+		return spell.Class
+	}
+	if classes := m.preparingClasses(spell); len(classes) > 0 {
+		return classes[0]
+	}
+	return ""
+}
+
+// preparedText sums up the prepared spells against the limits: "2/4", by
+// class for several preparing classes ("Cleric 2/4, Wizard 1/5") and by
+// spell level for OSE ("1st 1/2, 2nd 0/1"). "" when nothing is limited
+func (m Model) preparedText() string {
+	var parts []string
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		for level, slots := range m.spellSlots() {
+			if slots > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d/%d", ordinal(level+1), m.memorizedCount(level+1), slots))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	for _, caster := range m.spellcasters() {
+		if limit := m.classPreparedLimit(caster.Class.Name); limit > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d/%d", caster.Class.Name, m.classPreparedCount(caster.Class.Name), limit))
+		}
+	}
+	if len(parts) == 1 {
+		return fmt.Sprintf("%d/%d", m.preparedCount(), m.preparedLimit())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ritualCaster reports whether one of the character's classes can cast
+// ritual spells without a slot
+func (m Model) ritualCaster() bool {
+	for _, caster := range m.spellcasters() {
+		if caster.Class.RitualCasting {
+			return true
+		}
+	}
+	return false
+}
+
+// oseSpellList is the spell list an OSE class casts from: elves learn
+// magic-user spells
+func oseSpellList(class string) string {
+	if strings.EqualFold(class, "Elf") {
+		return "Magic-User"
+	}
+	return class
+}
+
+// spellFromSRD turns an SRD spell into a sheet entry
+func spellFromSRD(s SRDSpell) Spell {
+	return Spell{Name: s.Name, Description: s.Description, Level: s.Level, School: s.School, Ritual: s.Ritual}
+}
+
+// openSpellPicker offers the spells on the lists of the character's
+// classes, or every spell when none of them has a list
+func (m *Model) openSpellPicker() {
+	var options []string
+	var spells []Spell
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		list := oseSpellList(m.character.Class)
+		for _, s := range m.srdData.OSESpells {
+			if strings.EqualFold(s.Class, list) {
+				options = append(options, fmt.Sprintf("%s (%s %d)", s.Name, s.Class, s.Level))
+				spells = append(spells, Spell{Name: s.Name, Description: s.Description, Level: s.Level})
+			}
+		}
+	} else {
+		var classes []string
+		for _, cl := range m.classLevels() {
+			classes = append(classes, cl.Name)
+		}
+		for _, all := range []bool{false, true} {
+			for _, s := range m.srdData.Spells {
+				if all || onSpellList(s, classes) {
+					options = append(options, fmt.Sprintf("%s (%s, %s)%s", s.Name, spellLevelText(s.Level), s.School, map[bool]string{true: " ritual"}[s.Ritual]))
+					spells = append(spells, spellFromSRD(s))
+				}
+			}
+			if len(spells) > 0 {
+				break
+			}
+		}
+	}
+	if len(spells) == 0 {
+		m.message = "No SRD spells loaded"
+		return
+	}
+
+	m.picker = &catalogPicker{
+		title:   "Add Spell",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.spells = append(m.spells, spells[index])
+			m.message = fmt.Sprintf("Added %s to spells", spells[index].Name)
+		},
+	}
+}
+
+// onSpellList reports whether a spell is on one of the classes' lists
+func onSpellList(s SRDSpell, classes []string) bool {
+	for _, class := range strings.Split(s.Classes, ",") {
+		if containsFold(classes, strings.TrimSpace(class)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSpell reports whether the spell list holds a spell
+func (m Model) hasSpell(name string) bool {
+	for _, spell := range m.spells {
+		if strings.EqualFold(spell.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// classSpellChoices are the class's spells between two levels that aren't
+// on the spell list yet
+func (m Model) classSpellChoices(class string, lowest, highest int) []Spell {
+	var spells []Spell
+	for _, s := range m.srdData.Spells {
+		if s.Level >= lowest && s.Level <= highest && onSpellList(s, []string{class}) && !m.hasSpell(s.Name) {
+			spells = append(spells, spellFromSRD(s))
+		}
+	}
+	return spells
+}
+
+// pickSpells asks for n spells one picker at a time, the candidates worked
+// out again after each pick. Optional picks can stop early with "Done"
+func (m *Model) pickSpells(title string, n int, optional bool, candidates func(m *Model) []Spell, add func(m *Model, s Spell), then func(m *Model)) {
+	spells := candidates(m)
+	if n <= 0 || len(spells) == 0 {
+		then(m)
+		return
+	}
+	var options []string
+	if optional {
+		options = append(options, "Done")
+	}
+	for _, spell := range spells {
+		option := fmt.Sprintf("%s (%s", spell.Name, spellLevelText(spell.Level))
+		if spell.School != "" {
+			option += ", " + spell.School
+		}
+		options = append(options, option+")")
+	}
+	m.picker = &catalogPicker{
+		title:   fmt.Sprintf("%s (%d left)", title, n),
+		options: options,
+		choose: func(m *Model, index int) {
+			if optional {
+				if index == 0 {
+					then(m)
+					return
+				}
+				index--
+			}
+			add(m, spells[index])
+			m.pickSpells(title, n-1, optional, candidates, add, then)
+		},
+	}
+}
+
+// spellLevelText renders "cantrip" or "3rd level"
+func spellLevelText(level int) string {
+	if level == 0 {
+		return "cantrip"
+	}
+	return ordinal(level) + " level"
+}
+
+// moveSpellSelection moves the Spells tab cursor by step, wrapping around
+// the spells or, when it is showing, the spellbook
+func (m *Model) moveSpellSelection(step int) {
+	rows := len(m.spells)
+	if m.spellbookView {
+		rows = len(m.character.Spellbook)
+	}
+	if rows > 0 {
+		m.selectedSpell = (m.selectedSpell + step + rows) % rows
+	}
+}
+
+// togglePrepared prepares or unprepares the selected spell
+func (m *Model) togglePrepared() {
+	if m.selectedSpell >= 0 && m.selectedSpell < len(m.spells) {
+		m.toggleSpellPrepared(m.selectedSpell)
+	}
+}
+
+// toggleSpellPrepared prepares or unprepares a spell, refusing to go over
+// the prepared limit of the class it is prepared for, or, for OSE, the
+// slots of its level. Cantrips are always ready
+func (m *Model) toggleSpellPrepared(index int) {
+	spell := &m.spells[index]
+	if spell.Level == 0 {
+		m.message = fmt.Sprintf("%s is a cantrip and always prepared", spell.Name)
+		return
+	}
+	if !spell.Prepared {
+		if _, ose := m.ruleset().(oseRuleset); ose {
+			slots := 0
+			if levels := m.spellSlots(); spell.Level <= len(levels) {
+				slots = levels[spell.Level-1]
+			}
+			if slots == 0 {
+				m.message = fmt.Sprintf("Can't memorize %s: no %s level slots", spell.Name, ordinal(spell.Level))
+				return
+			}
+			if m.memorizedCount(spell.Level) >= slots {
+				m.message = fmt.Sprintf("Can't memorize %s: already %d of %d %s level spells memorized", spell.Name, slots, slots, ordinal(spell.Level))
+				return
+			}
+		} else {
+			class := spell.Class
+			if class == "" {
+				// The first class with room to prepare it
+				for _, candidate := range m.preparingClasses(*spell) {
+					if class == "" || m.classPreparedCount(class) >= m.classPreparedLimit(class) {
+						class = candidate
+					}
+				}
+			}
+			if limit := m.classPreparedLimit(class); limit > 0 && m.classPreparedCount(class) >= limit {
+				m.message = fmt.Sprintf("Can't prepare %s: already %d of %d %s spells prepared", spell.Name, limit, limit, class)
+				return
+			}
+			spell.Class = class
+		}
+	}
+	spell.Prepared = !spell.Prepared
+	m.message = fmt.Sprintf("%s %s", spell.Name, map[bool]string{true: "prepared", false: "unprepared"}[spell.Prepared])
+}
+
+// castOption is a way to cast the selected spell
+type castOption struct {
+	label string
+	cast  func(m *Model) string // spends what the option costs, returns the message
+}
+
+// openCastPicker offers the ways to cast the selected spell: a slot of its
+// level or higher (upcasting), a pact slot, or as a ritual. Cantrips cast
+// straight away. OSE casters spend a slot of the spell's own level
+func (m *Model) openCastPicker() {
+	if m.selectedSpell < 0 || m.selectedSpell >= len(m.spells) {
+		m.message = "Select a spell to cast"
+		return
+	}
+	spell := m.spells[m.selectedSpell]
+	if spell.Level == 0 {
+		m.message = fmt.Sprintf("Cast %s", spell.Name)
+		return
+	}
+
+	_, ose := m.ruleset().(oseRuleset)
+	prepared := spell.Prepared || (!ose && m.classPreparedLimit(m.spellClass(spell)) == 0)
+	var options []castOption
+	if prepared {
+		top := len(m.spellSlots())
+		if ose {
+			top = spell.Level
+		}
+		for level := spell.Level; level <= top; level++ {
+			left := m.slotsLeft(level)
+			if left == 0 {
+				continue
+			}
+			level := level
+			label := fmt.Sprintf("%s slot (%d left)", ordinal(level), left)
+			if level > spell.Level {
+				label += ", upcast"
+			}
+			options = append(options, castOption{label, func(m *Model) string {
+				m.spendSlot(level)
+				return fmt.Sprintf("Cast %s with a %s level slot", spell.Name, ordinal(level))
+			}})
+		}
+		if _, pactLevel := m.pactSlots(); pactLevel >= spell.Level && m.pactSlotsLeft() > 0 {
+			options = append(options, castOption{fmt.Sprintf("Pact slot, %s level (%d left)", ordinal(pactLevel), m.pactSlotsLeft()), func(m *Model) string {
+				m.character.SlotsUsed.Pact++
+				return fmt.Sprintf("Cast %s with a %s level pact slot", spell.Name, ordinal(pactLevel))
+			}})
+		}
+	}
+	if spell.Ritual && m.ritualCaster() {
+		options = append(options, castOption{"As a ritual (10 minutes longer, no slot)", func(m *Model) string {
+			return fmt.Sprintf("Cast %s as a ritual", spell.Name)
+		}})
+	}
+	if len(options) == 0 {
+		if !prepared {
+			m.message = fmt.Sprintf("%s isn't prepared", spell.Name)
+		} else {
+			m.message = fmt.Sprintf("No slots left to cast %s", spell.Name)
+		}
+		return
+	}
+
+	var labels []string
+	for _, option := range options {
+		labels = append(labels, option.label)
+	}
+	m.picker = &catalogPicker{
+		title:   "Cast " + spell.Name,
+		options: labels,
+		choose: func(m *Model, index int) {
+			m.message = options[index].cast(m)
+		},
+	}
+}
+
+// openRestPicker offers a short or long rest. A short rest brings back
+// pact slots; a long rest brings back every slot, all hit points and half
+// the spent hit dice. OSE characters take a full day of rest. The dead
+// don't rest
+func (m *Model) openRestPicker() {
+	if m.character.Combat.Dead {
+		m.message = "The dead can't rest; ctrl+v on the Combat tab to revive"
+		return
+	}
+	options := []string{"Short rest", "Long rest"}
+	if _, ok := m.ruleset().(oseRuleset); ok {
+		options = []string{"Full day of rest"}
+	}
+	m.picker = &catalogPicker{
+		title:   "Rest",
+		options: options,
+		choose: func(m *Model, index int) {
+			switch options[index] {
+			case "Short rest":
+				m.character.SlotsUsed.Pact = 0
+				m.message = "Short rest taken"
+			case "Long rest":
+				m.longRest()
+				m.message = "Long rest taken"
+			default:
+				healed := m.oseRest()
+				m.message = fmt.Sprintf("%s taken, %d HP recovered", options[index], healed)
+			}
+		},
+	}
+}
+
+// oseRest is a full day of complete rest under OSE, from 5. Adventures/3b.
+// Damage, Healing and Death.md: spells can be memorized again and the
+// character recovers 1d3 hit points, unless it is already dead at 0. It
+// returns the hit points recovered
+func (m *Model) oseRest() int {
+	if m.character.Combat.Dead {
+		return 0
+	}
+	m.character.SlotsUsed = SlotsUsed{}
+	before := m.currentHP()
+	if before > 0 {
+		m.heal(rollDie(3))
+	}
+	return m.currentHP() - before
+}
+
+// longRest is a 5e long rest: slots, hit points and half the character's
+// hit dice come back. A dead character gets nothing back
+func (m *Model) longRest() {
+	if m.character.Combat.Dead {
+		return
+	}
+	m.character.SlotsUsed = SlotsUsed{}
+	combat := &m.character.Combat
+	combat.Damage, combat.TempHP = 0, 0
+	combat.DeathSaves = DeathSaves{}
+	m.regainHitDice(max(m.character.Level/2, 1))
+}
+
+// renderSpells is the Spells tab: spellcasting numbers, slots and the
+// character's spells with the selected one highlighted
+func (m Model) renderSpells() string {
+	if m.spellbookView {
+		return m.renderSpellbook()
+	}
+	view := titleStyle.Render("Spells") + "\n\n"
+
+	for _, caster := range m.spellcasters() {
+		view += fmt.Sprintf("%s %d: %s, spell save DC %d, spell attack %+d", caster.Class.Name, caster.Level,
+			caster.Class.SpellcastingAbility, m.spellSaveDC(caster), m.spellAttackBonus(caster))
+		if caster.Row.CantripsKnown > 0 {
+			view += fmt.Sprintf(", %d cantrips known", caster.Row.CantripsKnown)
+		}
+		if caster.Row.SpellsKnown > 0 {
+			view += fmt.Sprintf(", %d spells known", caster.Row.SpellsKnown)
+		}
+		view += "\n"
+	}
+
+	var slots []string
+	for level, total := range m.spellSlots() {
+		if total > 0 {
+			slots = append(slots, fmt.Sprintf("%s %d/%d", ordinal(level+1), m.slotsLeft(level+1), total))
+		}
+	}
+	if pact, pactLevel := m.pactSlots(); pact > 0 {
+		slots = append(slots, fmt.Sprintf("pact (%s) %d/%d", ordinal(pactLevel), m.pactSlotsLeft(), pact))
+	}
+	if len(slots) > 0 {
+		view += "Slots: " + strings.Join(slots, ", ") + "\n"
+	}
+	if prepared := m.preparedText(); prepared != "" {
+		view += "Prepared: " + prepared + "\n"
+	}
+	view += "\n"
+
+	for i, spell := range m.spells {
+		prepared := " "
+		if spell.Prepared || spell.Level == 0 {
+			prepared = "✓"
+		}
+		line := fmt.Sprintf("[%s] %s: %s", prepared, capitalize(spellLevelText(spell.Level)), spell.Name)
+		if spell.School != "" {
+			line += fmt.Sprintf(" (%s)", spell.School)
+		}
+		if spell.Ritual {
+			line += " [ritual]"
+		}
+		if i == m.selectedSpell {
+			line = selectedItemStyle.Render(line)
+		}
+		view += line + "\n"
+	}
+
+	view += "\n↑/↓ to select, c to cast, ctrl+l to rest"
+	if m.hasSpellbook() {
+		view += ", b for the spellbook"
+	}
+	if m.mode == "edit" {
+		view += ", a to add a spell from SRD, space to prepare"
+	}
+	return sectionStyle.Render(view)
+}
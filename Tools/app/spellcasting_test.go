@@ -0,0 +1,250 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// casterModel is a character of the given ruleset, class and level with
+// the embedded SRD data
+func casterModel(t *testing.T, ose bool, class string, level int) Model {
+	t.Helper()
+	data, err := loadSRDData(nil)
+	if err != nil {
+		t.Fatalf("loadSRDData: %v", err)
+	}
+	var ruleset Ruleset = dnd5eRuleset{classes: data.Classes}
+	if ose {
+		ruleset = oseRuleset{classes: data.OSEClasses}
+	}
+	m := Model{rulesets: []Ruleset{ruleset}, srdData: data}
+	m.character.Ruleset = ruleset.ID()
+	m.character.Class = class
+	m.character.Level = level
+	m.character.Abilities = Abilities{Strength: 10, Dexterity: 10, Constitution: 10, Intelligence: 10, Wisdom: 10, Charisma: 10}
+	return m
+}
+
+func TestSpellSlots(t *testing.T) {
+	tests := []struct {
+		ose   bool
+		class string
+		level int
+		want  []int
+	}{
+		{false, "Wizard", 1, []int{2, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{false, "Wizard", 5, []int{4, 3, 2, 0, 0, 0, 0, 0, 0}},
+		{false, "Paladin", 5, []int{4, 2, 0, 0, 0}},
+		{false, "Fighter", 5, nil},
+		{false, "Warlock", 5, nil},
+		{true, "Magic-User", 3, []int{2, 1, 0, 0, 0, 0}},
+		{true, "Fighter", 3, nil},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, tt.ose, tt.class, tt.level)
+		if got := m.spellSlots(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %d: spellSlots = %v, want %v", tt.class, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestPactSlots(t *testing.T) {
+	tests := []struct {
+		class        string
+		level        int
+		slots, slotL int
+	}{
+		{"Warlock", 1, 1, 1},
+		{"Warlock", 5, 2, 3},
+		{"Wizard", 5, 0, 0},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, false, tt.class, tt.level)
+		if slots, level := m.pactSlots(); slots != tt.slots || level != tt.slotL {
+			t.Errorf("%s %d: pactSlots = %d of level %d, want %d of level %d", tt.class, tt.level, slots, level, tt.slots, tt.slotL)
+		}
+	}
+}
+
+func TestCasterLevel(t *testing.T) {
+	tests := []struct {
+		class   string
+		level   int
+		columns int
+		want    int
+	}{
+		{"Wizard", 7, 9, 7},
+		{"Paladin", 7, 5, 3},
+		{"Warlock", 7, 0, 0},
+		{"Fighter", 7, 0, 0},
+	}
+	m := casterModel(t, false, "Wizard", 1)
+	for _, tt := range tests {
+		class, ok := m.srdData.class(tt.class)
+		if !ok {
+			t.Fatalf("no %s in the SRD data", tt.class)
+		}
+		if got := class.slotColumns(); got != tt.columns {
+			t.Errorf("%s: slotColumns = %d, want %d", tt.class, got, tt.columns)
+		}
+		if got := class.casterLevel(tt.level); got != tt.want {
+			t.Errorf("%s %d: casterLevel = %d, want %d", tt.class, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestMulticlassSlots(t *testing.T) {
+	m := casterModel(t, false, "Wizard", 1)
+	tests := []struct {
+		casterLevel int
+		want        []int
+	}{
+		{1, []int{2, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{5, []int{4, 3, 2, 0, 0, 0, 0, 0, 0}},
+		{20, []int{4, 3, 3, 3, 3, 2, 2, 1, 1}},
+	}
+	for _, tt := range tests {
+		if got := m.srdData.multiclassSlots(tt.casterLevel); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("multiclassSlots(%d) = %v, want %v", tt.casterLevel, got, tt.want)
+		}
+	}
+}
+
+func TestSlotsLeft(t *testing.T) {
+	m := casterModel(t, false, "Wizard", 3)
+	m.spendSlot(2)
+	m.spendSlot(1)
+	m.spendSlot(1)
+	m.spendSlot(1)
+	for level, want := range map[int]int{0: 0, 1: 1, 2: 1, 3: 0} {
+		if got := m.slotsLeft(level); got != want {
+			t.Errorf("slotsLeft(%d) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestPreparedLimit(t *testing.T) {
+	tests := []struct {
+		class   string
+		level   int
+		ability int
+		want    int
+	}{
+		{"Cleric", 3, 16, 6},
+		{"Wizard", 1, 8, 1},
+		{"Paladin", 5, 14, 4},
+		{"Sorcerer", 5, 18, 0},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, false, tt.class, tt.level)
+		m.character.Abilities = Abilities{Intelligence: tt.ability, Wisdom: tt.ability, Charisma: tt.ability}
+		if got := m.preparedLimit(); got != tt.want {
+			t.Errorf("%s %d: preparedLimit = %d, want %d", tt.class, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestOSERest(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage int
+		min    int
+		max    int
+	}{
+		{"wounded", 3, 1, 3},
+		{"barely hurt", 1, 1, 1},
+		{"at full HP", 0, 0, 0},
+		{"dead at 0", 100, 0, 0},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, true, "Magic-User", 3)
+		m.character.Combat.Damage = min(tt.damage, m.maxHP())
+		m.spendSlot(1)
+		healed := m.oseRest()
+		if healed < tt.min || healed > tt.max {
+			t.Errorf("%s: oseRest healed %d, want %d to %d", tt.name, healed, tt.min, tt.max)
+		}
+		if m.slotsLeft(1) != 2 {
+			t.Errorf("%s: %d 1st level slots left after resting, want 2", tt.name, m.slotsLeft(1))
+		}
+	}
+}
+
+func TestRestWhenDead(t *testing.T) {
+	tests := []struct {
+		name string
+		ose  bool
+		rest func(m *Model)
+	}{
+		{"long rest", false, (*Model).longRest},
+		{"full day of rest", true, func(m *Model) { m.oseRest() }},
+	}
+	for _, tt := range tests {
+		m := casterModel(t, tt.ose, "Wizard", 3)
+		if tt.ose {
+			m.character.Class = "Magic-User"
+		}
+		m.character.Combat.Damage = m.maxHP()
+		m.character.Combat.Dead = true
+		m.spendSlot(1)
+		left := m.slotsLeft(1)
+		tt.rest(&m)
+		if m.currentHP() != 0 || !m.character.Combat.Dead || m.slotsLeft(1) != left {
+			t.Errorf("%s when dead: HP %d, dead %v, %d slots left, want nothing back", tt.name, m.currentHP(), m.character.Combat.Dead, m.slotsLeft(1))
+		}
+
+		m.openRestPicker()
+		if m.picker != nil {
+			t.Errorf("%s: rest picker opened for the dead", tt.name)
+		}
+	}
+}
+
+func TestToggleSpellPreparedMulticlass(t *testing.T) {
+	m := casterModel(t, false, "Cleric", 5)
+	m.character.Classes = []CharacterClass{{Name: "Wizard", Level: 2}}
+	// Cleric 3 prepares 3, Wizard 2 prepares 2
+	for _, name := range []string{"Bless", "Cure Wounds", "Healing Word", "Shield of Faith", "Magic Missile", "Detect Magic"} {
+		spell, ok := m.catalogSpell(name)
+		if !ok {
+			t.Fatalf("no SRD spell %q", name)
+		}
+		m.spells = append(m.spells, spell)
+	}
+	tests := []struct {
+		name     string
+		prepared bool
+		class    string
+	}{
+		{"Bless", true, "Cleric"},
+		{"Cure Wounds", true, "Cleric"},
+		{"Healing Word", true, "Cleric"},
+		{"Shield of Faith", false, ""}, // the cleric is full
+		{"Magic Missile", true, "Wizard"},
+		{"Detect Magic", true, "Wizard"}, // on both lists, the cleric is full
+	}
+	for i, tt := range tests {
+		m.toggleSpellPrepared(i)
+		if spell := m.spells[i]; spell.Prepared != tt.prepared || spell.Class != tt.class {
+			t.Errorf("%s: prepared %v for %q, want %v for %q (%s)", tt.name, spell.Prepared, spell.Class, tt.prepared, tt.class, m.message)
+		}
+	}
+	if got, want := m.preparedText(), "Cleric 3/3, Wizard 2/2"; got != want {
+		t.Errorf("preparedText = %q, want %q", got, want)
+	}
+}
+
+func TestToggleSpellMemorizedOSE(t *testing.T) {
+	m := casterModel(t, true, "Magic-User", 3) // two 1st level slots, one 2nd
+	m.spells = []Spell{{Name: "Sleep", Level: 1}, {Name: "Light", Level: 1}, {Name: "Shield", Level: 1}, {Name: "Web", Level: 2}, {Name: "Fly", Level: 3}}
+	want := []bool{true, true, false, true, false}
+	for i := range m.spells {
+		m.toggleSpellPrepared(i)
+		if m.spells[i].Prepared != want[i] {
+			t.Errorf("%s: memorized %v, want %v (%s)", m.spells[i].Name, m.spells[i].Prepared, want[i], m.message)
+		}
+	}
+	if got, want := m.preparedText(), "1st 2/2, 2nd 1/1"; got != want {
+		t.Errorf("preparedText = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Experience levels from Rules/DND.SRD.Wiki/Characterizations/Beyond 1st Level.md
+
+// AdvancementLevel is a row of the character advancement table: the XP a
+// character needs to reach a level, whatever their class
+type AdvancementLevel struct {
+	Level            int `json:"level"`
+	XP               int `json:"xp"`
+	ProficiencyBonus int `json:"proficiency_bonus"`
+}
+
+// importAdvancement parses the character advancement table
+func importAdvancement(file string) ([]AdvancementLevel, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading advancement: %v", err)
+	}
+	rows, err := wikiTable(string(data), "Character Advancement")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading advancement: %v", err)
+	}
+
+	var levels []AdvancementLevel
+	var issues []ImportIssue
+	for _, row := range rows {
+		if len(row) < 3 {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("short advancement row %q", strings.Join(row, " | "))})
+			continue
+		}
+		xp, err := strconv.Atoi(strings.ReplaceAll(row[0], ",", ""))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("bad XP %q", row[0])})
+			continue
+		}
+		level, err := strconv.Atoi(row[1])
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("bad level %q", row[1])})
+			continue
+		}
+		bonus, _ := strconv.Atoi(strings.TrimPrefix(row[2], "+"))
+		levels = append(levels, AdvancementLevel{Level: level, XP: xp, ProficiencyBonus: bonus})
+	}
+	return levels, issues, nil
+}
+
+// advancement returns the advancement table's row for a level
+func (d SRDData) advancement(level int) (AdvancementLevel, bool) {
+	for _, row := range d.Advancement {
+		if row.Level == level {
+			return row, true
+		}
+	}
+	return AdvancementLevel{}, false
+}
+
+func advancementFile(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Characterizations", "Beyond 1st Level.md")
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestImportAdvancement(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "Beyond 1st Level.md")
+	text := "**Table- Character Advancement**\n\n" +
+		"| Experience Points Required | Level | Prof. Bonus |\n" +
+		"| :--- | --- | --- |\n" +
+		"| 0 | 1 | +2 |\n" +
+		"| 6,500 | 5 | +3 |\n" +
+		"| lots | 6 | +3 |\n"
+	if err := os.WriteFile(file, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	levels, issues, err := importAdvancement(file)
+	if err != nil {
+		t.Fatalf("importAdvancement: %v", err)
+	}
+	want := []AdvancementLevel{{Level: 1, XP: 0, ProficiencyBonus: 2}, {Level: 5, XP: 6500, ProficiencyBonus: 3}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %+v, want %+v", levels, want)
+	}
+	if len(issues) != 1 {
+		t.Errorf("issues = %+v, want the bad XP", issues)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 5e armor from the tables in Rules/DND.SRD.Wiki/Equipment/Armor.md
+
+type SRDArmor struct {
+	Name                string `json:"name"`
+	Category            string `json:"category"` // light, medium, heavy or shield
+	Cost                string `json:"cost"`
+	BaseAC              int    `json:"base_ac"` // the bonus for shields
+	AddDex              bool   `json:"add_dex"`
+	MaxDex              int    `json:"max_dex"` // 0 means uncapped when AddDex is set
+	StrengthRequirement int    `json:"strength_requirement"`
+	StealthDisadvantage bool   `json:"stealth_disadvantage"`
+	Weight              string `json:"weight"`
+	DonTime             string `json:"don_time"`
+	DoffTime            string `json:"doff_time"`
+	Description         string `json:"description"`
+}
+
+var (
+	armorACPattern  = regexp.MustCompile(`^\+?(\d+)( \+ Dex modifier)?(?: \(max (\d+)\))?$`)
+	armorStrPattern = regexp.MustCompile(`^Str (\d+)$`)
+	// "Chain mail, +1", the way the SRD names magic armor
+	armorBonusPattern = regexp.MustCompile(`^(.+), \+(\d)$`)
+)
+
+// importArmor reads the Armor table and the Donning and Doffing Armor table
+func importArmor(file string) ([]SRDArmor, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading armor: %v", err)
+	}
+	text := string(data)
+
+	rows, err := wikiTable(text, "Armor")
+	if err != nil {
+		return nil, nil, err
+	}
+	timeRows, err := wikiTable(text, "Donning and Doffing Armor")
+	if err != nil {
+		return nil, nil, err
+	}
+	features := wikiFeatures(text)
+
+	// "Light Armor" -> {"1 minute", "1 minute"}
+	times := make(map[string][2]string)
+	for _, row := range timeRows {
+		if len(row) >= 3 {
+			times[strings.ToLower(row[0])] = [2]string{row[1], row[2]}
+		}
+	}
+
+	var armor []SRDArmor
+	var issues []ImportIssue
+	category := ""
+	for _, row := range rows {
+		if len(row) < 6 {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("short row %q", strings.Join(row, " | "))})
+			continue
+		}
+
+		// Group rows: "**Light Armor**", and a bare "Shield" row with no cost
+		if strings.HasPrefix(row[0], "**") || row[1] == "" {
+			category = strings.TrimSuffix(strings.ToLower(strings.Trim(row[0], "*")), " armor")
+			continue
+		}
+
+		match := armorACPattern.FindStringSubmatch(row[2])
+		if match == nil {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("%s: unrecognised armor class %q", row[0], row[2])})
+			continue
+		}
+
+		piece := SRDArmor{
+			Name:                row[0],
+			Category:            category,
+			Cost:                row[1],
+			AddDex:              match[2] != "",
+			StealthDisadvantage: row[4] == "Disadvantage",
+			Weight:              dashToEmpty(row[5]),
+		}
+		piece.BaseAC, _ = strconv.Atoi(match[1])
+		if match[3] != "" {
+			piece.MaxDex, _ = strconv.Atoi(match[3])
+		}
+		if str := armorStrPattern.FindStringSubmatch(row[3]); str != nil {
+			piece.StrengthRequirement, _ = strconv.Atoi(str[1])
+		}
+
+		timeKey := category + " armor"
+		if category == "shield" {
+			timeKey = "shield"
+		}
+		piece.DonTime, piece.DoffTime = times[timeKey][0], times[timeKey][1]
+
+		// The descriptions are "***Studded Leather***." and "***Shields***."
+		name := strings.ToLower(piece.Name)
+		piece.Description = features[name]
+		if piece.Description == "" {
+			piece.Description = features[name+"s"]
+		}
+
+		armor = append(armor, piece)
+	}
+	return armor, issues, nil
+}
+
+// armor finds a catalog armor by name. Inventory names like "Chain mail,
+// +1" or "Leather armor" match too, and the ", +N" is returned as the bonus
+func (d SRDData) armor(name string) (SRDArmor, int, bool) {
+	return findArmor(d.Armor, name)
+}
+
+// findArmor is SRDData.armor over a list of armor
+func findArmor(armors []SRDArmor, name string) (SRDArmor, int, bool) {
+	bonus := 0
+	if match := armorBonusPattern.FindStringSubmatch(name); match != nil {
+		name = match[1]
+		bonus, _ = strconv.Atoi(match[2])
+	}
+	for _, candidate := range []string{name, strings.TrimSuffix(strings.ToLower(name), " armor")} {
+		for _, armor := range armors {
+			if strings.EqualFold(armor.Name, strings.TrimSpace(candidate)) {
+				return armor, bonus, true
+			}
+		}
+	}
+	return SRDArmor{}, 0, false
+}
+
+// itemFromArmor copies a catalog armor into the inventory. Shields go in
+// the shield slot, everything else on the body
+func itemFromArmor(a SRDArmor) Item {
+	slot := "body"
+	if a.Category == "shield" {
+		slot = "shield"
+	}
+	return Item{
+		Name:        a.Name,
+		Description: a.Description,
+		Quantity:    1,
+		Weight:      a.Weight,
+		Cost:        a.Cost,
+		Slot:        slot,
+	}
+}
+
+// openArmorPicker lets the user add armor from the SRD catalog
+func (m *Model) openArmorPicker() {
+	if len(m.srdData.Armor) == 0 {
+		m.message = "No SRD armor loaded"
+		return
+	}
+
+	var options []string
+	for _, a := range m.srdData.Armor {
+		options = append(options, fmt.Sprintf("%s (%s, AC %s, %s)", a.Name, a.Category, a.acText(), a.Cost))
+	}
+	m.picker = &catalogPicker{
+		title:   "Add Armor",
+		options: options,
+		choose: func(m *Model, index int) {
+			item := itemFromArmor(m.srdData.Armor[index])
+			m.equipment = append(m.equipment, item)
+			m.message = fmt.Sprintf("Added %s to equipment", item.Name)
+		},
+	}
+}
+
+// acText renders the armor's AC the way the table does: "12 + Dex (max 2)"
+func (a SRDArmor) acText() string {
+	switch {
+	case a.Category == "shield":
+		return fmt.Sprintf("+%d", a.BaseAC)
+	case a.AddDex && a.MaxDex > 0:
+		return fmt.Sprintf("%d + Dex (max %d)", a.BaseAC, a.MaxDex)
+	case a.AddDex:
+		return fmt.Sprintf("%d + Dex", a.BaseAC)
+	}
+	return fmt.Sprintf("%d", a.BaseAC)
+}
+
+// armorFile is the 5e armor page under the Rules folder
+func armorFile(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Equipment", "Armor.md")
+}
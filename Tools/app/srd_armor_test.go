@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const armorTables = `**Table- Armor**
+
+| Armor            | Cost     | Armor Class (AC)          | Strength | Stealth      | Weight |
+|------------------|----------|---------------------------|----------|--------------|--------|
+| **Light Armor**  |          |                           |          |              |        |
+| Studded leather  | 45 gp    | 12 + Dex modifier         | -        | -            | 13 lb. |
+| **Medium Armor** |          |                           |          |              |        |
+| Half plate       | 750 gp   | 15 + Dex modifier (max 2) | -        | Disadvantage | 40 lb. |
+| **Heavy Armor**  |          |                           |          |              |        |
+| Chain mail       | 75 gp    | 16                        | Str 13   | Disadvantage | 55 lb. |
+| Shield           |          |                           |          |              |        |
+| Shield           | 10 gp    | +2                        | -        | -            | 6 lb.  |
+| Mithral coat     | 900 gp   | very good                 | -        | -            | 5 lb.  |
+
+***Studded Leather***. Made from tough but flexible leather.
+
+***Shields***. A shield is carried in one hand.
+
+**Table- Donning and Doffing Armor**
+
+| Category     | Don        | Doff      |
+|--------------|------------|-----------|
+| Light Armor  | 1 minute   | 1 minute  |
+| Medium Armor | 5 minutes  | 1 minute  |
+| Heavy Armor  | 10 minutes | 5 minutes |
+| Shield       | 1 action   | 1 action  |
+`
+
+func TestImportArmor(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "Armor.md")
+	if err := os.WriteFile(file, []byte(armorTables), 0644); err != nil {
+		t.Fatal(err)
+	}
+	armor, issues, err := importArmor(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The mithral coat's armor class can't be read
+	if len(issues) != 1 {
+		t.Errorf("got %d issues, want 1: %v", len(issues), issues)
+	}
+
+	want := []SRDArmor{
+		{Name: "Studded leather", Category: "light", Cost: "45 gp", BaseAC: 12, AddDex: true, Weight: "13 lb.", DonTime: "1 minute", DoffTime: "1 minute", Description: "Made from tough but flexible leather."},
+		{Name: "Half plate", Category: "medium", Cost: "750 gp", BaseAC: 15, AddDex: true, MaxDex: 2, StealthDisadvantage: true, Weight: "40 lb.", DonTime: "5 minutes", DoffTime: "1 minute"},
+		{Name: "Chain mail", Category: "heavy", Cost: "75 gp", BaseAC: 16, StrengthRequirement: 13, StealthDisadvantage: true, Weight: "55 lb.", DonTime: "10 minutes", DoffTime: "5 minutes"},
+		{Name: "Shield", Category: "shield", Cost: "10 gp", BaseAC: 2, Weight: "6 lb.", DonTime: "1 action", DoffTime: "1 action", Description: "A shield is carried in one hand."},
+	}
+	if len(armor) != len(want) {
+		t.Fatalf("got %d armors, want %d: %+v", len(armor), len(want), armor)
+	}
+	for i := range want {
+		if armor[i] != want[i] {
+			t.Errorf("got %+v, want %+v", armor[i], want[i])
+		}
+	}
+}
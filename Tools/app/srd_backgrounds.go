@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 5e backgrounds and feats from Rules/DND.SRD.Wiki/Characterizations
+
+type SRDBackground struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Feature     Trait  `json:"feature"`
+	Grants
+}
+
+type SRDFeat struct {
+	Name         string   `json:"name"`
+	Prerequisite string   `json:"prerequisite"` // "Strength 13 or higher"
+	Description  string   `json:"description"`
+	Benefits     []string `json:"benefits"`
+	Grants
+}
+
+var (
+	backgroundGoldPattern     = regexp.MustCompile(`^(.+?) containing (\d+) gp$`)
+	featPrerequisitePattern   = regexp.MustCompile(`^\*Prerequisite: (.+)\*$`)
+	featIncreasePattern       = regexp.MustCompile(`Increase your (\w+) score by (\d)`)
+	featAbilityMinimumPattern = regexp.MustCompile(`(\w+) (\d+) or higher`)
+)
+
+// importBackgrounds parses the "## Name" sections of the backgrounds page.
+// The sections before the first one explain backgrounds in general
+func importBackgrounds(file string) ([]SRDBackground, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading backgrounds: %v", err)
+	}
+
+	var backgrounds []SRDBackground
+	var issues []ImportIssue
+	var current *SRDBackground
+	section := "" // the "### Feature: Name" or other subsection we're in
+	for _, paragraph := range wikiParagraphs(string(data)) {
+		switch {
+		case strings.HasPrefix(paragraph, "## "):
+			backgrounds = append(backgrounds, SRDBackground{Name: headingText(paragraph)})
+			current = &backgrounds[len(backgrounds)-1]
+			section = ""
+			continue
+		case current == nil:
+			continue
+		case strings.HasPrefix(paragraph, "### "):
+			section = headingText(paragraph)
+			if name, ok := strings.CutPrefix(section, "Feature: "); ok {
+				current.Feature.Name = name
+			}
+			continue
+		}
+
+		if section != "" {
+			if section == "Feature: "+current.Feature.Name {
+				current.Feature.Description = joinParagraphs(current.Feature.Description, paragraph)
+			}
+			continue // suggested characteristics
+		}
+		label, value, ok := boldField(paragraph)
+		if !ok {
+			current.Description = joinParagraphs(current.Description, paragraph)
+			continue
+		}
+		if err := parseBackgroundField(current, label, value); err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("%s: %v", current.Name, err)})
+		}
+	}
+	return backgrounds, issues, nil
+}
+
+// parseBackgroundField reads the "**Skill Proficiencies:** Insight, Religion" lines
+func parseBackgroundField(background *SRDBackground, label, value string) error {
+	switch label {
+	case "Skill Proficiencies":
+		background.Skills = splitList(value)
+	case "Tool Proficiencies":
+		words := strings.Fields(strings.ToLower(value))
+		if strings.Contains(value, "of your choice") && len(words) > 0 {
+			background.ToolChoices = numberWords[words[0]]
+		} else {
+			background.Proficiencies = splitList(value)
+		}
+	case "Languages":
+		words := strings.Fields(strings.ToLower(value))
+		if strings.Contains(value, "of your choice") && len(words) > 0 {
+			background.LanguageChoices = numberWords[words[0]]
+		} else {
+			background.Languages = splitList(value)
+		}
+	case "Equipment":
+		for _, item := range splitOutsideParens(value) {
+			item = strings.TrimPrefix(item, "and ")
+			if match := backgroundGoldPattern.FindStringSubmatch(item); match != nil {
+				background.Gold, _ = strconv.Atoi(match[2])
+				item = match[1]
+			}
+			background.Equipment = append(background.Equipment, item)
+		}
+	default:
+		return fmt.Errorf("unknown field %q", label)
+	}
+	return nil
+}
+
+// splitOutsideParens splits on ", " except inside parentheses
+func splitOutsideParens(text string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(text[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(text[start:]))
+}
+
+// importFeats parses the "## Name" sections of the feats page
+func importFeats(file string) ([]SRDFeat, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading feats: %v", err)
+	}
+
+	var feats []SRDFeat
+	var current *SRDFeat
+	for _, paragraph := range wikiParagraphs(string(data)) {
+		switch {
+		case strings.HasPrefix(paragraph, "## "):
+			feats = append(feats, SRDFeat{Name: headingText(paragraph)})
+			current = &feats[len(feats)-1]
+		case current == nil:
+			continue
+		case featPrerequisitePattern.MatchString(paragraph):
+			current.Prerequisite = featPrerequisitePattern.FindStringSubmatch(paragraph)[1]
+		case strings.HasPrefix(paragraph, "- "):
+			for _, line := range strings.Split(paragraph, "\n") {
+				benefit := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+				current.Benefits = append(current.Benefits, benefit)
+				for _, match := range featIncreasePattern.FindAllStringSubmatch(benefit, -1) {
+					if current.AbilityIncreases == nil {
+						current.AbilityIncreases = make(map[string]int)
+					}
+					current.AbilityIncreases[match[1]], _ = strconv.Atoi(match[2])
+				}
+			}
+		default:
+			current.Description = joinParagraphs(current.Description, paragraph)
+		}
+	}
+	return feats, nil, nil
+}
+
+// meetsPrerequisite checks ability minimums like "Strength 13 or higher".
+// Prerequisites it can't read are left to the player
+func (f SRDFeat) meetsPrerequisite(c Character) bool {
+	for _, match := range featAbilityMinimumPattern.FindAllStringSubmatch(f.Prerequisite, -1) {
+		minimum, _ := strconv.Atoi(match[2])
+		if isAbility(match[1]) && c.Abilities.score(match[1]) < minimum {
+			return false
+		}
+	}
+	return true
+}
+
+// isAbility reports whether name is one of the six ability names
+func isAbility(name string) bool {
+	for _, ability := range abilityNames {
+		if strings.EqualFold(ability, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// background finds a catalog background by name
+func (d SRDData) background(name string) (SRDBackground, bool) {
+	for _, background := range d.Backgrounds {
+		if strings.EqualFold(background.Name, name) {
+			return background, true
+		}
+	}
+	return SRDBackground{}, false
+}
+
+// feat finds a catalog feat by name
+func (d SRDData) feat(name string) (SRDFeat, bool) {
+	for _, feat := range d.Feats {
+		if strings.EqualFold(feat.Name, name) {
+			return feat, true
+		}
+	}
+	return SRDFeat{}, false
+}
+
+// openBackgroundPicker lets the user choose an SRD background, swapping
+// the old background's grants for the new one's
+func (m *Model) openBackgroundPicker() {
+	if len(m.srdData.Backgrounds) == 0 {
+		m.message = "No SRD backgrounds loaded"
+		return
+	}
+
+	var options []string
+	for _, background := range m.srdData.Backgrounds {
+		options = append(options, fmt.Sprintf("%s (%s)", background.Name, strings.Join(background.Skills, ", ")))
+	}
+	m.picker = &catalogPicker{
+		title:   "Choose Background",
+		options: options,
+		choose: func(m *Model, index int) {
+			background := m.srdData.Backgrounds[index]
+			m.character.Background = background.Name
+			m.grantFrom("background", background.Grants)
+
+			m.message = fmt.Sprintf("Background: %s", background.Name)
+			if choices := background.choices(); choices != "" {
+				m.message += " (choose " + choices + ")"
+			}
+		},
+	}
+}
+
+// openFeatPicker lets the user take an SRD feat, or give up one they have
+func (m *Model) openFeatPicker() {
+	if len(m.srdData.Feats) == 0 {
+		m.message = "No SRD feats loaded"
+		return
+	}
+
+	var options []string
+	for _, feat := range m.srdData.Feats {
+		option := feat.Name
+		if feat.Prerequisite != "" {
+			option += " (" + feat.Prerequisite + ")"
+		}
+		if containsFold(m.character.Feats, feat.Name) {
+			option += " [taken]"
+		}
+		options = append(options, option)
+	}
+	m.picker = &catalogPicker{
+		title:   "Take or Remove Feat",
+		options: options,
+		choose: func(m *Model, index int) {
+			feat := m.srdData.Feats[index]
+			switch {
+			case containsFold(m.character.Feats, feat.Name):
+				m.character.Feats = removeFold(m.character.Feats, feat.Name)
+				m.revokeFrom("feat:" + feat.Name)
+				m.message = fmt.Sprintf("Removed feat %s", feat.Name)
+			case !feat.meetsPrerequisite(m.character):
+				m.message = fmt.Sprintf("%s requires %s", feat.Name, feat.Prerequisite)
+			default:
+				m.character.Feats = append(m.character.Feats, feat.Name)
+				m.grantFrom("feat:"+feat.Name, feat.Grants)
+				m.message = fmt.Sprintf("Took feat %s", feat.Name)
+			}
+		},
+	}
+}
+
+// backgroundsFile is the backgrounds page under the Rules folder
+func backgroundsFile(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Characterizations", "Backgrounds.md")
+}
+
+// featsFile is the feats page under the Rules folder
+func featsFile(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Characterizations", "Feats.md")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBackgroundField(t *testing.T) {
+	tests := []struct {
+		label string
+		value string
+		want  Grants
+		err   bool
+	}{
+		{"Skill Proficiencies", "Insight, Religion", Grants{Skills: []string{"Insight", "Religion"}}, false},
+		{"Tool Proficiencies", "One type of gaming set of your choice", Grants{ToolChoices: 1}, false},
+		{"Tool Proficiencies", "Thieves' tools", Grants{Proficiencies: []string{"Thieves' tools"}}, false},
+		{"Languages", "Two of your choice", Grants{LanguageChoices: 2}, false},
+		{"Equipment", "A holy symbol (a gift to you when you entered the priesthood), 5 sticks of incense, and a belt pouch containing 15 gp",
+			Grants{Equipment: []string{"A holy symbol (a gift to you when you entered the priesthood)", "5 sticks of incense", "a belt pouch"}, Gold: 15}, false},
+		{"Favorite Schemes", "Cheat at cards", Grants{}, true},
+	}
+	for _, tt := range tests {
+		var background SRDBackground
+		err := parseBackgroundField(&background, tt.label, tt.value)
+		if (err != nil) != tt.err {
+			t.Errorf("parseBackgroundField(%q) error = %v, want error %v", tt.label, err, tt.err)
+		}
+		if !reflect.DeepEqual(background.Grants, tt.want) {
+			t.Errorf("parseBackgroundField(%q, %q) = %+v, want %+v", tt.label, tt.value, background.Grants, tt.want)
+		}
+	}
+}
+
+func TestSplitOutsideParens(t *testing.T) {
+	got := splitOutsideParens("a crowbar, a set of dark common clothes (with a hood, and gloves), and a pouch")
+	want := []string{"a crowbar", "a set of dark common clothes (with a hood, and gloves)", "and a pouch"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitOutsideParens = %q, want %q", got, want)
+	}
+}
+
+func TestMeetsPrerequisite(t *testing.T) {
+	tests := []struct {
+		prerequisite string
+		strength     int
+		want         bool
+	}{
+		{"", 8, true},
+		{"Strength 13 or higher", 13, true},
+		{"Strength 13 or higher", 12, false},
+		{"The ability to cast at least one spell", 8, true},
+	}
+	for _, tt := range tests {
+		var c Character
+		c.Abilities.Strength = tt.strength
+		feat := SRDFeat{Prerequisite: tt.prerequisite}
+		if got := feat.meetsPrerequisite(c); got != tt.want {
+			t.Errorf("%q with Strength %d: meetsPrerequisite = %v, want %v", tt.prerequisite, tt.strength, got, tt.want)
+		}
+	}
+}
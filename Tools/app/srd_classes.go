@@ -0,0 +1,573 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 5e classes from Rules/DND.SRD.Wiki/Classes
+
+type ClassDefinition struct {
+	Name                string         `json:"name"`
+	HitDie              int            `json:"hit_die"` // 6 for a d6
+	Armor               []string       `json:"armor"`
+	Weapons             []string       `json:"weapons"`
+	Tools               []string       `json:"tools"`
+	SavingThrows        []string       `json:"saving_throws"` // "Intelligence", "Wisdom"
+	SkillChoices        int            `json:"skill_choices"`
+	SkillOptions        []string       `json:"skill_options"` // empty when any skill will do
+	Equipment           []string       `json:"equipment"`     // "(a) a quarterstaff or (b) a dagger"
+	SpellcastingAbility string         `json:"spellcasting_ability,omitempty"`
+	PreparesSpells      bool           `json:"prepares_spells,omitempty"`    // prepares ability modifier + level spells a day
+	PrepareHalfLevel    bool           `json:"prepare_half_level,omitempty"` // the paladin's ability modifier + half level
+	RitualCasting       bool           `json:"ritual_casting,omitempty"`
+	Spellbook           bool           `json:"spellbook,omitempty"` // prepares from the spells copied into a book
+	Levels              []ClassLevel   `json:"levels"`
+	Features            []ClassFeature `json:"features"`
+	SubclassGroup       string         `json:"subclass_group"` // "Arcane Traditions"
+	Subclasses          []Subclass     `json:"subclasses"`
+}
+
+// ClassLevel is one row of a class's level table
+type ClassLevel struct {
+	Level            int               `json:"level"`
+	ProficiencyBonus int               `json:"proficiency_bonus"`
+	Features         []string          `json:"features"` // as the table names them: "Extra Attack (2)"
+	CantripsKnown    int               `json:"cantrips_known,omitempty"`
+	SpellsKnown      int               `json:"spells_known,omitempty"`
+	SpellSlots       []int             `json:"spell_slots,omitempty"` // per spell level
+	PactSlots        int               `json:"pact_slots,omitempty"`  // the warlock's, all of PactSlotLevel
+	PactSlotLevel    int               `json:"pact_slot_level,omitempty"`
+	Columns          map[string]string `json:"columns,omitempty"` // the class's own columns: "Rages": "2"
+}
+
+// ClassFeature is a "### Name" class feature or a "#### Name" subclass
+// feature. Levels lists every level the level table mentions it at, the
+// first being when the character gains it
+type ClassFeature struct {
+	Name        string `json:"name"`
+	Levels      []int  `json:"levels"`
+	Description string `json:"description"`
+}
+
+// Subclass is a "### Name" section under the class's "## Group" heading
+type Subclass struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Features    []ClassFeature `json:"features"`
+}
+
+var (
+	classHitDiePattern       = regexp.MustCompile(`^1d(\d+) per`)
+	classSkillsPattern       = regexp.MustCompile(`^Choose (\w+)(?: skills)? from (.+)$`)
+	classAnySkillsPattern    = regexp.MustCompile(`^Choose any (\w+)`)
+	classSpellAbilityPattern = regexp.MustCompile(`Spell save DC\*\* = 8 \+ your proficiency bonus \+ your (\w+) modifier`)
+	classFeatureLevelPattern = regexp.MustCompile(`(?i)\b(?:at|reach) (\d+)(?:st|nd|rd|th)\b`)
+	classEquipmentPattern    = regexp.MustCompile(`\(\*(\w)\*\)`)
+	classPreparePattern      = regexp.MustCompile(`spells (?:from your spellbook )?equal to your \w+ modifier \+ (half )?your \w+ level`)
+	classRitualPattern       = regexp.MustCompile(`(?m)^#{3,4} Ritual Casting`)
+	classSpellbookPattern    = regexp.MustCompile(`(?m)^#{3,4} Spellbook`)
+)
+
+// importClasses parses every class file in dir
+func importClasses(dir string) ([]ClassDefinition, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing classes: %v", err)
+	}
+
+	var classes []ClassDefinition
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		class, problems, err := parseClass(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		for _, problem := range problems {
+			issues = append(issues, ImportIssue{File: file, Reason: problem})
+		}
+		classes = append(classes, class)
+	}
+	return classes, issues, nil
+}
+
+// parseClass reads the "### Class Features" basics, the "**Table- The
+// Name**" level table, the "### Feature" sections and the "### Subclass"
+// sections under the "## Group" heading. Problems are table features that
+// match no section, which still import
+func parseClass(text string) (ClassDefinition, []string, error) {
+	var class ClassDefinition
+
+	paragraphs := wikiParagraphs(text)
+	if len(paragraphs) == 0 || !strings.HasPrefix(paragraphs[0], "# ") {
+		return class, nil, fmt.Errorf("missing class heading")
+	}
+	class.Name = headingText(paragraphs[0])
+
+	basics := false // inside "### Class Features"
+	subsection := ""
+	var feature *ClassFeature // the section paragraphs belong to
+	var subclass *Subclass
+	for _, paragraph := range paragraphs[1:] {
+		switch {
+		case strings.HasPrefix(paragraph, "|"), strings.HasPrefix(paragraph, "**Table- "):
+			continue // the level table is read below; tables in features are left out
+		case strings.HasPrefix(paragraph, "## "):
+			class.SubclassGroup = headingText(paragraph)
+			basics, feature, subclass = false, nil, nil
+			continue
+		case strings.HasPrefix(paragraph, "### "):
+			name := headingText(paragraph)
+			basics, feature, subclass = name == "Class Features", nil, nil
+			switch {
+			case basics:
+			case class.SubclassGroup != "":
+				class.Subclasses = append(class.Subclasses, Subclass{Name: name})
+				subclass = &class.Subclasses[len(class.Subclasses)-1]
+			default:
+				// A second section of the same name (the Warlock's list of
+				// invocations) continues the first
+				feature = findClassFeature(class.Features, name)
+				if feature == nil {
+					class.Features = append(class.Features, ClassFeature{Name: name})
+					feature = &class.Features[len(class.Features)-1]
+				}
+			}
+			continue
+		case strings.HasPrefix(paragraph, "#### "):
+			name := headingText(paragraph)
+			switch {
+			case basics:
+				subsection = name
+				continue
+			case subclass != nil:
+				subclass.Features = append(subclass.Features, ClassFeature{Name: name})
+				feature = &subclass.Features[len(subclass.Features)-1]
+				continue
+			}
+			paragraph = name // an option within the feature, such as a fighting style
+		}
+
+		switch {
+		case basics:
+			parseClassBasics(&class, subsection, paragraph)
+		case feature != nil:
+			feature.Description = joinParagraphs(feature.Description, paragraph)
+		case subclass != nil:
+			subclass.Description = joinParagraphs(subclass.Description, paragraph)
+		}
+	}
+
+	if class.HitDie == 0 {
+		return class, nil, fmt.Errorf("missing hit dice")
+	}
+	if match := classSpellAbilityPattern.FindStringSubmatch(text); match != nil {
+		class.SpellcastingAbility = match[1]
+	}
+	if match := classPreparePattern.FindStringSubmatch(text); match != nil {
+		class.PreparesSpells = true
+		class.PrepareHalfLevel = match[1] != ""
+	}
+	class.RitualCasting = classRitualPattern.MatchString(text)
+	class.Spellbook = classSpellbookPattern.MatchString(text)
+
+	header, rows, err := wikiTableHeader(text, "The "+class.Name)
+	if err != nil {
+		return class, nil, err
+	}
+	for _, row := range rows {
+		level, err := parseClassLevel(header, row)
+		if err != nil {
+			return class, nil, err
+		}
+		class.Levels = append(class.Levels, level)
+	}
+	return class, assignFeatureLevels(&class), nil
+}
+
+// parseClassBasics reads the hit dice, proficiency and equipment paragraphs
+// under "### Class Features"
+func parseClassBasics(class *ClassDefinition, subsection, paragraph string) {
+	if subsection == "Equipment" && strings.HasPrefix(paragraph, "- ") {
+		for _, line := range strings.Split(paragraph, "\n") {
+			item := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+			class.Equipment = append(class.Equipment, classEquipmentPattern.ReplaceAllString(item, "($1)"))
+		}
+		return
+	}
+
+	label, value, ok := boldField(paragraph)
+	if !ok {
+		return
+	}
+	list := func() []string {
+		if strings.EqualFold(value, "None") {
+			return nil
+		}
+		return splitList(value)
+	}
+	switch label {
+	case "Hit Dice":
+		if match := classHitDiePattern.FindStringSubmatch(value); match != nil {
+			class.HitDie, _ = strconv.Atoi(match[1])
+		}
+	case "Armor":
+		class.Armor = list()
+	case "Weapons":
+		class.Weapons = list()
+	case "Tools":
+		class.Tools = list()
+	case "Saving Throws":
+		class.SavingThrows = list()
+	case "Skills":
+		if match := classSkillsPattern.FindStringSubmatch(value); match != nil {
+			class.SkillChoices = numberWords[match[1]]
+			class.SkillOptions = joinSkillNames(splitList(match[2]))
+		} else if match := classAnySkillsPattern.FindStringSubmatch(value); match != nil {
+			class.SkillChoices = numberWords[match[1]]
+		}
+	}
+}
+
+// joinSkillNames mends skill names the wiki splits with a stray comma, as
+// in the Fighter's "Animal, Handling"
+func joinSkillNames(names []string) []string {
+	var joined []string
+	for i := 0; i < len(names); i++ {
+		if i+1 < len(names) && !isSkill(names[i]) && isSkill(names[i]+" "+names[i+1]) {
+			joined = append(joined, names[i]+" "+names[i+1])
+			i++
+			continue
+		}
+		joined = append(joined, names[i])
+	}
+	return joined
+}
+
+// isSkill reports whether name is one of the 5e skills
+func isSkill(name string) bool {
+	for _, skill := range (dnd5eRuleset{}).Skills() {
+		if strings.EqualFold(skill.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClassLevel reads one row of the level table, using the header to
+// tell the class's columns apart
+func parseClassLevel(header, row []string) (ClassLevel, error) {
+	var level ClassLevel
+	if len(row) != len(header) {
+		return level, fmt.Errorf("level table row %q has %d cells, want %d", strings.Join(row, " | "), len(row), len(header))
+	}
+
+	for i, column := range header {
+		cell := dashToEmpty(row[i])
+		switch column {
+		case "Level":
+			n, err := parseOrdinal(cell)
+			if err != nil {
+				return level, fmt.Errorf("bad level %q", cell)
+			}
+			level.Level = n
+		case "Proficiency Bonus":
+			level.ProficiencyBonus, _ = strconv.Atoi(strings.TrimPrefix(cell, "+"))
+		case "Features":
+			if cell != "" {
+				level.Features = splitOutsideParens(cell)
+			}
+		case "Cantrips Known":
+			level.CantripsKnown, _ = strconv.Atoi(cell)
+		case "Spells Known":
+			level.SpellsKnown, _ = strconv.Atoi(cell)
+		case "Spell Slots":
+			level.PactSlots, _ = strconv.Atoi(cell)
+		case "Slot Level":
+			level.PactSlotLevel, _ = parseOrdinal(cell)
+		default:
+			if spellLevel, err := parseOrdinal(column); err == nil {
+				for len(level.SpellSlots) < spellLevel {
+					level.SpellSlots = append(level.SpellSlots, 0)
+				}
+				level.SpellSlots[spellLevel-1], _ = strconv.Atoi(cell)
+				continue
+			}
+			if level.Columns == nil {
+				level.Columns = make(map[string]string)
+			}
+			level.Columns[column] = cell
+		}
+	}
+	return level, nil
+}
+
+// assignFeatureLevels gives each feature the levels the table lists it at.
+// Features the table doesn't name ("Beginning at 2nd level...") take the
+// level from their text, else the level of the feature before them
+func assignFeatureLevels(class *ClassDefinition) []string {
+	var problems []string
+	for _, level := range class.Levels {
+		for _, name := range level.Features {
+			if strings.HasSuffix(strings.ToLower(name), " feature") {
+				continue // "Arcane Tradition Feature": a subclass feature comes in
+			}
+			features := tableFeatures(class.Features, name)
+			if len(features) == 0 {
+				problems = append(problems, fmt.Sprintf("level %d feature %q has no section", level.Level, name))
+			}
+			for _, feature := range features {
+				if n := len(feature.Levels); n == 0 || feature.Levels[n-1] != level.Level {
+					feature.Levels = append(feature.Levels, level.Level)
+				}
+			}
+		}
+	}
+
+	levelFromText := func(features []ClassFeature, previous int) {
+		for i := range features {
+			if len(features[i].Levels) == 0 {
+				if match := classFeatureLevelPattern.FindStringSubmatch(features[i].Description); match != nil {
+					previous, _ = strconv.Atoi(match[1])
+				}
+				features[i].Levels = []int{previous}
+			}
+			previous = features[i].Levels[0]
+		}
+	}
+	levelFromText(class.Features, 1)
+	subclassLevel := 1 // "Sacred Oath" for "Sacred Oaths", where the table names it so
+	if feature := findClassFeature(class.Features, class.SubclassGroup); feature != nil {
+		subclassLevel = feature.Levels[0]
+	}
+	for i := range class.Subclasses {
+		levelFromText(class.Subclasses[i].Features, subclassLevel)
+	}
+	return problems
+}
+
+// findClassFeature finds a feature by the name the level table uses, which
+// may add a count ("Extra Attack (2)") or drop a plural ("Signature Spell")
+func findClassFeature(features []ClassFeature, name string) *ClassFeature {
+	key := classFeatureKey(name)
+	for i := range features {
+		if classFeatureKey(features[i].Name) == key {
+			return &features[i]
+		}
+	}
+	return nil
+}
+
+// tableFeatures finds the features a level table entry refers to. Besides
+// the plain names, "Favored Enemy and Natural Explorer improvements" improves
+// two features, and "Relentless" or "Aura improvements" is short for every
+// feature the name starts ("Relentless Rage", "Aura of Protection")
+func tableFeatures(features []ClassFeature, name string) []*ClassFeature {
+	if feature := findClassFeature(features, name); feature != nil {
+		return []*ClassFeature{feature}
+	}
+
+	var found []*ClassFeature
+	lower := strings.ToLower(name)
+	lower = strings.TrimSuffix(strings.TrimSuffix(lower, " improvements"), " improvement")
+	for _, part := range strings.Split(lower, " and ") {
+		if feature := findClassFeature(features, part); feature != nil {
+			found = append(found, feature)
+			continue
+		}
+		for i := range features {
+			if strings.HasPrefix(strings.ToLower(features[i].Name), part+" ") {
+				found = append(found, &features[i])
+			}
+		}
+	}
+	return found
+}
+
+func classFeatureKey(name string) string {
+	if i := strings.Index(name, " ("); i >= 0 {
+		name = name[:i]
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), "s")
+}
+
+// level returns the level table row for a character level, capped at 20
+func (c ClassDefinition) level(level int) (ClassLevel, bool) {
+	if len(c.Levels) == 0 || level < 1 {
+		return ClassLevel{}, false
+	}
+	return c.Levels[min(level, len(c.Levels))-1], true
+}
+
+// subclassLevel is the level the class picks its subclass at, when the
+// first subclass's earliest feature comes in
+func (c ClassDefinition) subclassLevel() int {
+	level := 0
+	if len(c.Subclasses) > 0 {
+		for _, feature := range c.Subclasses[0].Features {
+			if level == 0 || feature.Levels[0] < level {
+				level = feature.Levels[0]
+			}
+		}
+	}
+	return max(level, 1)
+}
+
+// subclass finds one of the class's subclasses by name
+func (c ClassDefinition) subclass(name string) (Subclass, bool) {
+	for _, subclass := range c.Subclasses {
+		if strings.EqualFold(subclass.Name, name) {
+			return subclass, true
+		}
+	}
+	return Subclass{}, false
+}
+
+// featuresAt lists the class features a character of the given level has,
+// followed by those of its subclass
+func (c ClassDefinition) featuresAt(level int, subclass string) []ClassFeature {
+	var features []ClassFeature
+	for _, feature := range c.Features {
+		if feature.Levels[0] <= level {
+			features = append(features, feature)
+		}
+	}
+	if sub, ok := c.subclass(subclass); ok {
+		for _, feature := range sub.Features {
+			if feature.Levels[0] <= level {
+				features = append(features, feature)
+			}
+		}
+	}
+	return features
+}
+
+// label renders "Extra Attack (5th, 11th)" with the levels reached so far
+func (f ClassFeature) label(level int) string {
+	var levels []string
+	for _, n := range f.Levels {
+		if n <= level {
+			levels = append(levels, ordinal(n))
+		}
+	}
+	return fmt.Sprintf("%s (%s)", f.Name, strings.Join(levels, ", "))
+}
+
+// ordinal renders 1 as "1st" and 12 as "12th"
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// class finds a catalog class by name
+func (d SRDData) class(name string) (ClassDefinition, bool) {
+	for _, class := range d.Classes {
+		if strings.EqualFold(class.Name, strings.TrimSpace(name)) {
+			return class, true
+		}
+	}
+	return ClassDefinition{}, false
+}
+
+// openSubclassPicker lets the user choose a subclass of the character's
+// class, asking which class first once they have several
+func (m *Model) openSubclassPicker() {
+	classes := m.classLevels()
+	if len(classes) == 1 {
+		m.openClassSubclassPicker(0)
+		return
+	}
+	var options []string
+	for _, class := range classes {
+		options = append(options, fmt.Sprintf("%s %d", class.Name, class.Level))
+	}
+	m.picker = &catalogPicker{
+		title:   "Choose Subclass For",
+		options: options,
+		choose: func(m *Model, index int) {
+			m.openClassSubclassPicker(index)
+		},
+	}
+}
+
+// openClassSubclassPicker lets the user choose a subclass of the
+// character's ith class
+func (m *Model) openClassSubclassPicker(i int) {
+	name := m.classLevels()[i].Name
+	class, ok := m.srdData.class(name)
+	if !ok || len(class.Subclasses) == 0 {
+		m.message = fmt.Sprintf("No SRD subclasses for %q", name)
+		return
+	}
+
+	var options []string
+	for _, subclass := range class.Subclasses {
+		options = append(options, fmt.Sprintf("%s (from %s level)", subclass.Name, ordinal(class.subclassLevel())))
+	}
+	m.picker = &catalogPicker{
+		title:   "Choose " + strings.TrimSuffix(class.SubclassGroup, "s"),
+		options: options,
+		choose: func(m *Model, index int) {
+			m.setSubclass(i, class.Subclasses[index].Name)
+			m.message = fmt.Sprintf("Subclass: %s", class.Subclasses[index].Name)
+		},
+	}
+}
+
+// renderClassFeatures lists the features the character has at its level,
+// class by class once they have several
+func (m Model) renderClassFeatures() string {
+	classes := m.classLevels()
+	view := ""
+	for _, cl := range classes {
+		class, ok := m.srdData.class(cl.Name)
+		if !ok {
+			continue
+		}
+		if view == "" {
+			view = titleStyle.Render("Class Features") + "\n"
+		}
+		if len(classes) > 1 {
+			view += fmt.Sprintf("%s %d\n", class.Name, cl.Level)
+		}
+		if class.SubclassGroup != "" {
+			subclass := cl.Subclass
+			if subclass == "" {
+				subclass = "none"
+			}
+			view += fmt.Sprintf("%s: %s", strings.TrimSuffix(class.SubclassGroup, "s"), subclass)
+			if m.mode == "edit" {
+				view += " (ctrl+g to choose)"
+			}
+			view += "\n"
+		}
+		for _, feature := range class.featuresAt(cl.Level, cl.Subclass) {
+			view += feature.label(cl.Level) + "\n"
+		}
+	}
+	return view
+}
+
+// classesDir is the 5e classes folder under the Rules folder
+func classesDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Classes")
+}
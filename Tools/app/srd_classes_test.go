@@ -0,0 +1,169 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const squireFile = `# Squire
+
+### Class Features
+
+#### Hit Points
+
+**Hit Dice:** 1d8 per squire level
+
+#### Proficiencies
+
+**Armor:** Light armor, shields
+
+**Weapons:** Simple weapons
+
+**Tools:** None
+
+**Saving Throws:** Strength, Charisma
+
+**Skills:** Choose two skills from Animal, Handling, Athletics, and History
+
+#### Equipment
+
+- (*a*) a spear or (*b*) a club
+
+**Table- The Squire**
+
+| Level | Proficiency Bonus | Features                | Errands | 1st | 2nd |
+|-------|-------------------|-------------------------|---------|-----|-----|
+| 1st   | +2                | Fetch, Polish           | 1       | 2   | -   |
+| 2nd   | +2                | Squire's Oath           | 1       | 3   | -   |
+| 3rd   | +2                | Fetch (2), Oath Feature | 2       | 4   | 2   |
+
+### Fetch
+
+You fetch things.
+
+### Polish
+
+You polish things.
+
+### Tilt
+
+Starting at 2nd level, you tilt at things.
+
+### Squire's Oath
+
+At 2nd level, you swear an oath.
+
+## Squire's Oaths
+
+### Oath of the Stable
+
+Squires of the stable care for horses.
+
+#### Grooming
+
+You groom horses.
+
+#### Farrier
+
+When you reach 3rd level, you shoe horses.
+`
+
+func TestParseClass(t *testing.T) {
+	class, problems, err := parseClass(squireFile)
+	if err != nil {
+		t.Fatalf("parseClass: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("parseClass problems: %q", problems)
+	}
+
+	if class.Name != "Squire" || class.HitDie != 8 || class.SkillChoices != 2 || class.SubclassGroup != "Squire's Oaths" {
+		t.Errorf("class = %q, d%d, %d skills, group %q", class.Name, class.HitDie, class.SkillChoices, class.SubclassGroup)
+	}
+	if want := []string{"Animal Handling", "Athletics", "History"}; !reflect.DeepEqual(class.SkillOptions, want) {
+		t.Errorf("skill options = %q, want %q", class.SkillOptions, want)
+	}
+	if class.Tools != nil {
+		t.Errorf("tools = %q, want none", class.Tools)
+	}
+	if want := []string{"(a) a spear or (b) a club"}; !reflect.DeepEqual(class.Equipment, want) {
+		t.Errorf("equipment = %q, want %q", class.Equipment, want)
+	}
+
+	wantLevel := ClassLevel{Level: 3, ProficiencyBonus: 2, Features: []string{"Fetch (2)", "Oath Feature"},
+		SpellSlots: []int{4, 2}, Columns: map[string]string{"Errands": "2"}}
+	if level, ok := class.level(3); !ok || !reflect.DeepEqual(level, wantLevel) {
+		t.Errorf("level 3 = %+v, want %+v", level, wantLevel)
+	}
+
+	wantLevels := map[string][]int{"Fetch": {1, 3}, "Polish": {1}, "Tilt": {2}, "Squire's Oath": {2}}
+	for _, feature := range class.Features {
+		if !reflect.DeepEqual(feature.Levels, wantLevels[feature.Name]) {
+			t.Errorf("%s levels = %v, want %v", feature.Name, feature.Levels, wantLevels[feature.Name])
+		}
+	}
+	if len(class.Subclasses) != 1 {
+		t.Fatalf("got %d subclasses, want 1", len(class.Subclasses))
+	}
+	var subclassLevels [][]int
+	for _, feature := range class.Subclasses[0].Features {
+		subclassLevels = append(subclassLevels, feature.Levels)
+	}
+	if want := [][]int{{2}, {3}}; !reflect.DeepEqual(subclassLevels, want) {
+		t.Errorf("subclass feature levels = %v, want %v", subclassLevels, want)
+	}
+	if got := class.subclassLevel(); got != 2 {
+		t.Errorf("subclassLevel = %d, want 2", got)
+	}
+
+	var names []string
+	for _, feature := range class.featuresAt(2, "Oath of the Stable") {
+		names = append(names, feature.Name)
+	}
+	if want := []string{"Fetch", "Polish", "Tilt", "Squire's Oath", "Grooming"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("featuresAt(2) = %q, want %q", names, want)
+	}
+}
+
+func TestParseClassProblems(t *testing.T) {
+	text := "# Page\n\n### Class Features\n\n**Hit Dice:** 1d6 per page level\n\n**Table- The Page**\n\n" +
+		"| Level | Proficiency Bonus | Features |\n|---|---|---|\n| 1st | +2 | Carry |\n"
+	_, problems, err := parseClass(text)
+	if err != nil {
+		t.Fatalf("parseClass: %v", err)
+	}
+	if want := []string{`level 1 feature "Carry" has no section`}; !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %q, want %q", problems, want)
+	}
+
+	for _, text := range []string{"no heading", "# Page\n\n### Class Features\n\n**Armor:** None"} {
+		if _, _, err := parseClass(text); err == nil {
+			t.Errorf("parseClass(%q) succeeded", text)
+		}
+	}
+}
+
+func TestClassFeatureLabel(t *testing.T) {
+	tests := []struct {
+		levels []int
+		level  int
+		want   string
+	}{
+		{[]int{5}, 5, "Extra Attack (5th)"},
+		{[]int{5, 11, 20}, 12, "Extra Attack (5th, 11th)"},
+	}
+	for _, tt := range tests {
+		feature := ClassFeature{Name: "Extra Attack", Levels: tt.levels}
+		if got := feature.label(tt.level); got != tt.want {
+			t.Errorf("label(%d) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd"} {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
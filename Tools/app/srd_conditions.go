@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Conditions from Rules/DND.SRD.Wiki/Gamemastering/Conditions.md
+
+type SRDCondition struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Rules       []string          `json:"rules"` // the bulleted definitions
+	Effects     []ConditionEffect `json:"effects"`
+	Levels      []ConditionLevel  `json:"levels,omitempty"` // exhaustion
+}
+
+// ConditionLevel is one row of a leveled condition's table. A creature
+// suffers its level's effects and those of every lower level
+type ConditionLevel struct {
+	Level       int               `json:"level"`
+	Description string            `json:"description"`
+	Effects     []ConditionEffect `json:"effects"`
+}
+
+// ConditionEffect is a rule the editor can apply to the derived numbers
+type ConditionEffect struct {
+	Type    string `json:"type"`              // one of the effect* constants
+	Ability string `json:"ability,omitempty"` // for saving throw effects, "" for all saves
+	Detail  string `json:"detail,omitempty"`  // "sight", "while the source of its fear is within line of sight"
+}
+
+const (
+	effectAttackAdvantage      = "attack_advantage"
+	effectAttackDisadvantage   = "attack_disadvantage"
+	effectAttackedAdvantage    = "attacked_advantage"
+	effectAttackedDisadvantage = "attacked_disadvantage"
+	effectAttackedCritical     = "attacked_critical"
+	effectCheckDisadvantage    = "check_disadvantage"
+	effectCheckAutoFail        = "check_autofail"
+	effectSaveDisadvantage     = "save_disadvantage"
+	effectSaveAutoFail         = "save_autofail"
+	effectSpeedZero            = "speed_zero"
+	effectSpeedHalved          = "speed_halved"
+	effectHPMaxHalved          = "hp_max_halved"
+	effectIncapacitated        = "incapacitated"
+	effectResistance           = "resistance"
+	effectDeath                = "death"
+)
+
+var (
+	conditionAbilityPattern = regexp.MustCompile(`(strength|dexterity|constitution|intelligence|wisdom|charisma)`)
+	conditionRollPattern    = regexp.MustCompile(`(advantage|disadvantage) on (?:any )?(.+?)(?: (while .+))?$`)
+)
+
+// conditionEffectPatterns turn a lower-cased clause of a definition into
+// effects. A clause can match several patterns
+var conditionEffectPatterns = []struct {
+	pattern *regexp.Regexp
+	effects func(match []string) []ConditionEffect
+}{
+	{regexp.MustCompile(`attack rolls? against the creature (?:have|has) (advantage|disadvantage)(?: if the attacker is (within 5 feet))?`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: "attacked_" + m[1], Detail: m[2]}}
+	}},
+	{regexp.MustCompile(`^otherwise, the attack roll has (advantage|disadvantage)`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: "attacked_" + m[1], Detail: "beyond 5 feet"}}
+	}},
+	{regexp.MustCompile(`attack rolls have (advantage|disadvantage)`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: "attack_" + m[1]}}
+	}},
+	{conditionRollPattern, func(m []string) []ConditionEffect {
+		var effects []ConditionEffect
+		if strings.Contains(m[2], "attack rolls") {
+			effects = append(effects, ConditionEffect{Type: "attack_" + m[1], Detail: m[3]})
+		}
+		if strings.Contains(m[2], "ability checks") {
+			effects = append(effects, ConditionEffect{Type: "check_" + m[1], Detail: m[3]})
+		}
+		if strings.Contains(m[2], "saving throws") {
+			ability := capitalize(conditionAbilityPattern.FindString(m[2]))
+			effects = append(effects, ConditionEffect{Type: "save_" + m[1], Ability: ability, Detail: m[3]})
+		}
+		return effects
+	}},
+	{regexp.MustCompile(`automatically fails? (.+) saving throws`), func(m []string) []ConditionEffect {
+		var effects []ConditionEffect
+		for _, ability := range conditionAbilityPattern.FindAllString(m[1], -1) {
+			effects = append(effects, ConditionEffect{Type: effectSaveAutoFail, Ability: capitalize(ability)})
+		}
+		return effects
+	}},
+	{regexp.MustCompile(`automatically fails any ability check that requires (\w+)`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectCheckAutoFail, Detail: m[1]}}
+	}},
+	{regexp.MustCompile(`speed (?:becomes|reduced to) 0|can't move(?:,| or|$)`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectSpeedZero}}
+	}},
+	{regexp.MustCompile(`speed halved`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectSpeedHalved}}
+	}},
+	{regexp.MustCompile(`hit point maximum halved`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectHPMaxHalved}}
+	}},
+	{regexp.MustCompile(`creature is incapacitated|can't take actions or reactions`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectIncapacitated}}
+	}},
+	{regexp.MustCompile(`critical hit if the attacker is (within 5 feet)`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectAttackedCritical, Detail: m[1]}}
+	}},
+	{regexp.MustCompile(`resistance to (all damage)`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectResistance, Detail: m[1]}}
+	}},
+	{regexp.MustCompile(`^death$`), func(m []string) []ConditionEffect {
+		return []ConditionEffect{{Type: effectDeath}}
+	}},
+}
+
+// importConditions parses the "## Name" sections of the conditions page
+func importConditions(file string) ([]SRDCondition, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading conditions: %v", err)
+	}
+
+	var conditions []SRDCondition
+	var issues []ImportIssue
+	var current *SRDCondition
+	for _, paragraph := range wikiParagraphs(string(data)) {
+		if strings.HasPrefix(paragraph, "## ") {
+			conditions = append(conditions, SRDCondition{Name: headingText(paragraph)})
+			current = &conditions[len(conditions)-1]
+			continue
+		}
+		if current == nil {
+			continue // the introduction
+		}
+
+		switch {
+		case strings.HasPrefix(paragraph, "- "):
+			for _, line := range strings.Split(paragraph, "\n") {
+				rule := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+				current.Rules = append(current.Rules, rule)
+				current.Effects = append(current.Effects, parseConditionEffects(rule)...)
+			}
+		case strings.HasPrefix(paragraph, "**Table- "), strings.HasPrefix(paragraph, "|"):
+			// Leveled conditions' tables are read below
+		default:
+			current.Description = joinParagraphs(current.Description, paragraph)
+		}
+	}
+
+	for i := range conditions {
+		condition := &conditions[i]
+		rows, err := wikiTable(string(data), condition.Name+" Effects")
+		if err == nil {
+			for _, row := range rows {
+				level, err := strconv.Atoi(row[0])
+				if err != nil || len(row) < 2 {
+					issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("%s: bad level row %q", condition.Name, strings.Join(row, " | "))})
+					continue
+				}
+				condition.Levels = append(condition.Levels, ConditionLevel{Level: level, Description: row[1], Effects: parseConditionEffects(row[1])})
+			}
+		}
+	}
+	return conditions, issues, nil
+}
+
+// parseConditionEffects reads the effects out of one definition, a clause
+// at a time. Clauses about someone else (the charmer) are skipped
+func parseConditionEffects(rule string) []ConditionEffect {
+	var effects []ConditionEffect
+	seen := make(map[ConditionEffect]bool)
+	for _, sentence := range strings.Split(strings.ToLower(rule), ". ") {
+		for _, clause := range strings.Split(strings.TrimSuffix(sentence, "."), ", and ") {
+			clause = strings.TrimSpace(clause)
+			if strings.HasPrefix(clause, "the charmer") {
+				continue
+			}
+			for _, p := range conditionEffectPatterns {
+				match := p.pattern.FindStringSubmatch(clause)
+				if match == nil {
+					continue
+				}
+				for _, effect := range p.effects(match) {
+					if !seen[effect] {
+						seen[effect] = true
+						effects = append(effects, effect)
+					}
+				}
+			}
+		}
+	}
+	return effects
+}
+
+// String describes the effect for the character sheet
+func (e ConditionEffect) String() string {
+	var s string
+	switch e.Type {
+	case effectAttackAdvantage:
+		s = "advantage on attack rolls"
+	case effectAttackDisadvantage:
+		s = "disadvantage on attack rolls"
+	case effectAttackedAdvantage:
+		s = "attacks against have advantage"
+	case effectAttackedDisadvantage:
+		s = "attacks against have disadvantage"
+	case effectAttackedCritical:
+		s = "hits are critical"
+	case effectCheckDisadvantage:
+		s = "disadvantage on ability checks"
+	case effectCheckAutoFail:
+		return "fails checks that require " + e.Detail
+	case effectSaveDisadvantage:
+		s = "disadvantage on saving throws"
+		if e.Ability != "" {
+			s = "disadvantage on " + e.Ability + " saves"
+		}
+	case effectSaveAutoFail:
+		s = "fails " + e.Ability + " saves"
+	case effectSpeedZero:
+		s = "speed 0"
+	case effectSpeedHalved:
+		s = "speed halved"
+	case effectHPMaxHalved:
+		s = "hit point maximum halved"
+	case effectIncapacitated:
+		s = "no actions or reactions"
+	case effectResistance:
+		return "resistance to " + e.Detail
+	case effectDeath:
+		s = "dead"
+	default:
+		s = e.Type
+	}
+	if e.Detail != "" {
+		s += " (" + e.Detail + ")"
+	}
+	return s
+}
+
+// conditionsFile is the conditions page under the Rules folder
+func conditionsFile(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Gamemastering", "Conditions.md")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConditionEffects(t *testing.T) {
+	tests := []struct {
+		rule string
+		want []string // the effects as the sheet shows them
+	}{
+		{"A blinded creature can't see and automatically fails any ability check that requires sight.", []string{"fails checks that require sight"}},
+		{"Attack rolls against the creature have advantage, and the creature's attack rolls have disadvantage.", []string{"attacks against have advantage", "disadvantage on attack rolls"}},
+		{"The charmer has advantage on any ability check to interact socially with the creature.", nil},
+		{"A frightened creature has disadvantage on ability checks and attack rolls while the source of its fear is within line of sight.", []string{"disadvantage on attack rolls (while the source of its fear is within line of sight)", "disadvantage on ability checks (while the source of its fear is within line of sight)"}},
+		{"A grappled creature's speed becomes 0, and it can't benefit from any bonus to its speed.", []string{"speed 0"}},
+		{"The creature automatically fails Strength and Dexterity saving throws.", []string{"fails Strength saves", "fails Dexterity saves"}},
+		{"An attack roll against the creature has advantage if the attacker is within 5 feet of the creature. Otherwise, the attack roll has disadvantage.", []string{"attacks against have advantage (within 5 feet)", "attacks against have disadvantage (beyond 5 feet)"}},
+		{"The creature has disadvantage on Dexterity saving throws.", []string{"disadvantage on Dexterity saves"}},
+		{"Any attack that hits the creature is a critical hit if the attacker is within 5 feet of the creature.", []string{"hits are critical (within 5 feet)"}},
+		{"The creature has resistance to all damage.", []string{"resistance to all damage"}},
+		{"Hit point maximum halved", []string{"hit point maximum halved"}},
+		{"Death", []string{"dead"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, effect := range parseConditionEffects(tt.rule) {
+			got = append(got, effect.String())
+		}
+		if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+			t.Errorf("parseConditionEffects(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestParseConditionDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     int
+		err      bool
+	}{
+		{"", 0, false},
+		{"3", 3, false},
+		{"10 rounds", 10, false},
+		{"1 minute", 10, false},
+		{"8 hours", 4800, false},
+		{"a while", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseConditionDuration(tt.duration)
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("parseConditionDuration(%q) = %d, %v, want %d, error %v", tt.duration, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestRollMode(t *testing.T) {
+	poisoned := []ConditionEffect{{Type: effectAttackDisadvantage}, {Type: effectCheckDisadvantage}}
+	restrained := []ConditionEffect{{Type: effectSaveDisadvantage, Ability: "Dexterity"}}
+	tests := []struct {
+		name    string
+		effects []ConditionEffect
+		kind    string
+		ability string
+		want    string
+	}{
+		{"poisoned attack", poisoned, "attack", "", "disadvantage"},
+		{"poisoned save", poisoned, "save", "Strength", ""},
+		{"restrained Dexterity save", restrained, "save", "Dexterity", "disadvantage"},
+		{"restrained Strength save", restrained, "save", "Strength", ""},
+		{"advantage cancels", append([]ConditionEffect{{Type: effectAttackAdvantage}}, poisoned...), "attack", "", ""},
+	}
+	for _, tt := range tests {
+		if got := rollMode(tt.effects, tt.kind, tt.ability); got != tt.want {
+			t.Errorf("%s: rollMode = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
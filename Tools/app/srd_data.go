@@ -0,0 +1,198 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The SRD dataset ships inside the binary. It is generated from the Rules wiki:
+//
+//go:generate go run . import -rules ../../Rules -out srd
+//go:embed srd/*.json
+var embeddedSRD embed.FS
+
+// srdDataPathEnv lists extra directories of srd_*.json files, separated like
+// PATH. Each is layered over the embedded data, later directories winning
+const srdDataPathEnv = "SRD_DATA_PATH"
+
+// srdDataset is one srd_*.json file and how to layer it into SRDData
+type srdDataset struct {
+	name  string // shown in the status line
+	file  string
+	layer func(data *SRDData, raw []byte) error
+}
+
+var srdDatasets = []srdDataset{
+	{"equipment", "srd_equipment.json", func(d *SRDData, raw []byte) (err error) {
+		d.Equipment, err = layerByName(d.Equipment, raw, func(e SRDEquipment) string { return e.Name })
+		return err
+	}},
+	{"weapons", "srd_weapons.json", func(d *SRDData, raw []byte) (err error) {
+		d.Weapons, err = layerByName(d.Weapons, raw, func(w SRDWeapon) string { return w.Name })
+		return err
+	}},
+	{"armor", "srd_armor.json", func(d *SRDData, raw []byte) (err error) {
+		d.Armor, err = layerByName(d.Armor, raw, func(a SRDArmor) string { return a.Name })
+		return err
+	}},
+	{"spells", "srd_spells.json", func(d *SRDData, raw []byte) (err error) {
+		d.Spells, err = layerByName(d.Spells, raw, func(s SRDSpell) string { return s.Name })
+		return err
+	}},
+	{"monsters", "srd_monsters.json", func(d *SRDData, raw []byte) (err error) {
+		d.Monsters, err = layerByName(d.Monsters, raw, func(m SRDMonster) string { return m.Name })
+		return err
+	}},
+	{"magic items", "srd_magic_items.json", func(d *SRDData, raw []byte) (err error) {
+		d.MagicItems, err = layerByName(d.MagicItems, raw, func(i SRDMagicItem) string { return i.Name })
+		return err
+	}},
+	{"OSE monsters", "srd_ose_monsters.json", func(d *SRDData, raw []byte) (err error) {
+		d.OSEMonsters, err = layerByName(d.OSEMonsters, raw, func(m OSEMonster) string { return m.Name })
+		return err
+	}},
+	{"OSE spells", "srd_ose_spells.json", func(d *SRDData, raw []byte) (err error) {
+		// Both classes have a "Light" and a "Dispel Magic"
+		d.OSESpells, err = layerByName(d.OSESpells, raw, func(s OSESpell) string { return s.Class + "/" + s.Name })
+		return err
+	}},
+	{"OSE classes", "srd_ose_classes.json", func(d *SRDData, raw []byte) (err error) {
+		d.OSEClasses, err = layerByName(d.OSEClasses, raw, func(c OSEClass) string { return c.Name })
+		return err
+	}},
+	{"conditions", "srd_conditions.json", func(d *SRDData, raw []byte) (err error) {
+		d.Conditions, err = layerByName(d.Conditions, raw, func(c SRDCondition) string { return c.Name })
+		return err
+	}},
+	{"races", "srd_races.json", func(d *SRDData, raw []byte) (err error) {
+		d.Races, err = layerByName(d.Races, raw, func(r SRDRace) string { return r.Name })
+		return err
+	}},
+	{"backgrounds", "srd_backgrounds.json", func(d *SRDData, raw []byte) (err error) {
+		d.Backgrounds, err = layerByName(d.Backgrounds, raw, func(b SRDBackground) string { return b.Name })
+		return err
+	}},
+	{"feats", "srd_feats.json", func(d *SRDData, raw []byte) (err error) {
+		d.Feats, err = layerByName(d.Feats, raw, func(f SRDFeat) string { return f.Name })
+		return err
+	}},
+	{"classes", "srd_classes.json", func(d *SRDData, raw []byte) (err error) {
+		d.Classes, err = layerByName(d.Classes, raw, func(c ClassDefinition) string { return c.Name })
+		return err
+	}},
+	{"multiclass slots", "srd_multiclass_slots.json", func(d *SRDData, raw []byte) (err error) {
+		d.MulticlassSlots, err = layerByName(d.MulticlassSlots, raw, func(s MulticlassSlots) string { return strconv.Itoa(s.Level) })
+		return err
+	}},
+	{"multiclassing", "srd_multiclassing.json", func(d *SRDData, raw []byte) (err error) {
+		d.MulticlassClasses, err = layerByName(d.MulticlassClasses, raw, func(c MulticlassClass) string { return c.Name })
+		return err
+	}},
+	{"advancement", "srd_advancement.json", func(d *SRDData, raw []byte) (err error) {
+		d.Advancement, err = layerByName(d.Advancement, raw, func(a AdvancementLevel) string { return strconv.Itoa(a.Level) })
+		return err
+	}},
+}
+
+// loadSRDData reads the embedded dataset, then layers the srd_*.json files
+// found in each override directory on top of it. Entries with the same name
+// replace the embedded ones and new names are added. A broken override file
+// is skipped and reported, leaving the data underneath it in place
+func loadSRDData(overrideDirs []string) (SRDData, error) {
+	srdData := SRDData{Sources: make(map[string]string)}
+
+	var errs []error
+	for _, dataset := range srdDatasets {
+		raw, err := embeddedSRD.ReadFile("srd/" + dataset.file)
+		if err != nil {
+			return srdData, fmt.Errorf("error reading embedded %s: %v", dataset.name, err)
+		}
+		if err := dataset.layer(&srdData, raw); err != nil {
+			return srdData, fmt.Errorf("error parsing embedded %s: %v", dataset.name, err)
+		}
+		sources := []string{"embedded"}
+
+		for _, dir := range overrideDirs {
+			file := filepath.Join(dir, dataset.file)
+			raw, err := os.ReadFile(file)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err == nil {
+				err = dataset.layer(&srdData, raw)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error loading %s: %v", file, err))
+				sources = append(sources, file+" (failed)")
+				continue
+			}
+			sources = append(sources, file)
+		}
+		srdData.Sources[dataset.name] = strings.Join(sources, " + ")
+	}
+	return srdData, errors.Join(errs...)
+}
+
+// layerByName unmarshals a list of entries and merges it into base by name
+func layerByName[T any](base []T, raw []byte, name func(T) string) ([]T, error) {
+	var entries []T
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return base, err
+	}
+
+	index := make(map[string]int)
+	for i, entry := range base {
+		index[strings.ToLower(name(entry))] = i
+	}
+	for _, entry := range entries {
+		key := strings.ToLower(name(entry))
+		if i, ok := index[key]; ok {
+			base[i] = entry
+			continue
+		}
+		index[key] = len(base)
+		base = append(base, entry)
+	}
+	return base, nil
+}
+
+// srdOverrideDirs are the SRD_DATA_PATH directories. Files "app import"
+// writes elsewhere are only picked up once their directory is listed there
+func srdOverrideDirs() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv(srdDataPathEnv)) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// sourceStatus summarises where each dataset came from, grouping the
+// datasets that share a source: "SRD data: weapons, armor from embedded; ..."
+func (d SRDData) sourceStatus() string {
+	var order []string
+	groups := make(map[string][]string)
+	for _, dataset := range srdDatasets {
+		source, ok := d.Sources[dataset.name]
+		if !ok {
+			source = "not loaded"
+		}
+		if _, seen := groups[source]; !seen {
+			order = append(order, source)
+		}
+		groups[source] = append(groups[source], dataset.name)
+	}
+
+	var parts []string
+	for _, source := range order {
+		parts = append(parts, fmt.Sprintf("%s from %s", strings.Join(groups[source], ", "), source))
+	}
+	return "SRD data: " + strings.Join(parts, "; ")
+}
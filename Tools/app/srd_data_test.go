@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLayerByName(t *testing.T) {
+	base := []SRDWeapon{{Name: "Dagger", Cost: "2 gp"}, {Name: "Club", Cost: "1 sp"}}
+	tests := []struct {
+		name  string
+		raw   string
+		want  []string // "Name Cost"
+		error bool
+	}{
+		{"replace and add", `[{"name": "dagger", "cost": "5 gp"}, {"name": "Laser", "cost": "1 pp"}]`, []string{"dagger 5 gp", "Club 1 sp", "Laser 1 pp"}, false},
+		{"empty list", `[]`, []string{"Dagger 2 gp", "Club 1 sp"}, false},
+		{"broken", `{"name":`, []string{"Dagger 2 gp", "Club 1 sp"}, true},
+	}
+	for _, tt := range tests {
+		layered, err := layerByName(append([]SRDWeapon(nil), base...), []byte(tt.raw), func(w SRDWeapon) string { return w.Name })
+		if (err != nil) != tt.error {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.error)
+		}
+		var got []string
+		for _, w := range layered {
+			got = append(got, w.Name+" "+w.Cost)
+		}
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadSRDDataOverrides(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"srd_weapons.json": `[{"name": "Dagger", "cost": "99 gp"}]`,
+		"srd_armor.json":   `not json`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := loadSRDData([]string{dir})
+	if err == nil || !strings.Contains(err.Error(), "srd_armor.json") {
+		t.Errorf("error = %v, want the broken armor file reported", err)
+	}
+	if len(data.Armor) == 0 {
+		t.Error("the embedded armor was dropped along with the broken override")
+	}
+	for _, w := range data.Weapons {
+		if w.Name == "Dagger" && w.Cost != "99 gp" {
+			t.Errorf("Dagger costs %s, want the override's 99 gp", w.Cost)
+		}
+	}
+
+	tests := []struct {
+		dataset string
+		source  string
+	}{
+		{"weapons", "embedded + " + filepath.Join(dir, "srd_weapons.json")},
+		{"armor", "embedded + " + filepath.Join(dir, "srd_armor.json") + " (failed)"},
+		{"spells", "embedded"},
+	}
+	for _, tt := range tests {
+		if got := data.Sources[tt.dataset]; got != tt.source {
+			t.Errorf("%s: source %q, want %q", tt.dataset, got, tt.source)
+		}
+	}
+}
+
+func TestSRDOverrideDirs(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	tests := []struct {
+		env  string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a" + sep + sep + "b", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Setenv(srdDataPathEnv, tt.env)
+		got := srdOverrideDirs()
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || len(got) != len(tt.want) {
+			t.Errorf("%s=%q: got %q, want %q", srdDataPathEnv, tt.env, got, tt.want)
+		}
+	}
+}
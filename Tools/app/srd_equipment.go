@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 5e adventuring gear, tools and equipment packs from Rules/DND.SRD.Wiki/Equipment
+
+// equipmentPackPattern matches the "***Burglar's Pack (16 gp)***" features
+var equipmentPackPattern = regexp.MustCompile(`^(.+ Pack) \((\d+ gp)\)$`)
+
+// equipmentSlots are the gear table rows that can be worn
+var equipmentSlots = map[string]string{
+	"backpack":            "back",
+	"quiver":              "back",
+	"clothes, common":     "body",
+	"clothes, costume":    "body",
+	"clothes, fine":       "body",
+	"clothes, traveler's": "body",
+	"robes":               "body",
+	"signet ring":         "ring",
+	"pouch":               "belt",
+	"component pouch":     "belt",
+}
+
+// importEquipment reads the Adventuring Gear and Tools tables and the
+// equipment packs described in Gear.md
+func importEquipment(dir string) ([]SRDEquipment, []ImportIssue, error) {
+	var equipment []SRDEquipment
+	var issues []ImportIssue
+
+	for _, source := range []struct{ file, caption, category string }{
+		{"Gear.md", "Adventuring Gear", "adventuring gear"},
+		{"Tools.md", "Tools", "tools"},
+	} {
+		file := filepath.Join(dir, source.file)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading equipment: %v", err)
+		}
+		rows, err := wikiTable(string(data), source.caption)
+		if err != nil {
+			return nil, nil, err
+		}
+		features := wikiFeatures(string(data))
+
+		group := ""
+		for _, row := range rows {
+			if len(row) < 3 {
+				issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("short row %q", strings.Join(row, " | "))})
+				continue
+			}
+
+			// Group rows such as "*Arcane focus*" head the "~ Crystal" rows below them
+			if strings.HasPrefix(row[0], "*") && row[1] == "" {
+				group = strings.Trim(row[0], "*")
+				continue
+			}
+			name := row[0]
+			if strings.HasPrefix(name, "~") {
+				name = strings.TrimSpace(strings.TrimPrefix(name, "~"))
+			} else {
+				group = ""
+			}
+			if row[1] == "*" {
+				continue // "Vehicles (land or water)" points at another page
+			}
+
+			item := SRDEquipment{
+				Name:        name,
+				Cost:        row[1],
+				Weight:      dashToEmpty(row[2]),
+				Category:    source.category,
+				Description: equipmentDescription(features, name, group),
+				Slot:        equipmentSlots[strings.ToLower(name)],
+			}
+			if group != "" {
+				item.Category = strings.ToLower(group)
+			}
+			item.Equippable = item.Slot != ""
+			equipment = append(equipment, item)
+		}
+
+		for _, paragraph := range wikiParagraphs(string(data)) {
+			match := featurePattern.FindStringSubmatch(paragraph)
+			if match == nil {
+				continue
+			}
+			if pack := equipmentPackPattern.FindStringSubmatch(strings.TrimSpace(match[1])); pack != nil {
+				equipment = append(equipment, SRDEquipment{
+					Name:        pack[1],
+					Cost:        pack[2],
+					Category:    "equipment pack",
+					Description: strings.TrimSpace(match[2]),
+				})
+			}
+		}
+	}
+	return equipment, issues, nil
+}
+
+// equipmentDescription finds the feature paragraph for a table row. The rows
+// are more specific than the features ("Rope, hempen (50 feet)" is described
+// under "Rope", "~ Crystal" under "Arcane Focus")
+func equipmentDescription(features map[string]string, name, group string) string {
+	name = strings.ToLower(trailingParenPattern.ReplaceAllString(name, ""))
+	candidates := []string{name}
+	if comma := strings.Index(name, ","); comma > 0 {
+		candidates = append(candidates, name[:comma])
+	}
+	if group != "" {
+		candidates = append(candidates, strings.ToLower(group))
+	}
+	for _, candidate := range candidates {
+		if description, ok := features[candidate]; ok {
+			return description
+		}
+	}
+	return ""
+}
+
+// itemFromEquipment copies a catalog entry into the inventory
+func itemFromEquipment(e SRDEquipment) Item {
+	return Item{
+		Name:        e.Name,
+		Description: e.Description,
+		Quantity:    1,
+		Weight:      e.Weight,
+		Cost:        e.Cost,
+		Slot:        e.Slot,
+	}
+}
+
+// equipmentDir is the 5e equipment folder under the Rules folder
+func equipmentDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Equipment")
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Magic items from the per-item files in Rules/DND.SRD.Wiki/Treasure
+
+type SRDMagicItem struct {
+	Name                  string `json:"name"`
+	Type                  string `json:"type"`    // Weapon, Armor, Ring, Wondrous item...
+	Subtype               string `json:"subtype"` // "any sword", "plate"
+	Rarity                string `json:"rarity"`
+	Attunement            bool   `json:"attunement"`
+	AttunementRestriction string `json:"attunement_restriction"` // "a spellcaster", "a cleric, druid, or paladin"
+	Bonus                 int    `json:"bonus"`                  // +1/+2/+3 variants
+	Charges               int    `json:"charges"`
+	Recharge              string `json:"recharge"` // "1d6+1 daily at dawn"
+	Description           string `json:"description"`
+	VariantOf             string `json:"variant_of"` // family name for expanded variants
+}
+
+var (
+	magicItemTypePattern     = regexp.MustCompile(`^\*([^,(]+?)(?: \(([^)]*)\))?, (.+)\*$`)
+	magicItemAttunePattern   = regexp.MustCompile(`\s*\(requires attunement(?: by ([^)]*)| ([^)]*))?\)`)
+	magicItemRarityPattern   = regexp.MustCompile(`\b(very rare|uncommon|common|rare|legendary|artifact)\b(?: \(([^)]*)\))?`)
+	magicItemChargesPattern  = regexp.MustCompile(`(?:has|have|starts with|of its) (\d+) charges`)
+	magicItemRechargePattern = regexp.MustCompile(`regains ([^ ]+) expended charges? ([^.]*)`)
+	magicItemBonusPattern    = regexp.MustCompile(`^\+(\d)$`)
+	magicItemFamilyPattern   = regexp.MustCompile(`, \+1, \+2, or \+3$`)
+	magicItemSectionPattern  = regexp.MustCompile(`^(.+) \((?i)(very rare|uncommon|common|rare|legendary)\)$`)
+)
+
+// importMagicItems parses every item file in dir. Families whose rarity
+// depends on the variant ("uncommon (+1), rare (+2), or very rare (+3)",
+// "rarity varies") are expanded into one entry per variant
+func importMagicItems(dir string) ([]SRDMagicItem, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing magic items: %v", err)
+	}
+
+	var items []SRDMagicItem
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		parsed, err := parseMagicItem(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		items = append(items, parsed...)
+	}
+	return items, issues, nil
+}
+
+// parseMagicItem reads a "### Name", "*Ring, rare (requires attunement)*" item
+func parseMagicItem(text string) ([]SRDMagicItem, error) {
+	var item SRDMagicItem
+
+	paragraphs := wikiParagraphs(text)
+	if len(paragraphs) < 2 || !strings.HasPrefix(paragraphs[0], "#") {
+		return nil, fmt.Errorf("missing item heading")
+	}
+	item.Name = headingText(paragraphs[0])
+
+	match := magicItemTypePattern.FindStringSubmatch(paragraphs[1])
+	if match == nil {
+		return nil, fmt.Errorf("unrecognised type/rarity line %q", paragraphs[1])
+	}
+	item.Type = match[1]
+	item.Subtype = match[2]
+	rarity := match[3]
+
+	if attune := magicItemAttunePattern.FindStringSubmatch(rarity); attune != nil {
+		item.Attunement = true
+		item.AttunementRestriction = attune[1] + attune[2]
+		rarity = magicItemAttunePattern.ReplaceAllString(rarity, "")
+	}
+
+	item.describe(paragraphs[2:])
+
+	// "rare (silver or brass), very rare (bronze), or legendary (iron)"
+	variants := magicItemRarityPattern.FindAllStringSubmatch(rarity, -1)
+	labelled := len(variants) > 1
+	for _, variant := range variants {
+		labelled = labelled && variant[2] != ""
+	}
+	if !labelled {
+		item.Rarity = strings.TrimPrefix(strings.TrimSpace(rarity), "rarity ")
+		switch {
+		case strings.HasSuffix(item.Rarity, "varies"), strings.HasPrefix(item.Rarity, "by "):
+			// "rarity varies", "rarity by figurine": a Rarity table or the
+			// "***Variant (Rarity)***." sections give each variant's
+			if items := tableVariants(item, text); items != nil {
+				return items, nil
+			}
+			if items := sectionVariants(item, paragraphs[2:], ""); items != nil {
+				return items, nil
+			}
+			return nil, fmt.Errorf("no variants for rarity %q", item.Rarity)
+		case len(variants) > 1:
+			// "very rare or legendary": the item has the first rarity, its
+			// "***Variant***." sections the last
+			return sectionVariants(item, paragraphs[2:], variants[len(variants)-1][1]), nil
+		}
+		return []SRDMagicItem{item}, nil
+	}
+
+	family := magicItemFamilyPattern.ReplaceAllString(item.Name, "")
+	var items []SRDMagicItem
+	for _, variant := range variants {
+		expanded := item
+		expanded.Rarity = variant[1]
+		expanded.VariantOf = item.Name
+		if bonus := magicItemBonusPattern.FindStringSubmatch(variant[2]); bonus != nil {
+			expanded.Bonus, _ = strconv.Atoi(bonus[1])
+			expanded.Name = fmt.Sprintf("%s, %s", family, variant[2])
+		} else {
+			expanded.Name = fmt.Sprintf("%s (%s)", family, variant[2])
+		}
+		items = append(items, expanded)
+	}
+	return items, nil
+}
+
+// describe sets the description and reads the charges from it
+func (item *SRDMagicItem) describe(paragraphs []string) {
+	item.Description = strings.Join(paragraphs, "\n\n")
+	item.Charges, item.Recharge = 0, ""
+	if charges := magicItemChargesPattern.FindStringSubmatch(item.Description); charges != nil {
+		item.Charges, _ = strconv.Atoi(charges[1])
+	}
+	if recharge := magicItemRechargePattern.FindStringSubmatch(item.Description); recharge != nil {
+		item.Recharge = recharge[1] + " " + strings.TrimSpace(recharge[2])
+	}
+}
+
+// tableVariants expands an item by the first table with a Rarity column,
+// one variant a row named by its first cell. "Stone/frost giant" rows make
+// two variants
+func tableVariants(item SRDMagicItem, text string) []SRDMagicItem {
+	for _, line := range strings.Split(text, "\n") {
+		caption, ok := strings.CutPrefix(strings.TrimSpace(line), "**Table- ")
+		if !ok {
+			continue
+		}
+		header, rows, err := wikiTableHeader(text, strings.TrimSuffix(caption, "**"))
+		if err != nil {
+			continue
+		}
+		column := -1
+		for i, cell := range header {
+			if strings.EqualFold(cell, "Rarity") {
+				column = i
+			}
+		}
+		if column < 0 {
+			continue
+		}
+
+		var items []SRDMagicItem
+		for _, row := range rows {
+			if column >= len(row) {
+				continue
+			}
+			for _, label := range splitAlternatives(row[0]) {
+				variant := item
+				variant.Name = variantName(item.Name, label)
+				variant.Rarity = strings.ToLower(row[column])
+				variant.VariantOf = item.Name
+				items = append(items, variant)
+			}
+		}
+		return items
+	}
+	return nil
+}
+
+// splitAlternatives splits "Stone/frost giant" into "Stone giant" and
+// "frost giant"
+func splitAlternatives(label string) []string {
+	parts := strings.Split(label, "/")
+	last := strings.Fields(parts[len(parts)-1])
+	if len(parts) == 1 || len(last) < 2 {
+		return parts
+	}
+	suffix := strings.Join(last[1:], " ")
+	for i := range parts[:len(parts)-1] {
+		parts[i] = strings.TrimSpace(parts[i]) + " " + suffix
+	}
+	return parts
+}
+
+// variantName names a table variant. A label ending in a word of the family
+// name takes that word's place: "Greater healing" makes "Potion of Greater
+// Healing", "Hill giant" makes "Belt of Hill Giant Strength". Other labels
+// go in brackets: "Spell Scroll (1st)"
+func variantName(family, label string) string {
+	words := strings.Fields(label)
+	for i := range words {
+		words[i] = capitalize(words[i])
+	}
+	last := words[len(words)-1]
+
+	name := strings.Fields(family)
+	for i, word := range name {
+		if strings.EqualFold(word, last) {
+			return strings.Join(append(append(name[:i:i], words...), name[i+1:]...), " ")
+		}
+	}
+	return fmt.Sprintf("%s (%s)", family, label)
+}
+
+// sectionVariants expands an item by its "***Variant (Rarity)***." sections,
+// as the ioun stones and figurines are written. The paragraphs before the
+// first section describe every variant. Sections named for the item
+// ("***Crystal Ball of Telepathy***.") keep their name and take the given
+// rarity, alongside the item itself
+func sectionVariants(item SRDMagicItem, paragraphs []string, rarity string) []SRDMagicItem {
+	var intro []string
+	var items []SRDMagicItem
+	var section []string
+	flush := func() {
+		if len(items) > 0 {
+			last := &items[len(items)-1]
+			last.describe(append(intro[:len(intro):len(intro)], section...))
+		}
+	}
+	for _, paragraph := range paragraphs {
+		match := featurePattern.FindStringSubmatch(paragraph)
+		if match == nil {
+			if len(items) == 0 {
+				intro = append(intro, paragraph)
+			} else {
+				section = append(section, paragraph)
+			}
+			continue
+		}
+		flush()
+		section = []string{paragraph}
+
+		variant := item
+		variant.VariantOf = item.Name
+		variant.Rarity = rarity
+		name := match[1]
+		if label := magicItemSectionPattern.FindStringSubmatch(name); label != nil {
+			name, variant.Rarity = label[1], strings.ToLower(label[2])
+		}
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(item.Name)) {
+			variant.Name = name
+		} else {
+			variant.Name = fmt.Sprintf("%s (%s)", item.Name, name)
+		}
+		items = append(items, variant)
+	}
+	flush()
+	if len(items) == 0 {
+		return nil
+	}
+
+	if rarity == "" {
+		return items
+	}
+	// The item itself is the first rarity
+	base := item
+	base.VariantOf = item.Name
+	base.Rarity = magicItemRarityPattern.FindString(item.Rarity)
+	base.describe(intro)
+	return append([]SRDMagicItem{base}, items...)
+}
+
+// slot guesses where a magic item is worn from its type and name
+func (item SRDMagicItem) slot() string {
+	if item.Type == "Ring" {
+		return "ring"
+	}
+	if item.Type == "Armor" && item.Subtype != "shield" {
+		return "body"
+	}
+
+	name := strings.ToLower(item.Name)
+	slots := []struct {
+		slot  string
+		words []string
+	}{
+		{"head", []string{"helm", "hat", "headband", "circlet", "cap", "goggles", "eyes"}},
+		{"neck", []string{"amulet", "necklace", "periapt", "medallion", "scarab", "brooch"}},
+		{"hands", []string{"gloves", "gauntlets", "bracers"}},
+		{"feet", []string{"boots", "slippers"}},
+		{"body", []string{"cloak", "robe", "mantle", "belt"}},
+	}
+	for _, s := range slots {
+		for _, word := range s.words {
+			if strings.Contains(name, word) {
+				return s.slot
+			}
+		}
+	}
+	return ""
+}
+
+// magicItem finds a catalog magic item by name
+func (d SRDData) magicItem(name string) (SRDMagicItem, bool) {
+	for _, item := range d.MagicItems {
+		if strings.EqualFold(item.Name, strings.TrimSpace(name)) {
+			return item, true
+		}
+	}
+	return SRDMagicItem{}, false
+}
+
+// itemFromMagicItem copies a catalog magic item into the inventory
+func itemFromMagicItem(item SRDMagicItem) Item {
+	return Item{
+		Name:        item.Name,
+		Description: item.Description,
+		Quantity:    1,
+		Slot:        item.slot(),
+	}
+}
+
+// openMagicItemPicker lets the user add any magic item from the SRD catalog
+func (m *Model) openMagicItemPicker() {
+	if len(m.srdData.MagicItems) == 0 {
+		m.message = "No SRD magic items loaded"
+		return
+	}
+
+	var options []string
+	for _, item := range m.srdData.MagicItems {
+		option := fmt.Sprintf("%s (%s, %s)", item.Name, item.Type, item.Rarity)
+		if item.Attunement {
+			option += " [attunement]"
+		}
+		options = append(options, option)
+	}
+	m.picker = &catalogPicker{
+		title:   "Add Magic Item",
+		options: options,
+		choose: func(m *Model, index int) {
+			item := itemFromMagicItem(m.srdData.MagicItems[index])
+			m.equipment = append(m.equipment, item)
+			m.message = fmt.Sprintf("Added %s to equipment", item.Name)
+		},
+	}
+}
+
+// magicItemsDir is where the magic item files live under the Rules folder
+func magicItemsDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Treasure")
+}
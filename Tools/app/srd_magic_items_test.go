@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseMagicItem(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		items []string // "Name: rarity, bonus N" for each entry
+		err   bool
+	}{
+		{
+			name:  "single item",
+			text:  "### Ring of Warmth\n\n*Ring, uncommon (requires attunement)*\n\nYou have resistance to cold damage.",
+			items: []string{"Ring of Warmth: uncommon, bonus 0"},
+		},
+		{
+			name:  "+1, +2, or +3 family",
+			text:  "### Weapon, +1, +2, or +3\n\n*Weapon (any), uncommon (+1), rare (+2), or very rare (+3)*\n\nYou have a bonus to attack and damage rolls.",
+			items: []string{"Weapon, +1: uncommon, bonus 1", "Weapon, +2: rare, bonus 2", "Weapon, +3: very rare, bonus 3"},
+		},
+		{
+			name:  "labelled variants",
+			text:  "### Horn of Valhalla\n\n*Wondrous item, rare (silver or brass), very rare (bronze), or legendary (iron)*\n\nYou can use an action to blow this horn.",
+			items: []string{"Horn of Valhalla (silver or brass): rare, bonus 0", "Horn of Valhalla (bronze): very rare, bonus 0", "Horn of Valhalla (iron): legendary, bonus 0"},
+		},
+		{
+			name:  "rarity table",
+			text:  "### Belt of Giant Strength\n\n*Wondrous item, rarity varies (requires attunement)*\n\nYour Strength score changes.\n\n**Table- Belt of Strength**\n\n| Type | Strength | Rarity |\n|---|---|---|\n| Hill giant | 21 | Rare |\n| Stone/frost giant | 23 | Very rare |\n| | | |",
+			items: []string{"Belt of Hill Giant Strength: rare, bonus 0", "Belt of Stone Giant Strength: very rare, bonus 0", "Belt of Frost Giant Strength: very rare, bonus 0"},
+		},
+		{
+			name:  "rarity table without a shared word",
+			text:  "### Spell Scroll\n\n*Scroll, varies*\n\nA scroll.\n\n**Table- Spell Scroll**\n\n| Spell Level | Rarity |\n|---|---|\n| Cantrip | Common |\n| 9th | Legendary |",
+			items: []string{"Spell Scroll (Cantrip): common, bonus 0", "Spell Scroll (9th): legendary, bonus 0"},
+		},
+		{
+			name:  "rarity by section",
+			text:  "### Figurine of Wondrous Power\n\n*Wondrous item, rarity by figurine*\n\nA statuette.\n\n***Ebony Fly (Rare)***. A fly.\n\n#### Giant Fly\n\n**Armor Class** 11\n\n***Silver Raven (Uncommon)***. A raven.",
+			items: []string{"Figurine of Wondrous Power (Ebony Fly): rare, bonus 0", "Figurine of Wondrous Power (Silver Raven): uncommon, bonus 0"},
+		},
+		{
+			name:  "base item and named sections",
+			text:  "### Crystal Ball\n\n*Wondrous item, very rare or legendary (requires attunement)*\n\nYou can scry.\n\n***Crystal Ball of Telepathy***. You can talk.",
+			items: []string{"Crystal Ball: very rare, bonus 0", "Crystal Ball of Telepathy: legendary, bonus 0"},
+		},
+		{
+			name: "rarity varies without variants",
+			text: "### Mystery\n\n*Wondrous item, rarity varies*\n\nNo table.",
+			err:  true,
+		},
+		{
+			name: "missing heading",
+			text: "*Ring, rare*\n\nText",
+			err:  true,
+		},
+		{
+			name: "no type line",
+			text: "### Ring\n\nJust text",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		items, err := parseMagicItem(tt.text)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.err)
+			continue
+		}
+		var got []string
+		for _, item := range items {
+			got = append(got, fmt.Sprintf("%s: %s, bonus %d", item.Name, item.Rarity, item.Bonus))
+		}
+		if !reflect.DeepEqual(got, tt.items) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.items)
+		}
+	}
+}
+
+func TestParseMagicItemDetails(t *testing.T) {
+	text := "### Staff of Healing\n\n*Staff, rare (requires attunement by a bard, cleric, or druid)*\n\nThis staff has 10 charges. The staff regains 1d6+4 expended charges daily at dawn."
+	items, err := parseMagicItem(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := items[0]
+	item.Description = ""
+	want := SRDMagicItem{Name: "Staff of Healing", Type: "Staff", Rarity: "rare", Attunement: true, AttunementRestriction: "a bard, cleric, or druid", Charges: 10, Recharge: "1d6+4 daily at dawn"}
+	if item != want {
+		t.Errorf("got %+v, want %+v", item, want)
+	}
+}
+
+func TestParseMagicItemSections(t *testing.T) {
+	text := "### Ioun Stone\n\n*Wondrous item, rarity varies (requires attunement)*\n\nA stone orbits your head.\n\n***Protection (Rare)***. You gain a +1 bonus to AC.\n\n***Reserve (Rare)***. It stores spells.\n\nIt has 3 charges."
+	items, err := parseMagicItem(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SRDMagicItem{
+		{Name: "Ioun Stone (Protection)", Type: "Wondrous item", Rarity: "rare", Attunement: true, VariantOf: "Ioun Stone",
+			Description: "A stone orbits your head.\n\n***Protection (Rare)***. You gain a +1 bonus to AC."},
+		{Name: "Ioun Stone (Reserve)", Type: "Wondrous item", Rarity: "rare", Attunement: true, VariantOf: "Ioun Stone", Charges: 3,
+			Description: "A stone orbits your head.\n\n***Reserve (Rare)***. It stores spells.\n\nIt has 3 charges."},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got %+v, want %+v", items, want)
+	}
+}
+
+func TestMagicItemFamilies(t *testing.T) {
+	data, err := loadSRDData(nil)
+	if err != nil {
+		t.Fatalf("loadSRDData: %v", err)
+	}
+	tests := []struct {
+		family   string
+		variants int
+	}{
+		{"Potion of Healing", 4},
+		{"Belt of Giant Strength", 6},
+		{"Potion of Giant Strength", 6},
+		{"Ioun Stone", 14},
+		{"Figurine of Wondrous Power", 9},
+		{"Crystal Ball", 4},
+		{"Spell Scroll", 10},
+	}
+	for _, tt := range tests {
+		count := 0
+		for _, item := range data.MagicItems {
+			if item.VariantOf == tt.family {
+				count++
+			}
+			if item.Name == tt.family && item.VariantOf == "" {
+				t.Errorf("%s: unexpanded family entry", tt.family)
+			}
+		}
+		if count != tt.variants {
+			t.Errorf("%s: %d variants, want %d", tt.family, count, tt.variants)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 5e monster stat blocks from Rules/DND.SRD.Wiki/Monsters
+
+type SRDMonster struct {
+	Name                  string           `json:"name"`
+	Size                  string           `json:"size"`
+	Type                  string           `json:"type"`
+	Subtype               string           `json:"subtype"` // goblinoid, devil, shapechanger...
+	Alignment             string           `json:"alignment"`
+	ArmorClass            int              `json:"armor_class"`
+	ArmorSource           string           `json:"armor_source"` // natural armor, leather armor, shield...
+	ArmorNotes            string           `json:"armor_notes"`  // alternate ACs, e.g. "11 while prone"
+	HitPoints             int              `json:"hit_points"`
+	HitDice               string           `json:"hit_dice"`
+	Speeds                map[string]int   `json:"speeds"` // walk, burrow, climb, fly, swim in feet
+	Hover                 bool             `json:"hover"`
+	SpeedNotes            string           `json:"speed_notes"`
+	Abilities             Abilities        `json:"abilities"`
+	SavingThrows          map[string]int   `json:"saving_throws"` // "Dex": 6
+	Skills                map[string]int   `json:"skills"`        // "Perception": 13
+	DamageVulnerabilities string           `json:"damage_vulnerabilities"`
+	DamageResistances     string           `json:"damage_resistances"`
+	DamageImmunities      string           `json:"damage_immunities"`
+	ConditionImmunities   string           `json:"condition_immunities"`
+	Senses                map[string]int   `json:"senses"` // "darkvision": 60
+	PassivePerception     int              `json:"passive_perception"`
+	Languages             string           `json:"languages"`
+	Challenge             string           `json:"challenge"` // "1/4", "17"
+	XP                    int              `json:"xp"`
+	Traits                []MonsterFeature `json:"traits"`
+	Actions               []MonsterFeature `json:"actions"`
+	Reactions             []MonsterFeature `json:"reactions"`
+	LegendaryIntro        string           `json:"legendary_intro"`
+	LegendaryActions      []MonsterFeature `json:"legendary_actions"`
+	Description           string           `json:"description"`
+}
+
+// MonsterFeature is a named trait or action, e.g. "Fire Breath (Recharge 5-6)"
+type MonsterFeature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var (
+	monsterTypePattern   = regexp.MustCompile(`^\*?(Tiny|Small|Medium|Large|Huge|Gargantuan) ([^,(]+?)(?: \(([^)]*)\))?, ([^*]+)\*?$`)
+	monsterNumberPattern = regexp.MustCompile(`^(\d+)(?: \(([^)]*)\))?(.*)$`)
+	monsterSpeedPattern  = regexp.MustCompile(`^(?:(\w+) )?(\d+) ft\.?(?: \((.*)\))?$`)
+	monsterSensePattern  = regexp.MustCompile(`^(\w+) (\d+) ft\.`)
+	monsterBonusPattern  = regexp.MustCompile(`^(.+?) ([+-]\d+)$`)
+	monsterCRPattern     = regexp.MustCompile(`^([\d/]+) \(([\d,]+) XP\)`)
+)
+
+// importMonsters parses every stat block in dir, reporting files that
+// don't contain one (templates, NPC customisation notes)
+func importMonsters(dir string) ([]SRDMonster, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing monsters: %v", err)
+	}
+
+	var monsters []SRDMonster
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		monster, err := parseMonster(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		monsters = append(monsters, monster)
+	}
+	return monsters, issues, nil
+}
+
+// parseMonster reads a single stat block
+func parseMonster(text string) (SRDMonster, error) {
+	monster := SRDMonster{
+		Speeds:       make(map[string]int),
+		SavingThrows: make(map[string]int),
+		Skills:       make(map[string]int),
+		Senses:       make(map[string]int),
+	}
+
+	paragraphs := wikiParagraphs(text)
+	if len(paragraphs) < 2 || !strings.HasPrefix(paragraphs[0], "#") {
+		return monster, fmt.Errorf("missing monster heading")
+	}
+	monster.Name = headingText(paragraphs[0])
+
+	match := monsterTypePattern.FindStringSubmatch(paragraphs[1])
+	if match == nil {
+		return monster, fmt.Errorf("no stat block (unrecognised size/type line %q)", firstLine(paragraphs[1]))
+	}
+	monster.Size = match[1]
+	monster.Type = match[2]
+	monster.Subtype = match[3]
+	monster.Alignment = match[4]
+
+	section := "traits"
+	var current *MonsterFeature
+	for _, paragraph := range paragraphs[2:] {
+		if strings.HasPrefix(paragraph, "######") {
+			section = strings.ToLower(headingText(paragraph))
+			current = nil
+			continue
+		}
+		if strings.HasPrefix(paragraph, "| STR") {
+			if err := parseMonsterAbilities(paragraph, &monster.Abilities); err != nil {
+				return monster, err
+			}
+			continue
+		}
+		if label, value, ok := boldField(paragraph); ok && current == nil && section == "traits" {
+			if parseMonsterStat(&monster, label, value) {
+				continue
+			}
+		}
+
+		if feature := featurePattern.FindStringSubmatch(paragraph); feature != nil {
+			list := monster.featureList(section)
+			if list == nil {
+				continue
+			}
+			*list = append(*list, MonsterFeature{Name: strings.TrimSpace(feature[1]), Description: strings.TrimSpace(feature[2])})
+			current = &(*list)[len(*list)-1]
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(paragraph, "**"):
+			// Lore blurbs such as "**Acolytes** are junior members of a clergy"
+			monster.Description = joinParagraphs(monster.Description, paragraph)
+		case section == "legendary actions" && current == nil:
+			monster.LegendaryIntro = joinParagraphs(monster.LegendaryIntro, paragraph)
+		case current != nil:
+			// Spell lists and follow-up paragraphs belong to the feature above them
+			current.Description = joinParagraphs(current.Description, paragraph)
+		default:
+			monster.Description = joinParagraphs(monster.Description, paragraph)
+		}
+	}
+
+	if monster.ArmorClass == 0 || monster.HitPoints == 0 || monster.Challenge == "" {
+		return monster, fmt.Errorf("incomplete stat block")
+	}
+	return monster, nil
+}
+
+// parseMonsterStat fills in one of the "**Armor Class** 15 (leather armor)"
+// lines, returning false for labels that aren't stat lines
+func parseMonsterStat(monster *SRDMonster, label, value string) bool {
+	switch label {
+	case "Armor Class":
+		if match := monsterNumberPattern.FindStringSubmatch(value); match != nil {
+			monster.ArmorClass, _ = strconv.Atoi(match[1])
+			monster.ArmorSource = match[2]
+			monster.ArmorNotes = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(match[3]), ","))
+		}
+	case "Hit Points":
+		if match := monsterNumberPattern.FindStringSubmatch(value); match != nil {
+			monster.HitPoints, _ = strconv.Atoi(match[1])
+			monster.HitDice = strings.ReplaceAll(match[2], " ", "")
+		}
+	case "Speed":
+		for _, part := range splitTopLevel(value) {
+			match := monsterSpeedPattern.FindStringSubmatch(strings.TrimSpace(part))
+			if match == nil {
+				monster.SpeedNotes = joinList(monster.SpeedNotes, part)
+				continue
+			}
+			mode := match[1]
+			if mode == "" {
+				mode = "walk"
+			}
+			monster.Speeds[mode], _ = strconv.Atoi(match[2])
+			if match[3] == "hover" {
+				monster.Hover = true
+			} else if match[3] != "" {
+				monster.SpeedNotes = joinList(monster.SpeedNotes, match[3])
+			}
+		}
+	case "Saving Throws":
+		parseMonsterBonuses(value, monster.SavingThrows)
+	case "Skills":
+		parseMonsterBonuses(value, monster.Skills)
+	case "Damage Vulnerabilities":
+		monster.DamageVulnerabilities = value
+	case "Damage Resistances", "Damage Resistance":
+		monster.DamageResistances = value
+	case "Damage Immunities":
+		monster.DamageImmunities = value
+	case "Condition Immunities":
+		monster.ConditionImmunities = value
+	case "Senses":
+		for _, sense := range splitTopLevel(value) {
+			sense = strings.TrimSpace(sense)
+			if strings.HasPrefix(sense, "passive Perception ") {
+				monster.PassivePerception, _ = strconv.Atoi(strings.TrimPrefix(sense, "passive Perception "))
+			} else if match := monsterSensePattern.FindStringSubmatch(sense); match != nil {
+				monster.Senses[match[1]], _ = strconv.Atoi(match[2])
+			}
+		}
+	case "Languages":
+		monster.Languages = value
+	case "Challenge":
+		if match := monsterCRPattern.FindStringSubmatch(value); match != nil {
+			monster.Challenge = match[1]
+			monster.XP, _ = strconv.Atoi(strings.ReplaceAll(match[2], ",", ""))
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// parseMonsterAbilities reads the STR..CHA table row, e.g. "| 8 (-1) | 14 (+2) | ..."
+func parseMonsterAbilities(table string, abilities *Abilities) error {
+	lines := strings.Split(table, "\n")
+	if len(lines) < 3 {
+		return fmt.Errorf("ability table has no scores")
+	}
+
+	var scores []int
+	for _, cell := range strings.Split(strings.Trim(strings.TrimSpace(lines[2]), "|"), "|") {
+		fields := strings.Fields(cell)
+		if len(fields) == 0 {
+			continue
+		}
+		score, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return fmt.Errorf("bad ability score %q", strings.TrimSpace(cell))
+		}
+		scores = append(scores, score)
+	}
+	if len(scores) != 6 {
+		return fmt.Errorf("expected 6 ability scores, found %d", len(scores))
+	}
+
+	abilities.Strength = scores[0]
+	abilities.Dexterity = scores[1]
+	abilities.Constitution = scores[2]
+	abilities.Intelligence = scores[3]
+	abilities.Wisdom = scores[4]
+	abilities.Charisma = scores[5]
+	return nil
+}
+
+// parseMonsterBonuses reads "Dex +6, Con +13" style lists
+func parseMonsterBonuses(value string, bonuses map[string]int) {
+	for _, part := range strings.Split(value, ",") {
+		if match := monsterBonusPattern.FindStringSubmatch(strings.TrimSpace(part)); match != nil {
+			bonuses[match[1]], _ = strconv.Atoi(match[2])
+		}
+	}
+}
+
+func (m *SRDMonster) featureList(section string) *[]MonsterFeature {
+	switch section {
+	case "traits":
+		return &m.Traits
+	case "actions":
+		return &m.Actions
+	case "reactions":
+		return &m.Reactions
+	case "legendary actions":
+		return &m.LegendaryActions
+	}
+	return nil
+}
+
+// splitTopLevel splits a comma separated list, leaving commas inside
+// parentheses alone: "30 ft. (40 ft., climb 30 ft. in bear form)"
+func splitTopLevel(value string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range value {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(value[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if rest := strings.TrimSpace(value[start:]); rest != "" {
+		parts = append(parts, rest)
+	}
+	return parts
+}
+
+func joinParagraphs(text, paragraph string) string {
+	if text == "" {
+		return paragraph
+	}
+	return text + "\n\n" + paragraph
+}
+
+func joinList(text, item string) string {
+	if text == "" {
+		return item
+	}
+	return text + ", " + item
+}
+
+func firstLine(text string) string {
+	if i := strings.Index(text, "\n"); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
+
+// monstersDir is where the 5e monster files live under the Rules folder
+func monstersDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Monsters")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const goblinStatBlock = `## Goblin
+
+*Small humanoid (goblinoid), neutral evil*
+
+**Armor Class** 15 (leather armor, shield)
+
+**Hit Points** 7 (2d6)
+
+**Speed** 30 ft.
+
+| STR      | DEX      | CON      | INT      | WIS      | CHA      |
+|:--------:|:--------:|:--------:|:--------:|:--------:|:--------:|
+| 8 (-1)   | 14 (+2)  | 10 (+0)  | 10 (+0)  | 8 (-1)   | 8 (-1)   |
+
+**Skills** Stealth +6
+
+**Senses** darkvision 60 ft., passive Perception 9
+
+**Languages** Common, Goblin
+
+**Challenge** 1/4 (50 XP)
+
+***Nimble Escape***. The goblin can take the Disengage or Hide action as a bonus action on each of its turns.
+
+###### Actions
+
+***Scimitar***. *Melee Weapon Attack:* +4 to hit, reach 5 ft., one target. *Hit:* 5 (1d6+2) slashing damage.
+
+***Shortbow***. *Ranged Weapon Attack:* +4 to hit, range 80/320 ft., one target. *Hit:* 5 (1d6+2) piercing damage.`
+
+const wraithlingStatBlock = `## Wraithling
+
+*Medium undead, chaotic evil*
+
+**Armor Class** 13, 11 while prone
+
+**Hit Points** 45 (6d8 + 18)
+
+**Speed** 0 ft., fly 60 ft. (hover)
+
+| STR     | DEX     | CON     | INT     | WIS     | CHA     |
+|---------|---------|---------|---------|---------|---------|
+| 6 (-2)  | 16 (+3) | 16 (+3) | 12 (+1) | 14 (+2) | 15 (+2) |
+
+**Saving Throws** Dex +5, Wis +4
+
+**Damage Immunities** necrotic, poison
+
+**Senses** darkvision 60 ft., passive Perception 12
+
+**Languages** the languages it knew in life
+
+**Challenge** 5 (1,800 XP)
+
+###### Actions
+
+***Life Drain***. *Melee Weapon Attack:* +6 to hit.
+
+###### Legendary Actions
+
+The wraithling can take 1 legendary action.
+
+***Move***. The wraithling flies half its speed.`
+
+func TestParseMonster(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    SRDMonster // the fields that aren't maps or slices
+		walk    int
+		fly     int
+		dex     int
+		bonus   int // the Stealth skill or Dex save
+		actions int
+		err     bool
+	}{
+		{
+			name:    "goblin",
+			text:    goblinStatBlock,
+			want:    SRDMonster{Name: "Goblin", Size: "Small", Type: "humanoid", Subtype: "goblinoid", Alignment: "neutral evil", ArmorClass: 15, ArmorSource: "leather armor, shield", HitPoints: 7, HitDice: "2d6", PassivePerception: 9, Languages: "Common, Goblin", Challenge: "1/4", XP: 50},
+			walk:    30,
+			dex:     14,
+			bonus:   6,
+			actions: 2,
+		},
+		{
+			name:    "hovering undead with legendary actions",
+			text:    wraithlingStatBlock,
+			want:    SRDMonster{Name: "Wraithling", Size: "Medium", Type: "undead", Alignment: "chaotic evil", ArmorClass: 13, ArmorNotes: "11 while prone", HitPoints: 45, HitDice: "6d8+18", Hover: true, DamageImmunities: "necrotic, poison", PassivePerception: 12, Languages: "the languages it knew in life", Challenge: "5", XP: 1800, LegendaryIntro: "The wraithling can take 1 legendary action."},
+			fly:     60,
+			dex:     16,
+			bonus:   5,
+			actions: 1,
+		},
+		{
+			name: "lore page",
+			text: "## Dragons\n\nDragons are ancient and proud.",
+			err:  true,
+		},
+		{
+			name: "no challenge",
+			text: "## Rat\n\n*Tiny beast, unaligned*\n\n**Armor Class** 10\n\n**Hit Points** 1 (1d4 - 1)",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseMonster(tt.text)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.err)
+			continue
+		}
+		if tt.err {
+			continue
+		}
+		scalars := SRDMonster{
+			Name: got.Name, Size: got.Size, Type: got.Type, Subtype: got.Subtype, Alignment: got.Alignment,
+			ArmorClass: got.ArmorClass, ArmorSource: got.ArmorSource, ArmorNotes: got.ArmorNotes,
+			HitPoints: got.HitPoints, HitDice: got.HitDice, Hover: got.Hover,
+			DamageImmunities: got.DamageImmunities, PassivePerception: got.PassivePerception,
+			Languages: got.Languages, Challenge: got.Challenge, XP: got.XP, LegendaryIntro: got.LegendaryIntro,
+		}
+		if !reflect.DeepEqual(scalars, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, scalars, tt.want)
+		}
+		if got.Speeds["walk"] != tt.walk || got.Speeds["fly"] != tt.fly {
+			t.Errorf("%s: speeds = %v, want walk %d fly %d", tt.name, got.Speeds, tt.walk, tt.fly)
+		}
+		if got.Abilities.Dexterity != tt.dex {
+			t.Errorf("%s: Dexterity = %d, want %d", tt.name, got.Abilities.Dexterity, tt.dex)
+		}
+		if got.Skills["Stealth"]+got.SavingThrows["Dex"] != tt.bonus {
+			t.Errorf("%s: skills %v, saves %v, want a bonus of %d", tt.name, got.Skills, got.SavingThrows, tt.bonus)
+		}
+		if len(got.Actions) != tt.actions {
+			t.Errorf("%s: %d actions, want %d", tt.name, len(got.Actions), tt.actions)
+		}
+	}
+}
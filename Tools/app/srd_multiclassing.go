@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Multiclassing rules from Rules/DND.SRD.Wiki/Characterizations/Multiclassing.md
+
+// MulticlassSlots is a row of the multiclass spellcaster table: the slots a
+// character with several spellcasting classes has at a combined caster level
+type MulticlassSlots struct {
+	Level      int   `json:"level"`
+	SpellSlots []int `json:"spell_slots"` // per spell level
+}
+
+// MulticlassClass is what a class asks of a character taking it as a new
+// class, and the part of its starting proficiencies they gain
+type MulticlassClass struct {
+	Name          string         `json:"name"`
+	Minimums      map[string]int `json:"minimums"`              // "Strength": 13
+	AnyMinimum    bool           `json:"any_minimum,omitempty"` // one of the minimums will do: "Strength 13 or Dexterity 13"
+	Proficiencies []string       `json:"proficiencies,omitempty"`
+	SkillChoices  int            `json:"skill_choices,omitempty"`
+	ClassSkills   bool           `json:"class_skills,omitempty"` // the skill comes from the class's skill list
+	Choices       []string       `json:"choices,omitempty"`      // other picks: "one musical instrument of your choice"
+}
+
+// "Strength 13"
+var multiclassMinimumPattern = regexp.MustCompile(`(\w+) (\d+)`)
+
+// importMulticlassClasses parses the prerequisites and proficiencies tables
+func importMulticlassClasses(file string) ([]MulticlassClass, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading multiclassing: %v", err)
+	}
+	prerequisites, err := wikiTable(string(data), "Multiclassing Prerequisites")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading multiclassing: %v", err)
+	}
+	proficiencies, err := wikiTable(string(data), "Multiclassing Proficiencies")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading multiclassing: %v", err)
+	}
+
+	var classes []MulticlassClass
+	var issues []ImportIssue
+	for _, row := range prerequisites {
+		if len(row) < 2 || row[0] == "" {
+			continue
+		}
+		class := MulticlassClass{Name: row[0], Minimums: make(map[string]int), AnyMinimum: strings.Contains(row[1], " or ")}
+		for _, match := range multiclassMinimumPattern.FindAllStringSubmatch(row[1], -1) {
+			class.Minimums[match[1]], _ = strconv.Atoi(match[2])
+		}
+		if len(class.Minimums) == 0 {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("%s: can't read prerequisite %q", row[0], row[1])})
+		}
+		classes = append(classes, class)
+	}
+	for _, row := range proficiencies {
+		if len(row) < 2 || row[0] == "" {
+			continue
+		}
+		i := -1
+		for j := range classes {
+			if classes[j].Name == row[0] {
+				i = j
+			}
+		}
+		if i < 0 {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("proficiencies for %s, which has no prerequisite", row[0])})
+			continue
+		}
+		if row[1] == "-" {
+			continue
+		}
+		for _, item := range strings.Split(row[1], ", ") {
+			switch {
+			case strings.HasPrefix(item, "one skill"):
+				classes[i].SkillChoices++
+				classes[i].ClassSkills = strings.Contains(item, "class's skill list")
+			case strings.Contains(item, "of your choice"):
+				classes[i].Choices = append(classes[i].Choices, item)
+			default:
+				classes[i].Proficiencies = append(classes[i].Proficiencies, item)
+			}
+		}
+	}
+	return classes, issues, nil
+}
+
+// importMulticlassSlots parses the multiclass spellcaster table
+func importMulticlassSlots(file string) ([]MulticlassSlots, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading multiclassing: %v", err)
+	}
+	rows, err := wikiTable(string(data), "Multiclass Spellcaster: Spell Slots per Spell Level")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading multiclassing: %v", err)
+	}
+
+	var levels []MulticlassSlots
+	var issues []ImportIssue
+	for _, row := range rows {
+		level, err := parseOrdinal(row[0])
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("bad caster level %q", row[0])})
+			continue
+		}
+		slots := MulticlassSlots{Level: level}
+		for _, cell := range row[1:] {
+			n, _ := strconv.Atoi(dashToEmpty(cell))
+			slots.SpellSlots = append(slots.SpellSlots, n)
+		}
+		levels = append(levels, slots)
+	}
+	return levels, issues, nil
+}
+
+// multiclassSlots returns the multiclass table's slots at a caster level
+func (d SRDData) multiclassSlots(casterLevel int) []int {
+	for _, row := range d.MulticlassSlots {
+		if row.Level == casterLevel {
+			return row.SpellSlots
+		}
+	}
+	return nil
+}
+
+// multiclassClass finds the multiclassing rules for a class
+func (d SRDData) multiclassClass(name string) (MulticlassClass, bool) {
+	for _, class := range d.MulticlassClasses {
+		if strings.EqualFold(class.Name, strings.TrimSpace(name)) {
+			return class, true
+		}
+	}
+	return MulticlassClass{}, false
+}
+
+// qualifies reports whether the scores meet the class's prerequisites
+func (c MulticlassClass) qualifies(a Abilities) bool {
+	met := 0
+	for ability, minimum := range c.Minimums {
+		if a.score(ability) >= minimum {
+			met++
+		}
+	}
+	if c.AnyMinimum {
+		return met > 0
+	}
+	return met == len(c.Minimums)
+}
+
+// prerequisite renders the minimums as the table does: "Dexterity 13 and Wisdom 13"
+func (c MulticlassClass) prerequisite() string {
+	var parts []string
+	for _, ability := range abilityNames {
+		if minimum, ok := c.Minimums[ability]; ok {
+			parts = append(parts, fmt.Sprintf("%s %d", ability, minimum))
+		}
+	}
+	if c.AnyMinimum {
+		return strings.Join(parts, " or ")
+	}
+	return strings.Join(parts, " and ")
+}
+
+func multiclassingFile(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Characterizations", "Multiclassing.md")
+}
@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestMulticlassQualifies(t *testing.T) {
+	fighter := MulticlassClass{Name: "Fighter", Minimums: map[string]int{"Strength": 13, "Dexterity": 13}, AnyMinimum: true}
+	monk := MulticlassClass{Name: "Monk", Minimums: map[string]int{"Dexterity": 13, "Wisdom": 13}}
+	tests := []struct {
+		class     MulticlassClass
+		abilities Abilities
+		want      bool
+	}{
+		{fighter, Abilities{Strength: 13}, true},
+		{fighter, Abilities{Dexterity: 13}, true},
+		{fighter, Abilities{Strength: 12, Dexterity: 12}, false},
+		{monk, Abilities{Dexterity: 13, Wisdom: 13}, true},
+		{monk, Abilities{Dexterity: 15, Wisdom: 12}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.class.qualifies(tt.abilities); got != tt.want {
+			t.Errorf("%s with %+v: qualifies = %v, want %v", tt.class.Name, tt.abilities, got, tt.want)
+		}
+	}
+
+	if got := fighter.prerequisite(); got != "Strength 13 or Dexterity 13" {
+		t.Errorf("fighter prerequisite = %q", got)
+	}
+	if got := monk.prerequisite(); got != "Dexterity 13 and Wisdom 13" {
+		t.Errorf("monk prerequisite = %q", got)
+	}
+}
+
+func TestMulticlassingData(t *testing.T) {
+	data, err := loadSRDData(nil)
+	if err != nil {
+		t.Fatalf("loadSRDData: %v", err)
+	}
+	tests := []struct {
+		name          string
+		skillChoices  int
+		classSkills   bool
+		proficiencies int
+	}{
+		{"Bard", 1, false, 1},
+		{"Ranger", 1, true, 5},
+		{"Wizard", 0, false, 0},
+	}
+	for _, tt := range tests {
+		class, ok := data.multiclassClass(tt.name)
+		if !ok {
+			t.Errorf("no multiclassing rules for %s", tt.name)
+			continue
+		}
+		if class.SkillChoices != tt.skillChoices || class.ClassSkills != tt.classSkills || len(class.Proficiencies) != tt.proficiencies {
+			t.Errorf("%s: %+v", tt.name, class)
+		}
+	}
+	if len(data.MulticlassSlots) != 20 {
+		t.Errorf("%d multiclass caster levels, want 20", len(data.MulticlassSlots))
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 5e races from Rules/DND.SRD.Wiki/Races. A race with subraces is imported
+// once per subrace, with the subrace's traits merged into the race's
+
+type SRDRace struct {
+	Name   string  `json:"name"` // "Hill Dwarf"
+	Race   string  `json:"race"` // "Dwarf"
+	Size   string  `json:"size"`
+	Speed  int     `json:"speed"` // feet
+	Traits []Trait `json:"traits"`
+	Grants
+}
+
+// Trait is a named racial trait or background feature
+type Trait struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var (
+	raceIncreasePattern    = regexp.MustCompile(`(\w+) score increases by (\d)`)
+	raceAllIncreasePattern = regexp.MustCompile(`ability scores each increase by (\d)`)
+	raceChoicePattern      = regexp.MustCompile(`(\w+) other ability scores of your choice increase by (\d)`)
+	raceSizePattern        = regexp.MustCompile(`(?i)your size is (\w+)`)
+	raceSpeedPattern       = regexp.MustCompile(`walking speed is (\d+) feet`)
+	raceLanguagesPattern   = regexp.MustCompile(`speak, read, and write ([^.]+)\.`)
+	raceSkillPattern       = regexp.MustCompile(`proficiency in the ([\w ]+?) skill`)
+	raceSkillChoicePattern = regexp.MustCompile(`proficiency in (\w+) skills of your choice`)
+	raceToolPattern        = regexp.MustCompile(`have proficiency with (?:the )?([^.]+)\.`)
+	raceToolChoicePattern  = regexp.MustCompile(`proficiency with the [^.:]+ of your choice: ([^.]+)\.`)
+	raceCantripPattern     = regexp.MustCompile(`know (\w+) cantrip of your choice from the (\w+) spell list`)
+	// "artisan's tools (tinker's tools)" names the tool in brackets
+	artisanToolsPattern = regexp.MustCompile(`^artisan's tools \((.+)\)$`)
+)
+
+// numberWords reads the small numbers the wiki spells out
+var numberWords = map[string]int{"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "ten": 10, "twenty": 20}
+
+// importRaces parses every race file in dir
+func importRaces(dir string) ([]SRDRace, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing races: %v", err)
+	}
+
+	var races []SRDRace
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		parsed, err := parseRace(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		races = append(races, parsed...)
+	}
+	return races, issues, nil
+}
+
+// parseRace reads the "***Trait***. text" paragraphs of a race and of each
+// "## Subrace" section
+func parseRace(text string) ([]SRDRace, error) {
+	paragraphs := wikiParagraphs(text)
+	if len(paragraphs) == 0 || !strings.HasPrefix(paragraphs[0], "# ") {
+		return nil, fmt.Errorf("missing race heading")
+	}
+	name := headingText(paragraphs[0])
+
+	// sections[0] is the race, the rest its subraces
+	sections := []SRDRace{{Name: name, Race: name}}
+	options := false // inside a "choose one of the following options:" list
+	for _, paragraph := range paragraphs[1:] {
+		current := &sections[len(sections)-1]
+		switch {
+		case strings.HasPrefix(paragraph, "## "):
+			sections = append(sections, SRDRace{Name: headingText(paragraph), Race: name})
+			options = false
+			continue
+		case strings.HasPrefix(paragraph, "#"), strings.HasPrefix(paragraph, "|"), strings.HasPrefix(paragraph, "**Table- "):
+			continue
+		}
+
+		match := featurePattern.FindStringSubmatch(paragraph)
+		last := len(current.Traits) - 1
+		if match == nil || options {
+			// More of the previous trait; introductions come before any
+			if last >= 0 {
+				current.Traits[last].Description = joinParagraphs(current.Traits[last].Description, paragraph)
+				options = options || strings.HasSuffix(paragraph, ":")
+			}
+			continue
+		}
+		parseRaceTrait(current, strings.TrimSpace(match[1]), strings.TrimSpace(match[2]))
+	}
+
+	race := sections[0]
+	if race.Speed == 0 || race.Size == "" {
+		return nil, fmt.Errorf("missing size or speed")
+	}
+	if len(sections) == 1 {
+		return []SRDRace{race}, nil
+	}
+	var races []SRDRace
+	for _, subrace := range sections[1:] {
+		races = append(races, mergeSubrace(race, subrace))
+	}
+	return races, nil
+}
+
+// parseRaceTrait records one trait, reading its grants
+func parseRaceTrait(race *SRDRace, name, text string) {
+	switch name {
+	case "Age", "Alignment":
+		return
+	case "Ability Score Increase":
+		if race.AbilityIncreases == nil {
+			race.AbilityIncreases = make(map[string]int)
+		}
+		for _, match := range raceIncreasePattern.FindAllStringSubmatch(text, -1) {
+			race.AbilityIncreases[match[1]], _ = strconv.Atoi(match[2])
+		}
+		if match := raceAllIncreasePattern.FindStringSubmatch(text); match != nil {
+			for _, ability := range abilityNames {
+				race.AbilityIncreases[ability], _ = strconv.Atoi(match[1])
+			}
+		}
+		if match := raceChoicePattern.FindStringSubmatch(text); match != nil {
+			race.AbilityChoices += numberWords[match[1]]
+		}
+		return
+	case "Size":
+		if match := raceSizePattern.FindStringSubmatch(text); match != nil {
+			race.Size = match[1]
+		}
+		return
+	case "Speed":
+		if match := raceSpeedPattern.FindStringSubmatch(text); match != nil {
+			race.Speed, _ = strconv.Atoi(match[1])
+		}
+		return
+	case "Languages", "Extra Language":
+		if match := raceLanguagesPattern.FindStringSubmatch(text); match != nil {
+			for _, language := range splitList(match[1]) {
+				if strings.Contains(language, "of your choice") {
+					race.LanguageChoices += numberWords[strings.Fields(language)[0]]
+				} else {
+					race.Languages = append(race.Languages, language)
+				}
+			}
+		}
+		return
+	}
+
+	race.Traits = append(race.Traits, Trait{Name: name, Description: text})
+	for _, match := range raceSkillPattern.FindAllStringSubmatch(text, -1) {
+		race.Skills = append(race.Skills, match[1])
+	}
+	if match := raceSkillChoicePattern.FindStringSubmatch(text); match != nil {
+		race.SkillChoices += numberWords[match[1]]
+	}
+	if match := raceToolPattern.FindStringSubmatch(text); match != nil && !strings.Contains(match[1], "of your choice") {
+		for _, proficiency := range splitList(match[1]) {
+			race.Proficiencies = append(race.Proficiencies, proficiencyName(proficiency))
+		}
+	}
+	if match := raceToolChoicePattern.FindStringSubmatch(text); match != nil {
+		race.ToolChoices++
+		for _, tool := range splitList(strings.ReplaceAll(match[1], ", or ", ", ")) {
+			race.ToolOptions = append(race.ToolOptions, proficiencyName(tool))
+		}
+	}
+	if match := raceCantripPattern.FindStringSubmatch(text); match != nil {
+		race.CantripChoices += numberWords[match[1]]
+		race.CantripList = capitalize(match[2])
+	}
+}
+
+// proficiencyName writes a weapon or tool the way the equipment catalog
+// does: "artisan's tools (tinker's tools)" is "Tinker's tools"
+func proficiencyName(text string) string {
+	if match := artisanToolsPattern.FindStringSubmatch(text); match != nil {
+		text = match[1]
+	}
+	return capitalize(text)
+}
+
+// mergeSubrace adds a subrace's traits and grants to a copy of its race
+func mergeSubrace(race, subrace SRDRace) SRDRace {
+	merged := race
+	merged.Name = subrace.Name
+	merged.Traits = append(append([]Trait(nil), race.Traits...), subrace.Traits...)
+	merged.AbilityIncreases = make(map[string]int)
+	for _, increases := range []map[string]int{race.AbilityIncreases, subrace.AbilityIncreases} {
+		for ability, increase := range increases {
+			merged.AbilityIncreases[ability] += increase
+		}
+	}
+	merged.AbilityChoices += subrace.AbilityChoices
+	merged.Skills = append(append([]string(nil), race.Skills...), subrace.Skills...)
+	merged.SkillChoices += subrace.SkillChoices
+	merged.Proficiencies = append(append([]string(nil), race.Proficiencies...), subrace.Proficiencies...)
+	merged.ToolChoices += subrace.ToolChoices
+	merged.ToolOptions = append(append([]string(nil), race.ToolOptions...), subrace.ToolOptions...)
+	merged.Languages = append(append([]string(nil), race.Languages...), subrace.Languages...)
+	merged.LanguageChoices += subrace.LanguageChoices
+	merged.CantripChoices += subrace.CantripChoices
+	if subrace.CantripList != "" {
+		merged.CantripList = subrace.CantripList
+	}
+	return merged
+}
+
+// splitList splits "Common, Elvish, and one extra language" into its items
+func splitList(text string) []string {
+	text = strings.ReplaceAll(text, ", and ", ", ")
+	text = strings.ReplaceAll(text, " and ", ", ")
+	var items []string
+	for _, item := range strings.Split(text, ", ") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// summary is the race's ability increases, size and speed for the picker:
+// "Con +2, Wis +1, Medium, 25 ft."
+func (r SRDRace) summary() string {
+	var parts []string
+	for _, ability := range abilityNames {
+		if increase := r.AbilityIncreases[ability]; increase != 0 {
+			parts = append(parts, fmt.Sprintf("%s %+d", ability[:3], increase))
+		}
+	}
+	if r.AbilityChoices > 0 {
+		parts = append(parts, fmt.Sprintf("%d × +1 of choice", r.AbilityChoices))
+	}
+	return strings.Join(append(parts, r.Size, fmt.Sprintf("%d ft.", r.Speed)), ", ")
+}
+
+// race finds a catalog race by name
+func (d SRDData) race(name string) (SRDRace, bool) {
+	for _, race := range d.Races {
+		if strings.EqualFold(race.Name, name) {
+			return race, true
+		}
+	}
+	return SRDRace{}, false
+}
+
+// openRacePicker lets the user choose an SRD race. The old race's grants
+// are taken back before the new race's are applied
+func (m *Model) openRacePicker() {
+	if len(m.srdData.Races) == 0 {
+		m.message = "No SRD races loaded"
+		return
+	}
+
+	var options []string
+	for _, race := range m.srdData.Races {
+		options = append(options, fmt.Sprintf("%s (%s)", race.Name, race.summary()))
+	}
+	m.picker = &catalogPicker{
+		title:   "Choose Race",
+		options: options,
+		choose: func(m *Model, index int) {
+			race := m.srdData.Races[index]
+			m.character.Race = race.Name
+			m.character.Size = race.Size
+			m.character.Speed = race.Speed
+			m.grantFrom("race", race.Grants)
+
+			m.message = fmt.Sprintf("Race: %s", race.Name)
+			if choices := race.choices(); choices != "" {
+				m.message += " (choose " + choices + ")"
+			}
+		},
+	}
+}
+
+// racesDir is the 5e races folder under the Rules folder
+func racesDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Races")
+}
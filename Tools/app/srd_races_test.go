@@ -0,0 +1,141 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const dwarfFile = `# Dwarf
+
+### Dwarf Traits
+
+Your dwarf character has an assortment of inborn abilities.
+
+***Ability Score Increase***. Your Constitution score increases by 2.
+
+***Age***. Dwarves mature at the same rate as humans.
+
+***Size***. Dwarves stand between 4 and 5 feet tall. Your size is Medium.
+
+***Speed***. Your base walking speed is 25 feet.
+
+***Dwarven Combat Training***. You have proficiency with the battleaxe, handaxe, light hammer, and warhammer.
+
+***Tool Proficiency***. You gain proficiency with the artisan's tools of your choice: smith's tools, brewer's supplies, or mason's tools.
+
+***Languages***. You can speak, read, and write Common and Dwarvish. Dwarvish is full of hard consonants.
+
+## Hill Dwarf
+
+As a hill dwarf, you have keen senses.
+
+***Ability Score Increase***. Your Wisdom score increases by 1.
+
+***Dwarven Toughness***. Your hit point maximum increases by 1.
+
+## Mountain Dwarf
+
+***Ability Score Increase***. Your Strength score increases by 2.
+`
+
+func TestParseRace(t *testing.T) {
+	races, err := parseRace(dwarfFile)
+	if err != nil {
+		t.Fatalf("parseRace: %v", err)
+	}
+	if len(races) != 2 {
+		t.Fatalf("parseRace gave %d races, want 2", len(races))
+	}
+
+	hill := races[0]
+	if hill.Name != "Hill Dwarf" || hill.Race != "Dwarf" || hill.Size != "Medium" || hill.Speed != 25 {
+		t.Errorf("Hill Dwarf is %q (%q), %q, %d ft.", hill.Name, hill.Race, hill.Size, hill.Speed)
+	}
+	wantIncreases := map[string]int{"Constitution": 2, "Wisdom": 1}
+	if !reflect.DeepEqual(hill.AbilityIncreases, wantIncreases) {
+		t.Errorf("Hill Dwarf increases = %v, want %v", hill.AbilityIncreases, wantIncreases)
+	}
+	wantProficiencies := []string{"Battleaxe", "Handaxe", "Light hammer", "Warhammer"}
+	if !reflect.DeepEqual(hill.Proficiencies, wantProficiencies) {
+		t.Errorf("Hill Dwarf proficiencies = %q, want %q", hill.Proficiencies, wantProficiencies)
+	}
+	if want := []string{"Smith's tools", "Brewer's supplies", "Mason's tools"}; hill.ToolChoices != 1 || !reflect.DeepEqual(hill.ToolOptions, want) {
+		t.Errorf("Hill Dwarf tool choices = %d of %q, want 1 of %q", hill.ToolChoices, hill.ToolOptions, want)
+	}
+	if want := []string{"Common", "Dwarvish"}; !reflect.DeepEqual(hill.Languages, want) {
+		t.Errorf("Hill Dwarf languages = %q, want %q", hill.Languages, want)
+	}
+	var traits []string
+	for _, trait := range hill.Traits {
+		traits = append(traits, trait.Name)
+	}
+	if want := []string{"Dwarven Combat Training", "Tool Proficiency", "Dwarven Toughness"}; !reflect.DeepEqual(traits, want) {
+		t.Errorf("Hill Dwarf traits = %q, want %q", traits, want)
+	}
+
+	if mountain := races[1]; mountain.AbilityIncreases["Strength"] != 2 || mountain.AbilityIncreases["Wisdom"] != 0 {
+		t.Errorf("Mountain Dwarf increases = %v", mountain.AbilityIncreases)
+	}
+}
+
+func TestParseRaceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"no heading", "***Speed***. Your base walking speed is 30 feet."},
+		{"no speed", "# Blob\n\n***Size***. Your size is Small."},
+	}
+	for _, tt := range tests {
+		if _, err := parseRace(tt.text); err == nil {
+			t.Errorf("%s: parseRace succeeded", tt.name)
+		}
+	}
+}
+
+func TestParseRaceTrait(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Grants
+	}{
+		{"Ability Score Increase", "Your ability scores each increase by 1.",
+			Grants{AbilityIncreases: map[string]int{"Strength": 1, "Dexterity": 1, "Constitution": 1, "Intelligence": 1, "Wisdom": 1, "Charisma": 1}}},
+		{"Ability Score Increase", "Your Charisma score increases by 2, and two other ability scores of your choice increase by 1.",
+			Grants{AbilityIncreases: map[string]int{"Charisma": 2}, AbilityChoices: 2}},
+		{"Languages", "You can speak, read, and write Common, Elvish, and one extra language of your choice.",
+			Grants{Languages: []string{"Common", "Elvish"}, LanguageChoices: 1}},
+		{"Menacing", "You gain proficiency in the Intimidation skill.",
+			Grants{Skills: []string{"Intimidation"}}},
+		{"Skill Versatility", "You gain proficiency in two skills of your choice.",
+			Grants{SkillChoices: 2}},
+		{"Tinker", "You have proficiency with artisan's tools (tinker's tools). Using those tools, you can build.",
+			Grants{Proficiencies: []string{"Tinker's tools"}}},
+		{"Cantrip", "You know one cantrip of your choice from the wizard spell list. Intelligence is your spellcasting ability for it.",
+			Grants{CantripChoices: 1, CantripList: "Wizard"}},
+	}
+	for _, tt := range tests {
+		var race SRDRace
+		parseRaceTrait(&race, tt.name, tt.text)
+		if !reflect.DeepEqual(race.Grants, tt.want) {
+			t.Errorf("%s %q: grants = %+v, want %+v", tt.name, tt.text, race.Grants, tt.want)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Common and Dwarvish", []string{"Common", "Dwarvish"}},
+		{"Common, Elvish, and one extra language", []string{"Common", "Elvish", "one extra language"}},
+		{"Insight", []string{"Insight"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitList(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
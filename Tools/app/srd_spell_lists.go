@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Cross-reference of "## Spell Lists.md" (Bard Spells > Cantrips, 1st Level, ...)
+// against the imported spells
+
+// parseSpellLists reads the class spell lists into class name -> spell names
+func parseSpellLists(text string) (map[string][]string, []string) {
+	lists := make(map[string][]string)
+	var classes []string
+
+	class := ""
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "## "):
+			class = strings.TrimSuffix(headingText(line), " Spells")
+			classes = append(classes, class)
+		case strings.HasPrefix(line, "- ") && class != "":
+			lists[class] = append(lists[class], strings.TrimSpace(strings.TrimPrefix(line, "- ")))
+		}
+	}
+	return lists, classes
+}
+
+// assignSpellClasses fills SRDSpell.Classes from the class spell lists and
+// reports every listed spell that has no spell file
+func assignSpellClasses(spells []SRDSpell, listFile string) ([]ImportIssue, error) {
+	data, err := os.ReadFile(listFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spell lists: %v", err)
+	}
+	lists, classes := parseSpellLists(string(data))
+
+	byName := make(map[string]*SRDSpell)
+	for i := range spells {
+		byName[spellKey(spells[i].Name)] = &spells[i]
+	}
+
+	var issues []ImportIssue
+	for _, class := range classes {
+		for _, name := range lists[class] {
+			spell, ok := byName[spellKey(name)]
+			if !ok {
+				issues = append(issues, ImportIssue{
+					File:   listFile,
+					Reason: fmt.Sprintf("%s spell %q has no spell file", class, name),
+				})
+				continue
+			}
+			if spell.Classes == "" {
+				spell.Classes = class
+			} else {
+				spell.Classes += ", " + class
+			}
+		}
+	}
+	return issues, nil
+}
+
+// spellKey normalises spell names so "Blindness/Deafness" in a list matches
+// however the spell file spells it
+func spellKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("’", "'", "/", " ", "-", " ").Replace(name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// spellListsFile is the class spell list page under the Rules folder
+func spellListsFile(rulesDir string) string {
+	return filepath.Join(spellsDir(rulesDir), "## Spell Lists.md")
+}
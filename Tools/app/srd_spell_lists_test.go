@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAssignSpellClasses(t *testing.T) {
+	lists := "# Spell Lists\n\n## Bard Spells\n\n#### Cantrips (0 Level)\n\n- Light\n- Vicious Mockery\n\n#### 2nd Level\n\n- Blindness/Deafness\n\n## Cleric Spells\n\n#### Cantrips (0 Level)\n\n- Light\n"
+	file := filepath.Join(t.TempDir(), "## Spell Lists.md")
+	if err := os.WriteFile(file, []byte(lists), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spells := []SRDSpell{{Name: "Light"}, {Name: "Blindness-Deafness"}, {Name: "Wish"}}
+	issues, err := assignSpellClasses(spells, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spell   string
+		classes string
+	}{
+		{"Light", "Bard, Cleric"},
+		{"Blindness-Deafness", "Bard"},
+		{"Wish", ""},
+	}
+	for i, tt := range tests {
+		if spells[i].Classes != tt.classes {
+			t.Errorf("%s: classes = %q, want %q", tt.spell, spells[i].Classes, tt.classes)
+		}
+	}
+	// Vicious Mockery is listed but has no spell file
+	if len(issues) != 1 {
+		t.Errorf("got %d issues, want 1: %v", len(issues), issues)
+	}
+}
+
+func TestSpellKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Blindness/Deafness", "blindness deafness"},
+		{"Blindness-Deafness", "blindness deafness"},
+		{" Tasha’s  Hideous Laughter", "tasha's hideous laughter"},
+	}
+	for _, tt := range tests {
+		if got := spellKey(tt.name); got != tt.want {
+			t.Errorf("spellKey(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
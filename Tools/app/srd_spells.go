@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Importer for the 5e spell files in Rules/DND.SRD.Wiki/Spells
+
+var (
+	spellLevelPattern   = regexp.MustCompile(`^\*(\d+(?:st|nd|rd|th))-level (\w+)( \(ritual\))?\*$`)
+	spellCantripPattern = regexp.MustCompile(`^\*(\w+) cantrip( \(ritual\))?\*$`)
+)
+
+const higherLevelMarker = "***At Higher Levels***."
+
+// importSpells parses every spell file in dir. Files that don't follow the
+// wiki layout are skipped and reported instead of failing the whole import
+func importSpells(dir string) ([]SRDSpell, []ImportIssue, error) {
+	files, err := wikiFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing spells: %v", err)
+	}
+
+	var spells []SRDSpell
+	var issues []ImportIssue
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		spell, err := parseSpell(string(data))
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: err.Error()})
+			continue
+		}
+		spells = append(spells, spell)
+	}
+	return spells, issues, nil
+}
+
+// parseSpell reads a single spell in the "### Name", "*2nd-level evocation*",
+// **Casting Time**/**Range**/**Components**/**Duration** layout
+func parseSpell(text string) (SRDSpell, error) {
+	var spell SRDSpell
+
+	paragraphs := wikiParagraphs(text)
+	if len(paragraphs) < 2 || !strings.HasPrefix(paragraphs[0], "#") {
+		return spell, fmt.Errorf("missing spell heading")
+	}
+	spell.Name = headingText(paragraphs[0])
+
+	if match := spellLevelPattern.FindStringSubmatch(paragraphs[1]); match != nil {
+		level, err := parseOrdinal(match[1])
+		if err != nil {
+			return spell, fmt.Errorf("bad spell level %q", match[1])
+		}
+		spell.Level = level
+		spell.School = capitalize(match[2])
+		spell.Ritual = match[3] != ""
+	} else if match := spellCantripPattern.FindStringSubmatch(paragraphs[1]); match != nil {
+		spell.Level = 0
+		spell.School = capitalize(match[1])
+		spell.Ritual = match[2] != ""
+	} else {
+		return spell, fmt.Errorf("unrecognised level/school line %q", paragraphs[1])
+	}
+
+	// The stat lines come first (a few files run two of them together without
+	// a blank line), the description is everything after them
+	rest := paragraphs[2:]
+	for len(rest) > 0 && isSpellStatBlock(rest[0]) {
+		for _, line := range strings.Split(rest[0], "\n") {
+			label, value, _ := boldField(line)
+			switch label {
+			case "Casting Time":
+				spell.CastingTime = value
+			case "Range":
+				spell.Range = value
+			case "Components", "Component":
+				spell.Components = value
+			case "Duration":
+				spell.Duration = value
+			}
+		}
+		rest = rest[1:]
+	}
+
+	var description []string
+	for i, paragraph := range rest {
+		if strings.HasPrefix(paragraph, higherLevelMarker) {
+			higher := append([]string{strings.TrimSpace(strings.TrimPrefix(paragraph, higherLevelMarker))}, rest[i+1:]...)
+			spell.HigherLevel = strings.Join(higher, "\n\n")
+			break
+		}
+		description = append(description, paragraph)
+	}
+	spell.Description = strings.Join(description, "\n\n")
+
+	var missing []string
+	if spell.CastingTime == "" {
+		missing = append(missing, "casting time")
+	}
+	if spell.Range == "" {
+		missing = append(missing, "range")
+	}
+	if spell.Components == "" {
+		missing = append(missing, "components")
+	}
+	if spell.Duration == "" {
+		missing = append(missing, "duration")
+	}
+	if len(missing) > 0 {
+		return spell, fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	return spell, nil
+}
+
+func isSpellStatBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		label, _, ok := boldField(line)
+		if !ok || strings.HasPrefix(label, "Table") {
+			return false
+		}
+	}
+	return true
+}
+
+// spellsDir is where the 5e spell files live under the Rules folder
+func spellsDir(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Spells")
+}
@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestParseSpell(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want SRDSpell
+		err  bool
+	}{
+		{
+			name: "leveled spell with higher levels",
+			text: "### Cure Wounds\n\n*1st-level evocation*\n\n**Casting Time:** 1 action\n\n**Range:** Touch\n\n**Components:** V, S\n\n**Duration:** Instantaneous\n\nA creature you touch regains hit points.\n\n***At Higher Levels***. The healing increases by 1d8.",
+			want: SRDSpell{
+				Name:        "Cure Wounds",
+				Level:       1,
+				School:      "Evocation",
+				CastingTime: "1 action",
+				Range:       "Touch",
+				Components:  "V, S",
+				Duration:    "Instantaneous",
+				Description: "A creature you touch regains hit points.",
+				HigherLevel: "The healing increases by 1d8.",
+			},
+		},
+		{
+			name: "cantrip",
+			text: "### Light\n\n*Evocation cantrip*\n\n**Casting Time:** 1 action\n\n**Range:** Touch\n\n**Components:** V, M (a firefly)\n\n**Duration:** 1 hour\n\nYou touch one object.",
+			want: SRDSpell{
+				Name:        "Light",
+				School:      "Evocation",
+				CastingTime: "1 action",
+				Range:       "Touch",
+				Components:  "V, M (a firefly)",
+				Duration:    "1 hour",
+				Description: "You touch one object.",
+			},
+		},
+		{
+			name: "ritual with stat lines run together",
+			text: "### Alarm\n\n*1st-level abjuration (ritual)*\n\n**Casting Time:** 1 minute\n**Range:** 30 feet\n**Components:** V, S, M\n**Duration:** 8 hours\n\nYou set an alarm.",
+			want: SRDSpell{
+				Name:        "Alarm",
+				Level:       1,
+				School:      "Abjuration",
+				CastingTime: "1 minute",
+				Range:       "30 feet",
+				Components:  "V, S, M",
+				Duration:    "8 hours",
+				Description: "You set an alarm.",
+				Ritual:      true,
+			},
+		},
+		{
+			name: "missing heading",
+			text: "*1st-level evocation*\n\n**Casting Time:** 1 action",
+			err:  true,
+		},
+		{
+			name: "unknown level line",
+			text: "### Odd\n\nNot a level line\n\n**Casting Time:** 1 action",
+			err:  true,
+		},
+		{
+			name: "missing stat lines",
+			text: "### Half\n\n*2nd-level illusion*\n\n**Casting Time:** 1 action\n\nSome text.",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseSpell(tt.text)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.err)
+			continue
+		}
+		if !tt.err && got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 5e weapons from the table in Rules/DND.SRD.Wiki/Equipment/Weapons.md
+
+// WeaponProperties is the parsed form of a weapon's "Properties" column,
+// e.g. "Ammunition (range 80/320), loading, two-handed"
+type WeaponProperties struct {
+	Ammunition  bool   `json:"ammunition"`
+	Finesse     bool   `json:"finesse"`
+	Heavy       bool   `json:"heavy"`
+	Light       bool   `json:"light"`
+	Loading     bool   `json:"loading"`
+	Reach       bool   `json:"reach"`
+	Special     bool   `json:"special"`
+	Thrown      bool   `json:"thrown"`
+	TwoHanded   bool   `json:"two_handed"`
+	Versatile   string `json:"versatile"`    // two-handed damage dice, e.g. "1d10"
+	NormalRange int    `json:"normal_range"` // feet, for ammunition and thrown weapons
+	LongRange   int    `json:"long_range"`
+}
+
+var (
+	weaponRangePattern     = regexp.MustCompile(`\(range (\d+)/(\d+)\)`)
+	weaponVersatilePattern = regexp.MustCompile(`^versatile \((\w+)\)$`)
+)
+
+// importWeapons reads the Weapons table
+func importWeapons(file string) ([]SRDWeapon, []ImportIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading weapons: %v", err)
+	}
+
+	rows, err := wikiTable(string(data), "Weapons")
+	if err != nil {
+		return nil, nil, err
+	}
+	features := wikiFeatures(string(data))
+
+	var weapons []SRDWeapon
+	var issues []ImportIssue
+	category, ranged := "", false
+	for _, row := range rows {
+		if len(row) < 5 {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("short row %q", strings.Join(row, " | "))})
+			continue
+		}
+
+		// Group rows such as "**Martial Ranged Weapons**"
+		if strings.HasPrefix(row[0], "**") {
+			group := strings.Fields(strings.ToLower(strings.Trim(row[0], "*")))
+			if len(group) < 2 {
+				issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("unrecognised weapon group %q", row[0])})
+				continue
+			}
+			category, ranged = group[0], group[1] == "ranged"
+			continue
+		}
+
+		properties, err := parseWeaponProperties(row[4])
+		if err != nil {
+			issues = append(issues, ImportIssue{File: file, Reason: fmt.Sprintf("%s: %v", row[0], err)})
+			continue
+		}
+
+		weapon := SRDWeapon{
+			Name:        row[0],
+			Cost:        dashToEmpty(row[1]),
+			Damage:      dashToEmpty(row[2]),
+			Weight:      dashToEmpty(row[3]),
+			Properties:  properties,
+			Category:    category,
+			Ranged:      ranged,
+			Description: features[strings.ToLower(row[0])],
+		}
+		if fields := strings.Fields(weapon.Damage); len(fields) == 2 {
+			weapon.DamageDice, weapon.DamageType = fields[0], fields[1]
+		}
+		weapons = append(weapons, weapon)
+	}
+	return weapons, issues, nil
+}
+
+// parseWeaponProperties reads the Properties column
+func parseWeaponProperties(text string) (WeaponProperties, error) {
+	var properties WeaponProperties
+	if dashToEmpty(text) == "" {
+		return properties, nil
+	}
+
+	for _, part := range splitTopLevel(text) {
+		property := strings.ToLower(strings.TrimSpace(part))
+		if match := weaponRangePattern.FindStringSubmatch(property); match != nil {
+			properties.NormalRange, _ = strconv.Atoi(match[1])
+			properties.LongRange, _ = strconv.Atoi(match[2])
+			property = strings.TrimSpace(weaponRangePattern.ReplaceAllString(property, ""))
+		}
+
+		switch property {
+		case "ammunition":
+			properties.Ammunition = true
+		case "finesse":
+			properties.Finesse = true
+		case "heavy":
+			properties.Heavy = true
+		case "light":
+			properties.Light = true
+		case "loading":
+			properties.Loading = true
+		case "reach":
+			properties.Reach = true
+		case "special":
+			properties.Special = true
+		case "thrown":
+			properties.Thrown = true
+		case "two-handed":
+			properties.TwoHanded = true
+		default:
+			match := weaponVersatilePattern.FindStringSubmatch(property)
+			if match == nil {
+				return properties, fmt.Errorf("unknown weapon property %q", part)
+			}
+			properties.Versatile = match[1]
+		}
+	}
+	return properties, nil
+}
+
+// String renders the properties the way the Weapons table writes them
+func (p WeaponProperties) String() string {
+	rangeText := ""
+	if p.NormalRange > 0 {
+		rangeText = fmt.Sprintf(" (range %d/%d)", p.NormalRange, p.LongRange)
+	}
+
+	var parts []string
+	if p.Ammunition {
+		parts = append(parts, "ammunition"+rangeText)
+	}
+	if p.Finesse {
+		parts = append(parts, "finesse")
+	}
+	if p.Heavy {
+		parts = append(parts, "heavy")
+	}
+	if p.Light {
+		parts = append(parts, "light")
+	}
+	if p.Loading {
+		parts = append(parts, "loading")
+	}
+	if p.Reach {
+		parts = append(parts, "reach")
+	}
+	if p.Special {
+		parts = append(parts, "special")
+	}
+	if p.Thrown {
+		parts = append(parts, "thrown"+rangeText)
+	}
+	if p.TwoHanded {
+		parts = append(parts, "two-handed")
+	}
+	if p.Versatile != "" {
+		parts = append(parts, fmt.Sprintf("versatile (%s)", p.Versatile))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return capitalize(strings.Join(parts, ", "))
+}
+
+// dashToEmpty turns the tables' "-" placeholder into an empty string
+func dashToEmpty(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "-" || s == "—" {
+		return ""
+	}
+	return s
+}
+
+// weaponsFile is the 5e weapons page under the Rules folder
+func weaponsFile(rulesDir string) string {
+	return filepath.Join(rulesDir, "DND.SRD.Wiki", "Equipment", "Weapons.md")
+}
+
+// weaponFromSRD copies a catalog weapon onto the character sheet
+func weaponFromSRD(w SRDWeapon) Weapon {
+	return Weapon{
+		Name:        w.Name,
+		Description: w.Description,
+		Damage:      w.Damage,
+		Properties:  w.Properties.String(),
+		Weight:      w.Weight,
+		Cost:        w.Cost,
+	}
+}
+
+// openWeaponPicker lets the user add any weapon from the SRD catalog
+func (m *Model) openWeaponPicker() {
+	if len(m.srdData.Weapons) == 0 {
+		m.message = "No SRD weapons loaded"
+		return
+	}
+
+	var options []string
+	for _, w := range m.srdData.Weapons {
+		options = append(options, fmt.Sprintf("%s (%s %s, %s, %s) %s", w.Name, w.Category, map[bool]string{true: "ranged", false: "melee"}[w.Ranged], w.Damage, w.Cost, w.Properties))
+	}
+	m.picker = &catalogPicker{
+		title:   "Add Weapon",
+		options: options,
+		choose: func(m *Model, index int) {
+			weapon := weaponFromSRD(m.srdData.Weapons[index])
+			m.weapons = append(m.weapons, weapon)
+			m.message = fmt.Sprintf("Added %s to weapons", weapon.Name)
+		},
+	}
+}
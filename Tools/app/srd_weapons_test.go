@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestParseWeaponProperties(t *testing.T) {
+	tests := []struct {
+		text string
+		want WeaponProperties
+		err  bool
+	}{
+		{"-", WeaponProperties{}, false},
+		{"Finesse, light, thrown (range 20/60)", WeaponProperties{Finesse: true, Light: true, Thrown: true, NormalRange: 20, LongRange: 60}, false},
+		{"Ammunition (range 80/320), loading, two-handed", WeaponProperties{Ammunition: true, Loading: true, TwoHanded: true, NormalRange: 80, LongRange: 320}, false},
+		{"Versatile (1d10)", WeaponProperties{Versatile: "1d10"}, false},
+		{"Heavy, reach, special", WeaponProperties{Heavy: true, Reach: true, Special: true}, false},
+		{"Sharp", WeaponProperties{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWeaponProperties(tt.text)
+		if (err != nil) != tt.err {
+			t.Errorf("parseWeaponProperties(%q) error = %v, want error %v", tt.text, err, tt.err)
+			continue
+		}
+		if tt.err {
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseWeaponProperties(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+		// The properties render back the way the table writes them
+		if got.String() != tt.text {
+			t.Errorf("%+v.String() = %q, want %q", got, got.String(), tt.text)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Helpers shared by the Rules wiki importers
+
+// ImportIssue records a wiki file (or entry) an importer could not turn into a record
+type ImportIssue struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+func (i ImportIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.File, i.Reason)
+}
+
+// wikiFiles lists the markdown entry files in dir, skipping the "# Overview.md"
+// and "## Spell Lists.md" style index pages that sort to the top of each folder
+func wikiFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".md" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// wikiParagraphs splits a markdown document into blank-line separated blocks
+func wikiParagraphs(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var paragraphs []string
+	for _, block := range strings.Split(text, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block != "" {
+			paragraphs = append(paragraphs, block)
+		}
+	}
+	return paragraphs
+}
+
+var boldFieldPattern = regexp.MustCompile(`^\*\*([^*]+?):?\*\*:?\s*(.*)$`)
+
+// featurePattern matches "***Fire Breath***. text" and "**Detect.** text" paragraphs
+var featurePattern = regexp.MustCompile(`^\*{2,3}([^*]+?)(?:\.\*{2,3}|\*{2,3}\.)\s*((?s).*)$`)
+
+// wikiFeatures collects the "***Name***. text" paragraphs of a document by
+// lower-cased name
+func wikiFeatures(text string) map[string]string {
+	features := make(map[string]string)
+	for _, paragraph := range wikiParagraphs(text) {
+		if match := featurePattern.FindStringSubmatch(paragraph); match != nil {
+			features[strings.ToLower(strings.TrimSpace(match[1]))] = strings.TrimSpace(match[2])
+		}
+	}
+	return features
+}
+
+// boldField splits a "**Casting Time:** 1 action" line into its label and value
+func boldField(line string) (string, string, bool) {
+	match := boldFieldPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return "", "", false
+	}
+	return strings.TrimSuffix(strings.TrimSpace(match[1]), ":"), strings.TrimSpace(match[2]), true
+}
+
+// headingText strips the leading #'s from a markdown heading
+func headingText(line string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+}
+
+// parseOrdinal reads "3rd", "1st" or "9th" as a number
+func parseOrdinal(s string) (int, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		s = strings.TrimSuffix(s, suffix)
+	}
+	return strconv.Atoi(s)
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// wikiTable returns the body rows of the markdown table that follows the
+// "**Table- Name**" caption, skipping the header, the |---| rule and blank rows
+func wikiTable(text, caption string) ([][]string, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "**Table- "+caption+"**") {
+			return tableRows(lines[i+1:]), nil
+		}
+	}
+	return nil, fmt.Errorf("table %q not found", caption)
+}
+
+// wikiTableHeader is wikiTable with the header cells, for tables whose
+// columns vary from page to page
+func wikiTableHeader(text, caption string) ([]string, [][]string, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "**Table- "+caption+"**") {
+			continue
+		}
+		for _, next := range lines[i+1:] {
+			if next = strings.TrimSpace(next); next != "" {
+				if !strings.HasPrefix(next, "|") {
+					break
+				}
+				return tableCells(next), tableRows(lines[i+1:]), nil
+			}
+		}
+		return nil, nil, fmt.Errorf("no table under %q", caption)
+	}
+	return nil, nil, fmt.Errorf("table %q not found", caption)
+}
+
+// wikiHeadingTable returns the body rows of the first table under the
+// "## Heading" line, the way the OSE wiki lays out its tables
+func wikiHeadingTable(text, heading string) ([][]string, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") && headingText(line) == heading {
+			for j, next := range lines[i+1:] {
+				if strings.HasPrefix(strings.TrimSpace(next), "|") {
+					return tableRows(lines[i+1+j:]), nil
+				}
+				if strings.HasPrefix(next, "#") {
+					break
+				}
+			}
+			return nil, fmt.Errorf("no table under %q", heading)
+		}
+	}
+	return nil, fmt.Errorf("heading %q not found", heading)
+}
+
+// tableRows reads the table starting at (or after blank lines before) lines[0]
+func tableRows(lines []string) [][]string {
+	var rows [][]string
+	header := true
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" && len(rows) == 0 && header {
+			continue
+		}
+		if !strings.HasPrefix(line, "|") {
+			break
+		}
+		cells := tableCells(line)
+		if header {
+			// The first row is the header, the second the |---| rule
+			if strings.Trim(strings.Join(cells, ""), "-: ") == "" {
+				header = false
+			}
+			continue
+		}
+		if strings.Join(cells, "") == "" {
+			continue
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// tableCells splits a "| a | b |" markdown row into trimmed cells
+func tableCells(line string) []string {
+	cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}